@@ -8,7 +8,10 @@ import (
 )
 
 func main() {
-	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
+	// the input methods show their candidates list over the text fields
+	sdl.SetHint(sdl.HINT_IME_SHOW_UI, "1")
+	// the audio is started by the game, which stays silent if there is no audio device
+	if err := sdl.Init(sdl.INIT_EVERYTHING &^ sdl.INIT_AUDIO); err != nil {
 		log.Fatal(err)
 	}
 	defer sdl.Quit()
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/history"
+	"os"
+)
+
+/*
+Writes the history of the finished games as CSV or JSON, on the standard output
+or in a file.
+
+usage: historyexport [-path data/history.yml] [-format csv|json] [-o file]
+*/
+func main() {
+	historyPath := flag.String("path", config.HistoryFilePath, "history file")
+	format := flag.String("format", history.FormatCSV, "output format: csv or json")
+	outputPath := flag.String("o", "", "output file (default: standard output)")
+	flag.Parse()
+
+	// an invalid format doesn't leave an empty output file
+	if err := history.CheckFormat(*format); err != nil {
+		log.Fatalln(err)
+	}
+	records, err := history.Load(*historyPath)
+	if err != nil {
+		// the records that could be read are still exported
+		if records == nil {
+			log.Fatalln(err)
+		}
+		log.Println(err)
+	}
+	if *outputPath == "" {
+		if err := history.Export(os.Stdout, records, *format); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if err := exportFile(*outputPath, records, *format); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// Writes the records in the file, closed before returning (the write errors may show when closing)
+func exportFile(path string, records []history.Record, format string) error {
+	output, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("export history: %s", err)
+	}
+	err = history.Export(output, records, format)
+	if closeErr := output.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("export history: %s", closeErr)
+	}
+	return err
+}
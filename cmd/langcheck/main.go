@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/lang"
+	"os"
+)
+
+/*
+Reports the keys missing from each language file (they fall back to English),
+and the files that can't be loaded. Exits with 1 if anything was reported.
+
+usage: langcheck [-path data/lang/] [locale...]
+*/
+func main() {
+	cfg := config.DefaultConfig
+	if err := config.LoadConfig(config.ConfigFilePath, &cfg); err != nil {
+		log.Printf("%s (using the default languages path)\n", err)
+	}
+	langPath := flag.String("path", cfg.Window.LangPath, "directory of the language files")
+	flag.Parse()
+
+	locales := flag.Args()
+	if len(locales) == 0 {
+		locales = lang.List(*langPath)
+	}
+	if len(locales) == 0 {
+		log.Fatalf("no language file in %q\n", *langPath)
+	}
+	incomplete := false
+	for _, locale := range locales {
+		data, err := ioutil.ReadFile(lang.FilePath(*langPath, locale))
+		if err != nil {
+			fmt.Printf("%s: %s\n", locale, err)
+			incomplete = true
+			continue
+		}
+		if _, err := lang.Load(*langPath, locale); err != nil {
+			fmt.Printf("%s: %s\n", locale, err)
+			incomplete = true
+		}
+		missing, err := config.MissingLangKeys(data)
+		if err != nil {
+			fmt.Printf("%s: %s\n", locale, err)
+			incomplete = true
+			continue
+		}
+		if len(missing) == 0 {
+			fmt.Printf("%s: complete\n", locale)
+			continue
+		}
+		incomplete = true
+		fmt.Printf("%s: %d missing keys\n", locale, len(missing))
+		for _, key := range missing {
+			fmt.Printf("  %s\n", key)
+		}
+	}
+	if incomplete {
+		os.Exit(1)
+	}
+}
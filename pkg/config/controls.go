@@ -0,0 +1,349 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Keys handled by the scenes themselves, they can't be bound to an action
+const (
+	KeyBack           = sdl.K_ESCAPE // leaves the scene in every scene
+	KeyZoomInAlt      = sdl.K_KP_PLUS
+	KeyZoomOutAlt     = sdl.K_KP_MINUS
+	KeyMenuFullscreen = sdl.K_f // only in the main menu, the game's actions can use it
+)
+
+type BindingKind int
+
+const (
+	BindKey BindingKind = iota
+	BindMouseButton
+	BindWheel
+	BindGamepadButton
+	BindGamepadAxis // a stick or trigger pushed past the gamepad's threshold
+)
+
+// prefixes of the bindings' names that aren't keys
+const (
+	mousePrefix   = "mouse:"
+	wheelPrefix   = "wheel:"
+	gamepadPrefix = "pad:"
+)
+
+/*
+Input triggering an action, written in the config as:
+  - a key name with optional modifiers ("Space", "Shift+Tab", "Ctrl+Z")
+  - "mouse:" and a button (left, middle, right, x1, x2)
+  - "wheel:up" or "wheel:down"
+  - "pad:" and a game controller button (a, b, x, y, back, start, leftshoulder, dpup...)
+  - "pad:" and a game controller axis with its direction (leftx-, lefty+, righttrigger+...)
+*/
+type Binding struct {
+	Kind      BindingKind
+	Key       sdl.Keycode
+	Mod       uint16 // KMOD_SHIFT, KMOD_CTRL, KMOD_ALT and KMOD_GUI, both sides
+	Button    uint8  // mouse or game controller button, game controller axis
+	Direction int32  // wheel 1 up, -1 down, axis 1 positive, -1 negative
+}
+
+var modifierNames = []struct {
+	name string
+	mod  uint16
+}{
+	{"Ctrl", sdl.KMOD_CTRL},
+	{"Shift", sdl.KMOD_SHIFT},
+	{"Alt", sdl.KMOD_ALT},
+	{"Gui", sdl.KMOD_GUI},
+}
+
+var mouseButtonNames = []struct {
+	name   string
+	button uint8
+}{
+	{"left", sdl.BUTTON_LEFT},
+	{"middle", sdl.BUTTON_MIDDLE},
+	{"right", sdl.BUTTON_RIGHT},
+	{"x1", sdl.BUTTON_X1},
+	{"x2", sdl.BUTTON_X2},
+}
+
+// Modifiers of a key event that can be part of a binding (both sides, without Num/Caps lock)
+func BindingMod(mod uint16) uint16 {
+	var result uint16
+	for _, m := range modifierNames {
+		if mod&m.mod != 0 {
+			result |= m.mod
+		}
+	}
+	return result
+}
+
+func ParseBinding(name string) (Binding, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case strings.HasPrefix(lower, mousePrefix):
+		for _, m := range mouseButtonNames {
+			if lower[len(mousePrefix):] == m.name {
+				return Binding{Kind: BindMouseButton, Button: m.button}, nil
+			}
+		}
+		return Binding{}, fmt.Errorf("unknown mouse button: %q", name)
+	case strings.HasPrefix(lower, wheelPrefix):
+		switch lower[len(wheelPrefix):] {
+		case "up":
+			return Binding{Kind: BindWheel, Direction: 1}, nil
+		case "down":
+			return Binding{Kind: BindWheel, Direction: -1}, nil
+		}
+		return Binding{}, fmt.Errorf("unknown wheel direction: %q", name)
+	case strings.HasPrefix(lower, gamepadPrefix):
+		pad := lower[len(gamepadPrefix):]
+		if strings.HasSuffix(pad, "+") || strings.HasSuffix(pad, "-") {
+			axis := sdl.GameControllerGetAxisFromString(pad[:len(pad)-1])
+			if axis == sdl.CONTROLLER_AXIS_INVALID {
+				return Binding{}, fmt.Errorf("unknown game controller axis: %q", name)
+			}
+			binding := Binding{Kind: BindGamepadAxis, Button: uint8(axis), Direction: 1}
+			if strings.HasSuffix(pad, "-") {
+				binding.Direction = -1
+			}
+			return binding, nil
+		}
+		button := sdl.GameControllerGetButtonFromString(pad)
+		if button == sdl.CONTROLLER_BUTTON_INVALID {
+			return Binding{}, fmt.Errorf("unknown game controller button: %q", name)
+		}
+		return Binding{Kind: BindGamepadButton, Button: uint8(button)}, nil
+	}
+	binding := Binding{Kind: BindKey}
+	key := strings.TrimSpace(name)
+	for found := true; found; {
+		found = false
+		for _, m := range modifierNames {
+			prefix := m.name + "+"
+			// "+" alone is the key
+			if len(key) > len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+				binding.Mod |= m.mod
+				key = key[len(prefix):]
+				found = true
+			}
+		}
+	}
+	binding.Key = sdl.GetKeyFromName(key)
+	if binding.Key == sdl.K_UNKNOWN {
+		return Binding{}, fmt.Errorf("unknown key name: %q", name)
+	}
+	return binding, nil
+}
+
+// Name of the binding, as written in the config
+func (b Binding) String() string {
+	switch b.Kind {
+	case BindMouseButton:
+		for _, m := range mouseButtonNames {
+			if m.button == b.Button {
+				return mousePrefix + m.name
+			}
+		}
+	case BindWheel:
+		if b.Direction > 0 {
+			return wheelPrefix + "up"
+		}
+		return wheelPrefix + "down"
+	case BindGamepadButton:
+		return gamepadPrefix + sdl.GameControllerGetStringForButton(sdl.GameControllerButton(b.Button))
+	case BindGamepadAxis:
+		direction := "+"
+		if b.Direction < 0 {
+			direction = "-"
+		}
+		return gamepadPrefix + sdl.GameControllerGetStringForAxis(sdl.GameControllerAxis(b.Button)) + direction
+	}
+	name := ""
+	for _, m := range modifierNames {
+		if b.Mod&m.mod != 0 {
+			name += m.name + "+"
+		}
+	}
+	return name + sdl.GetKeyName(b.Key)
+}
+
+// True for the inputs handled by the scenes themselves
+func (b Binding) IsReserved() bool {
+	switch b.Kind {
+	case BindKey:
+		return b.Key == KeyBack || b.Key == KeyZoomInAlt || b.Key == KeyZoomOutAlt
+	case BindMouseButton:
+		// clicks the buttons and drags the camera
+		return b.Button == sdl.BUTTON_LEFT
+	case BindGamepadButton:
+		// leaves the game like Escape
+		return b.Button == sdl.CONTROLLER_BUTTON_START
+	}
+	return false
+}
+
+// Bindings of an action, written as a single name or a list of names
+type KeyList []string
+
+func (l *KeyList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		*l = KeyList{name}
+		return nil
+	}
+	var names []string
+	if err := unmarshal(&names); err != nil {
+		return err
+	}
+	*l = names
+	return nil
+}
+
+type ControlNames struct {
+	KeyUp          KeyList `yaml:"up"`
+	KeyDown        KeyList `yaml:"down"`
+	KeyLeft        KeyList `yaml:"left"`
+	KeyRight       KeyList `yaml:"right"`
+	KeyFlag        KeyList `yaml:"flag"`
+	KeyOpen        KeyList `yaml:"open"`
+	KeyChord       KeyList `yaml:"chord"`
+	KeyReplay      KeyList `yaml:"replay"`
+	KeyZoomIn      KeyList `yaml:"zoom-in"`
+	KeyZoomOut     KeyList `yaml:"zoom-out"`
+	KeyRecenter    KeyList `yaml:"recenter"`
+	KeyRotateLeft  KeyList `yaml:"rotate-left"`
+	KeyRotateRight KeyList `yaml:"rotate-right"`
+	KeyMinimap     KeyList `yaml:"minimap"`
+}
+
+// Parsed ControlNames, set by Check
+type ControlBindings struct {
+	KeyUp          []Binding
+	KeyDown        []Binding
+	KeyLeft        []Binding
+	KeyRight       []Binding
+	KeyFlag        []Binding
+	KeyOpen        []Binding
+	KeyChord       []Binding
+	KeyReplay      []Binding
+	KeyZoomIn      []Binding
+	KeyZoomOut     []Binding
+	KeyRecenter    []Binding
+	KeyRotateLeft  []Binding
+	KeyRotateRight []Binding
+	KeyMinimap     []Binding
+}
+
+type GameControls struct {
+	Names    ControlNames    `yaml:"keys"`
+	Bindings ControlBindings `yaml:"-"`
+}
+
+// Bindings of an action of the controls, Id is its name in the config file
+type ControlKey struct {
+	Id           string
+	Names        *KeyList
+	DefaultNames KeyList
+	Bindings     *[]Binding
+}
+
+// Keys of the actions, in the order of the controls page
+func (c *GameControls) Keys() []ControlKey {
+	names := &c.Names
+	bindings := &c.Bindings
+	defaults := DefaultConfig.Controls.Names
+	return []ControlKey{
+		{"up", &names.KeyUp, defaults.KeyUp, &bindings.KeyUp},
+		{"down", &names.KeyDown, defaults.KeyDown, &bindings.KeyDown},
+		{"left", &names.KeyLeft, defaults.KeyLeft, &bindings.KeyLeft},
+		{"right", &names.KeyRight, defaults.KeyRight, &bindings.KeyRight},
+		{"open", &names.KeyOpen, defaults.KeyOpen, &bindings.KeyOpen},
+		{"flag", &names.KeyFlag, defaults.KeyFlag, &bindings.KeyFlag},
+		{"chord", &names.KeyChord, defaults.KeyChord, &bindings.KeyChord},
+		{"replay", &names.KeyReplay, defaults.KeyReplay, &bindings.KeyReplay},
+		{"zoom-in", &names.KeyZoomIn, defaults.KeyZoomIn, &bindings.KeyZoomIn},
+		{"zoom-out", &names.KeyZoomOut, defaults.KeyZoomOut, &bindings.KeyZoomOut},
+		{"recenter", &names.KeyRecenter, defaults.KeyRecenter, &bindings.KeyRecenter},
+		{"rotate-left", &names.KeyRotateLeft, defaults.KeyRotateLeft, &bindings.KeyRotateLeft},
+		{"rotate-right", &names.KeyRotateRight, defaults.KeyRotateRight, &bindings.KeyRotateRight},
+		{"minimap", &names.KeyMinimap, defaults.KeyMinimap, &bindings.KeyMinimap},
+	}
+}
+
+// Parses the names of the bindings, missing names are replaced by the default ones
+func (k *ControlKey) check() error {
+	*k.Bindings = make([]Binding, len(*k.Names))
+	for i, name := range *k.Names {
+		binding, err := ParseBinding(name)
+		if err != nil {
+			return fmt.Errorf("%s (%s)", err, k.Id)
+		}
+		if binding.IsReserved() {
+			return fmt.Errorf("reserved binding (%s): %q", k.Id, name)
+		}
+		(*k.Bindings)[i] = binding
+	}
+	return nil
+}
+
+/*
+Parses the bindings, a binding used by several actions is kept (see Conflicts).
+
+The actions missing from the config (added since it was written) get their default bindings,
+except the ones already used by the other actions
+*/
+func (c *GameControls) check() error {
+	used := map[Binding]bool{}
+	var missing []ControlKey
+	for _, key := range c.Keys() {
+		if len(*key.Names) == 0 {
+			missing = append(missing, key)
+			continue
+		}
+		if err := key.check(); err != nil {
+			return err
+		}
+		for _, binding := range *key.Bindings {
+			used[binding] = true
+		}
+	}
+	for _, key := range missing {
+		names := KeyList{}
+		for _, name := range key.DefaultNames {
+			if binding, err := ParseBinding(name); err == nil && used[binding] {
+				continue
+			}
+			names = append(names, name)
+		}
+		*key.Names = names
+		if err := key.check(); err != nil {
+			return err
+		}
+		for _, binding := range *key.Bindings {
+			used[binding] = true
+		}
+	}
+	return nil
+}
+
+/*
+Bindings used by several actions, with the ids of these actions in the order of Keys.
+The game gives such a binding to a single action, the controls page warns about them
+*/
+func (c *GameControls) Conflicts() map[Binding][]string {
+	used := map[Binding][]string{}
+	for _, key := range c.Keys() {
+		for _, binding := range *key.Bindings {
+			used[binding] = append(used[binding], key.Id)
+		}
+	}
+	for binding, ids := range used {
+		if len(ids) < 2 {
+			delete(used, binding)
+		}
+	}
+	return used
+}
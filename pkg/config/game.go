@@ -2,12 +2,30 @@ package config
 
 import (
 	"fmt"
-
-	"github.com/veandco/go-sdl2/sdl"
+	"strings"
+	"unicode/utf8"
 )
 
 const ConfigFilePath = "data/config.yml"
 
+// best times of the won games, by grid settings
+const BestsFilePath = "data/bests.yml"
+
+// record of every finished game, appended at the end of each one
+const HistoryFilePath = "data/history.yml"
+
+// game saved by the pause menu's "Save and quit to menu", removed once it is over or replaced
+const SaveFilePath = "data/save.yml"
+
+// directory of the replays saved from the results
+const ReplaysPath = "data/replays"
+
+// directory of the exported boards and histories
+const ExportsPath = "data/exports"
+
+// longest player name, in characters
+const MaxPlayerNameLength = 20
+
 type WindowConfig struct {
 	FPS           int32  `yaml:"fps"`
 	Width         int32  `yaml:"width"`
@@ -17,6 +35,42 @@ type WindowConfig struct {
 	IconFile      string `yaml:"icon_path"`
 	FontFile      string `yaml:"font_path"`
 	ResourcesPath string `yaml:"resources_path"`
+	// directory of the theme (in ThemesPath) used for the board, the menus and the font
+	Theme      string `yaml:"theme"`
+	ThemesPath string `yaml:"themes_path"`
+	// locale of the language file (in LangPath) of the texts
+	Language string `yaml:"language"`
+	LangPath string `yaml:"lang_path"`
+	// logs the average/max frame draw time every few seconds
+	FrameStats bool `yaml:"frame_stats"`
+	// draws every tile each frame instead of using the pre-rendered board
+	DisableBoardCache bool `yaml:"disable_board_cache"`
+	// animation between the scenes (one of Transitions) and its duration
+	Transition   string `yaml:"transition"`
+	TransitionMS int    `yaml:"transition_ms"`
+}
+
+// Volumes in percents, the sound effects and music volumes are scaled by the master volume
+type AudioConfig struct {
+	MasterVolume int  `yaml:"master_volume"`
+	SfxVolume    int  `yaml:"sfx_volume"`
+	MusicVolume  int  `yaml:"music_volume"`
+	Mute         bool `yaml:"mute"`
+}
+
+// animations between the scenes
+var Transitions = []string{"none", "fade", "slide"}
+
+// color modes of the board's numbers, highlight and minimap
+var ColorModes = []string{"normal", "red-green", "blue-yellow"}
+
+type AccessibilityConfig struct {
+	// colors safe for a color vision deficiency (one of ColorModes)
+	ColorMode string `yaml:"color_mode"`
+	// thick outline on the player's tile and larger numbers drawn with the font
+	HighContrast bool `yaml:"high_contrast"`
+	// numbers drawn with the font over the tiles' sprites
+	NumberGlyphs bool `yaml:"number_glyphs"`
 }
 
 type GameConfig struct {
@@ -25,72 +79,74 @@ type GameConfig struct {
 	BombPercent      int    `yaml:"bomb-percent"`
 	Lives            int    `yaml:"lives"`
 	WrongFlagPenalty bool   `yaml:"wrong_flag_penalty"`
+	PlayerName       string `yaml:"player_name"`
+	// opens the pause menu when the window loses the focus during a game
+	PauseOnFocusLoss bool `yaml:"pause_on_focus_loss"`
+	// a left click on a tile (without dragging the board) moves the player to it
+	ClickToMove bool `yaml:"click_to_move"`
 }
 
-type ControlNames struct {
-	KeyUp     string `yaml:"up"`
-	KeyDown   string `yaml:"down"`
-	KeyLeft   string `yaml:"left"`
-	KeyRight  string `yaml:"right"`
-	KeyFlag   string `yaml:"flag"`
-	KeyOpen   string `yaml:"open"`
-	KeyReplay string `yaml:"replay"`
-}
-
-type ControlCodes struct {
-	KeyUp     sdl.Keycode
-	KeyDown   sdl.Keycode
-	KeyLeft   sdl.Keycode
-	KeyRight  sdl.Keycode
-	KeyFlag   sdl.Keycode
-	KeyOpen   sdl.Keycode
-	KeyReplay sdl.Keycode
-}
-
-type GameControls struct {
-	Names ControlNames `yaml:"keys"`
-	Codes ControlCodes `yaml:"-"`
+type GamepadConfig struct {
+	// axis value (0 to 32767) past which a stick or trigger binding is pressed
+	AxisThreshold int16 `yaml:"axis_threshold"`
+	// rumbles the game controllers when a bomb explodes
+	Rumble bool `yaml:"rumble"`
+	// SDL game controller mappings (https://github.com/gabomdq/SDL_GameControllerDB) for the controllers SDL doesn't know
+	Mappings []string `yaml:"mappings"`
 }
 
 type Config struct {
-	Window   WindowConfig `yaml:"window"`
-	Game     GameConfig   `yaml:"game"`
-	Controls GameControls `yaml:"controls"`
+	Window        WindowConfig        `yaml:"window"`
+	Game          GameConfig          `yaml:"game"`
+	Audio         AudioConfig         `yaml:"audio"`
+	Accessibility AccessibilityConfig `yaml:"accessibility"`
+	Controls      GameControls        `yaml:"controls"`
+	Gamepad       GamepadConfig       `yaml:"gamepad"`
 }
 
 func (c *Config) Check() error {
 	if c.Window.FPS < 1 {
 		return fmt.Errorf("invalid FPS: got %d (expected FPS>0)", c.Window.FPS)
 	}
-	c.Controls.Codes.KeyUp = sdl.GetKeyFromName(c.Controls.Names.KeyUp)
-	if c.Controls.Codes.KeyUp == sdl.K_UNKNOWN {
-		return fmt.Errorf("unknown key name (KeyUp): %q", c.Controls.Names.KeyUp)
+	if c.Window.Transition == "" {
+		c.Window.Transition = DefaultConfig.Window.Transition
+	}
+	validTransition := false
+	for _, transition := range Transitions {
+		validTransition = validTransition || c.Window.Transition == transition
+	}
+	if !validTransition {
+		return fmt.Errorf("invalid transition: got %q (expected one of %v)", c.Window.Transition, Transitions)
 	}
-	c.Controls.Codes.KeyDown = sdl.GetKeyFromName(c.Controls.Names.KeyDown)
-	if c.Controls.Codes.KeyDown == sdl.K_UNKNOWN {
-		return fmt.Errorf("unknown key name (KeyDown): %q", c.Controls.Names.KeyDown)
+	if c.Window.TransitionMS < 0 {
+		return fmt.Errorf("invalid transition duration: got %d (expected transition_ms>=0)", c.Window.TransitionMS)
 	}
-	c.Controls.Codes.KeyLeft = sdl.GetKeyFromName(c.Controls.Names.KeyLeft)
-	if c.Controls.Codes.KeyLeft == sdl.K_UNKNOWN {
-		return fmt.Errorf("unknown key name (KeyLeft): %q", c.Controls.Names.KeyLeft)
+	for _, volume := range []*int{&c.Audio.MasterVolume, &c.Audio.SfxVolume, &c.Audio.MusicVolume} {
+		if *volume < 0 || *volume > 100 {
+			return fmt.Errorf("invalid volume: got %d (expected 0<=volume<=100)", *volume)
+		}
 	}
-	c.Controls.Codes.KeyRight = sdl.GetKeyFromName(c.Controls.Names.KeyRight)
-	if c.Controls.Codes.KeyRight == sdl.K_UNKNOWN {
-		return fmt.Errorf("unknown key name (KeyRight): %q", c.Controls.Names.KeyRight)
+	if c.Accessibility.ColorMode == "" {
+		c.Accessibility.ColorMode = ColorModes[0]
 	}
-	c.Controls.Codes.KeyFlag = sdl.GetKeyFromName(c.Controls.Names.KeyFlag)
-	if c.Controls.Codes.KeyFlag == sdl.K_UNKNOWN {
-		return fmt.Errorf("unknown key name (KeyFlag): %q", c.Controls.Names.KeyFlag)
+	validColorMode := false
+	for _, mode := range ColorModes {
+		validColorMode = validColorMode || c.Accessibility.ColorMode == mode
 	}
-	c.Controls.Codes.KeyOpen = sdl.GetKeyFromName(c.Controls.Names.KeyOpen)
-	if c.Controls.Codes.KeyOpen == sdl.K_UNKNOWN {
-		return fmt.Errorf("unknown key name (KeyOpen): %q", c.Controls.Names.KeyOpen)
+	if !validColorMode {
+		return fmt.Errorf("invalid color mode: got %q (expected one of %v)", c.Accessibility.ColorMode, ColorModes)
 	}
-	c.Controls.Codes.KeyReplay = sdl.GetKeyFromName(c.Controls.Names.KeyReplay)
-	if c.Controls.Codes.KeyReplay == sdl.K_UNKNOWN {
-		return fmt.Errorf("unknown key name (KeyOpen): %q", c.Controls.Names.KeyOpen)
+	c.Game.PlayerName = strings.TrimSpace(c.Game.PlayerName)
+	if c.Game.PlayerName == "" {
+		c.Game.PlayerName = DefaultConfig.Game.PlayerName
 	}
-	return nil
+	if length := utf8.RuneCountInString(c.Game.PlayerName); length > MaxPlayerNameLength {
+		return fmt.Errorf("invalid player name: got %d characters (expected at most %d)", length, MaxPlayerNameLength)
+	}
+	if c.Gamepad.AxisThreshold <= 0 {
+		return fmt.Errorf("invalid axis threshold: got %d (expected 0<threshold<=32767)", c.Gamepad.AxisThreshold)
+	}
+	return c.Controls.check()
 }
 
 var DefaultConfig = Config{
@@ -102,6 +158,12 @@ var DefaultConfig = Config{
 		IconFile:      "logo.png",
 		FontFile:      "PressStart2P.ttf",
 		ResourcesPath: "./assets",
+		Theme:         "classic",
+		ThemesPath:    "./data/themes/",
+		Language:      FallbackLocale,
+		LangPath:      "./data/lang/",
+		Transition:    "fade",
+		TransitionMS:  200,
 	},
 	Game: GameConfig{
 		GridColumns:      30,
@@ -109,25 +171,42 @@ var DefaultConfig = Config{
 		BombPercent:      10,
 		Lives:            3,
 		WrongFlagPenalty: false,
+		PlayerName:       "Player",
+		PauseOnFocusLoss: true,
+		ClickToMove:      false,
+	},
+	Audio: AudioConfig{
+		MasterVolume: 80,
+		SfxVolume:    100,
+		MusicVolume:  50,
+		Mute:         false,
+	},
+	Accessibility: AccessibilityConfig{
+		ColorMode:    "normal",
+		HighContrast: false,
+		NumberGlyphs: false,
 	},
 	Controls: GameControls{
 		Names: ControlNames{
-			KeyUp:     "up",
-			KeyDown:   "down",
-			KeyLeft:   "left",
-			KeyRight:  "right",
-			KeyFlag:   "f",
-			KeyOpen:   "space",
-			KeyReplay: "r",
-		},
-		Codes: ControlCodes{
-			KeyUp:     sdl.K_UNKNOWN,
-			KeyDown:   sdl.K_UNKNOWN,
-			KeyLeft:   sdl.K_UNKNOWN,
-			KeyRight:  sdl.K_UNKNOWN,
-			KeyFlag:   sdl.K_UNKNOWN,
-			KeyOpen:   sdl.K_UNKNOWN,
-			KeyReplay: sdl.K_UNKNOWN,
+			KeyUp:          KeyList{"Up", "W", "pad:dpup", "pad:lefty-"},
+			KeyDown:        KeyList{"Down", "S", "pad:dpdown", "pad:lefty+"},
+			KeyLeft:        KeyList{"Left", "A", "pad:dpleft", "pad:leftx-"},
+			KeyRight:       KeyList{"Right", "D", "pad:dpright", "pad:leftx+"},
+			KeyFlag:        KeyList{"F", "mouse:right", "pad:x"},
+			KeyOpen:        KeyList{"Space", "pad:a"},
+			KeyChord:       KeyList{"X", "mouse:middle", "pad:y"},
+			KeyReplay:      KeyList{"R", "pad:b"},
+			KeyZoomIn:      KeyList{"=", "wheel:up", "pad:righttrigger+"},
+			KeyZoomOut:     KeyList{"-", "wheel:down", "pad:lefttrigger+"},
+			KeyRecenter:    KeyList{"C", "pad:rightstick"},
+			KeyRotateLeft:  KeyList{"Q", "pad:leftshoulder"},
+			KeyRotateRight: KeyList{"E", "pad:rightshoulder"},
+			KeyMinimap:     KeyList{"M", "pad:back"},
 		},
 	},
+	Gamepad: GamepadConfig{
+		AxisThreshold: 16000,
+		Rumble:        true,
+		Mappings:      []string{},
+	},
 }
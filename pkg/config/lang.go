@@ -1,30 +1,192 @@
 package config
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// name of the locale used when a key is missing from a language file
+const FallbackLocale = "en"
+
+/*
+Text depending on a count, the form is chosen by the language's plural rules.
+
+Written either as a single string (used for every count) or as the forms:
+zero (optional, used for 0 when set), one, few, many and other
+*/
+type Plural struct {
+	Zero  string `yaml:"zero,omitempty"`
+	One   string `yaml:"one,omitempty"`
+	Few   string `yaml:"few,omitempty"`
+	Many  string `yaml:"many,omitempty"`
+	Other string `yaml:"other"`
+}
+
+func (p *Plural) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err == nil {
+		*p = Plural{Other: text}
+		return nil
+	}
+	type plural Plural // without the UnmarshalYAML method
+	var forms plural
+	if err := unmarshal(&forms); err != nil {
+		return err
+	}
+	*p = Plural(forms)
+	return nil
+}
+
+// Plural category of n in the locale ("one", "few", "many" or "other"), by the CLDR rules of the shipped languages
+func pluralCategory(locale string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+	switch strings.SplitN(strings.SplitN(locale, "-", 2)[0], "_", 2)[0] {
+	case "fr", "pt":
+		if n <= 1 {
+			return "one"
+		}
+	case "ru", "uk":
+		if mod10 == 1 && mod100 != 11 {
+			return "one"
+		}
+		if mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14) {
+			return "few"
+		}
+		return "many"
+	case "pl":
+		if n == 1 {
+			return "one"
+		}
+		if mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14) {
+			return "few"
+		}
+		return "many"
+	case "ja", "ko", "zh":
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+// Form of the text for n in the locale, the "other" form if the language file doesn't have it
+func (p Plural) Form(locale string, n int) string {
+	if n == 0 && p.Zero != "" {
+		return p.Zero
+	}
+	form := ""
+	switch pluralCategory(locale, n) {
+	case "one":
+		form = p.One
+	case "few":
+		form = p.Few
+	case "many":
+		form = p.Many
+	}
+	if form == "" {
+		return p.Other
+	}
+	return form
+}
+
 type MainMenuLang struct {
+	Title      string `yaml:"title"`
 	NewGame    string `yaml:"new-game"`
 	ResumeGame string `yaml:"resume-game"`
+	Statistics string `yaml:"statistics"`
 	Settings   string `yaml:"settings"`
 	Exit       string `yaml:"exit"`
 }
 
 type SettingsLang struct {
-	Columns string `yaml:"columns"`
-	Rows    string `yaml:"rows"`
-	Bombs   string `yaml:"bombs"`
-	Lives   string `yaml:"lives"`
-	// LivesNoLimit string `yaml:"livesNoLimit"`
+	WindowSettings   string `yaml:"window-settings"`
+	ToggleFullscreen string `yaml:"toggle-fullscreen"`
+	ToggleBorders    string `yaml:"toggle-borders"`
+	Theme            string `yaml:"theme"`
+	Language         string `yaml:"language"`
+
+	GridSettings     string `yaml:"grid-settings"`
+	Columns          string `yaml:"columns"`
+	Rows             string `yaml:"rows"`
+	Bombs            Plural `yaml:"bombs"`
+	Lives            string `yaml:"lives"`
+	LivesNoLimit     string `yaml:"lives-no-limit"`
+	PlayerName       string `yaml:"player-name"`
+	NameEmpty        string `yaml:"name-empty"`
+	PauseOnFocusLoss string `yaml:"pause-on-focus-loss"`
+
+	AudioSettings string `yaml:"audio-settings"`
+	MasterVolume  string `yaml:"master-volume"`
+	SfxVolume     string `yaml:"sfx-volume"`
+	MusicVolume   string `yaml:"music-volume"`
+	Mute          string `yaml:"mute"`
+	NoAudioDevice string `yaml:"no-audio-device"`
+
+	Accessibility   string `yaml:"accessibility"`
+	ColorMode       string `yaml:"color-mode"`
+	ColorNormal     string `yaml:"color-normal"`
+	ColorRedGreen   string `yaml:"color-red-green"`
+	ColorBlueYellow string `yaml:"color-blue-yellow"`
+	HighContrast    string `yaml:"high-contrast"`
+	NumberGlyphs    string `yaml:"number-glyphs"`
+
+	Controls string `yaml:"controls"`
+
+	On     string `yaml:"on"`
+	Off    string `yaml:"off"`
+	GoBack string `yaml:"go-back"`
+}
+
+type ControlsLang struct {
+	Title string `yaml:"title"`
+
+	// names of the actions
+	Up          string `yaml:"up"`
+	Down        string `yaml:"down"`
+	Left        string `yaml:"left"`
+	Right       string `yaml:"right"`
+	Open        string `yaml:"open"`
+	Flag        string `yaml:"flag"`
+	Chord       string `yaml:"chord"`
+	Replay      string `yaml:"replay"`
+	ZoomIn      string `yaml:"zoom-in"`
+	ZoomOut     string `yaml:"zoom-out"`
+	Recenter    string `yaml:"recenter"`
+	RotateLeft  string `yaml:"rotate-left"`
+	RotateRight string `yaml:"rotate-right"`
+	Minimap     string `yaml:"minimap"`
+
+	WaitingKey     string `yaml:"waiting-key"`
+	PressKey       string `yaml:"press-key"`
+	KeyChanged     string `yaml:"key-changed"`
+	KeySwapped     string `yaml:"key-swapped"`
+	KeyReserved    string `yaml:"key-reserved"`
+	KeyNoName      string `yaml:"key-no-name"`
+	MenuFullscreen string `yaml:"menu-fullscreen"`
+	KeyConflict    string `yaml:"key-conflict"`  // binding then the actions using it
+	ConflictMark   string `yaml:"conflict-mark"` // around a binding used by several actions
+	ResetDefaults  string `yaml:"reset-defaults"`
+	DefaultsReset  string `yaml:"defaults-reset"`
 }
 
 type GameLang struct {
 	TilesHidden    string `yaml:"tiles-hidden"`
 	FlagsUsed      string `yaml:"flags-used"`
-	bombsRemaining string `yaml:"bombs-remaining"`
-	livesLeft      string `yaml:"lives-left"`
+	BombsRemaining string `yaml:"bombs-remaining"`
+	LivesLeft      string `yaml:"lives-left"`
+	LivesInfinite  string `yaml:"lives-infinite"`
 
 	MovedTo             string `yaml:"moved-to"`
 	BombExploded        string `yaml:"bomb-exploded"`
 	OpenedSingleTile    string `yaml:"opened-single-tile"`
-	OpenedMultipleTiles string `yaml:"opened-multiple-tiles"`
+	OpenedMultipleTiles Plural `yaml:"opened-multiple-tiles"`
 	FlagSetWrong        string `yaml:"flag-set-wrong"`
 	FlagSet             string `yaml:"flag-set-unspecified"`
 	FlagUnset           string `yaml:"flag-unset"`
@@ -33,48 +195,376 @@ type GameLang struct {
 	GameWon        string `yaml:"game-won"`
 	IncorrectFlags string `yaml:"incorrect-flags"`
 
+	// stats shown when the game is over
+	TotalTiles         Plural `yaml:"total-tiles"`
+	TotalBombsExploded Plural `yaml:"total-bombs-exploded"`
+	TotalLivesLeft     Plural `yaml:"total-lives-left"`
+	TotalLivesNoLimit  string `yaml:"total-lives-no-limit"`
+	Time               string `yaml:"time"`
+
 	MainMenuBtn string `yaml:"main-menu"`
 	SettingsBtn string `yaml:"settings-menu"`
 }
 
+type PauseLang struct {
+	Title         string `yaml:"title"`
+	Resume        string `yaml:"resume"`
+	Restart       string `yaml:"restart"`
+	NewGame       string `yaml:"new-game"`
+	Settings      string `yaml:"settings"`
+	QuitToMenu    string `yaml:"quit-to-menu"`
+	QuitToDesktop string `yaml:"quit-to-desktop"`
+}
+
+// Texts of the end-of-game results, the labels are followed by their value
+type ResultsLang struct {
+	Won             string `yaml:"won"`
+	Lost            string `yaml:"lost"`
+	Time            string `yaml:"time"`
+	TilesOpened     string `yaml:"tiles-opened"`
+	Flags           string `yaml:"flags"`
+	FlagsValue      string `yaml:"flags-value"` // correct then wrong flags
+	LivesUsed       string `yaml:"lives-used"`
+	NoLivesLimit    string `yaml:"no-lives-limit"`
+	HintsUndos      string `yaml:"hints-undos"`
+	HintsUndosValue string `yaml:"hints-undos-value"` // hints then undos
+	Clicks          string `yaml:"clicks"`
+	Efficiency      string `yaml:"efficiency"`
+	EfficiencyValue string `yaml:"efficiency-value"` // percentage then 3BV
+	Seed            string `yaml:"seed"`
+	PersonalBest    string `yaml:"personal-best"`
+	NewBest         string `yaml:"new-best"`
+	NoBest          string `yaml:"no-best"`
+	None            string `yaml:"none"`
+	Replay          string `yaml:"replay"`
+	NewBoard        string `yaml:"new-board"`
+	SaveReplay      string `yaml:"save-replay"`
+	ExportBoard     string `yaml:"export-board"`
+	MainMenu        string `yaml:"main-menu"`
+	Exported        string `yaml:"exported"`
+	ExportFailed    string `yaml:"export-failed"`
+	ReplaySaved     string `yaml:"replay-saved"`
+	ReplayFailed    string `yaml:"replay-failed"`
+}
+
+// Texts of the statistics of the finished games
+type StatsLang struct {
+	Title          string `yaml:"title"`
+	Player         string `yaml:"player"`
+	AllPlayers     string `yaml:"all-players"`
+	GridFilter     string `yaml:"grid-filter"` // of the times histogram
+	AllGrids       string `yaml:"all-grids"`
+	GamesPlayed    string `yaml:"games-played"`
+	WinRate        string `yaml:"win-rate"`
+	WinRateValue   string `yaml:"win-rate-value"` // percentage then games won
+	CurrentStreak  string `yaml:"current-streak"`
+	LongestStreak  string `yaml:"longest-streak"`
+	Grid           string `yaml:"grid"`
+	Played         string `yaml:"played"`
+	Won            string `yaml:"won"`
+	Best           string `yaml:"best"`
+	Average        string `yaml:"average"`
+	None           string `yaml:"none"`
+	Times          string `yaml:"times"`
+	TimesRange     string `yaml:"times-range"` // longest time of the histogram
+	Days           string `yaml:"days"`
+	DaysRange      string `yaml:"days-range"` // days shown
+	ExportCSV      string `yaml:"export-csv"`
+	ExportJSON     string `yaml:"export-json"`
+	Back           string `yaml:"back"`
+	Exported       string `yaml:"exported"`
+	ExportFailed   string `yaml:"export-failed"`
+	LoadFailed     string `yaml:"load-failed"`
+	RecordsSkipped string `yaml:"records-skipped"` // some records couldn't be read, the others are shown
+}
+
+type DialogLang struct {
+	Yes    string `yaml:"yes"`
+	No     string `yaml:"no"`
+	Ok     string `yaml:"ok"`
+	Cancel string `yaml:"cancel"`
+
+	LeaveGameTitle string `yaml:"leave-game-title"`
+	LeaveGame      string `yaml:"leave-game"`
+	NewGameTitle   string `yaml:"new-game-title"`
+	NewGame        string `yaml:"new-game"`
+	StartNewGame   string `yaml:"start-new-game"`
+	ContinueGame   string `yaml:"continue-game"`
+	RestartTitle   string `yaml:"restart-title"`
+	Restart        string `yaml:"restart"`
+	QuitTitle      string `yaml:"quit-title"`
+	Quit           string `yaml:"quit"`
+	SaveFailed     string `yaml:"save-failed"`
+
+	// errors found when the game starts, the error is followed by the fallback
+	InvalidConfig    string `yaml:"invalid-config"`
+	ConfigFallback   string `yaml:"config-fallback"`
+	InvalidLanguage  string `yaml:"invalid-language"`
+	LanguageFallback string `yaml:"language-fallback"`
+	InvalidTheme     string `yaml:"invalid-theme"`
+	ThemeFallback    string `yaml:"theme-fallback"`
+}
+
+// Content of a language file, the texts are fmt formats
 type LangConfig struct {
+	Name string `yaml:"name"` // shown in the language picker
+	// fallback fonts for the language's script, tried before the theme's ones (looked up like the theme's font)
+	Fonts    []string     `yaml:"fonts,omitempty"`
 	MainMenu MainMenuLang `yaml:"main-menu"`
 	Settings SettingsLang `yaml:"settings-menu"`
+	Controls ControlsLang `yaml:"controls-menu"`
 	Game     GameLang     `yaml:"game"`
+	Pause    PauseLang    `yaml:"pause-menu"`
+	Results  ResultsLang  `yaml:"results"`
+	Stats    StatsLang    `yaml:"statistics"`
+	Dialog   DialogLang   `yaml:"dialog"`
 }
 
+// English texts, the keys missing from a language file keep these
 var DefaultLang = LangConfig{
+	Name: "English",
 	MainMenu: MainMenuLang{
+		Title:      "Isometric minesweeper",
 		NewGame:    "New Game",
 		ResumeGame: "Continue",
-		Settings:   "Exit",
+		Statistics: "Statistics",
+		Settings:   "Settings",
+		Exit:       "Exit",
 	},
 	Settings: SettingsLang{
-		Columns: "Grid columns: %d",
-		Rows:    "Grid rows: %d",
-		Bombs:   "Bombs: %d (%d of the tiles)",
-		Lives:   "Lives: %d",
+		WindowSettings:   "Window settings",
+		ToggleFullscreen: "Fullscreen",
+		ToggleBorders:    "Window borders",
+		Theme:            "Theme : %s",
+		Language:         "Language : %s",
+		GridSettings:     "Grid settings (changes for the next game)",
+		Columns:          "Grid columns: %d",
+		Rows:             "Grid rows: %d",
+		Bombs:            Plural{Other: "Bombs: %d (%d%% of the tiles)"},
+		Lives:            "Lives: %d",
+		LivesNoLimit:     "Lives: no limit",
+		PlayerName:       "Player name",
+		NameEmpty:        "The name can't be empty",
+		PauseOnFocusLoss: "Pause on focus loss : %s",
+		AudioSettings:    "Audio settings",
+		MasterVolume:     "Master volume : %d%%",
+		SfxVolume:        "Effects volume : %d%%",
+		MusicVolume:      "Music volume : %d%%",
+		Mute:             "Mute : %s",
+		NoAudioDevice:    "Mute : off (no audio device)",
+		Accessibility:    "Accessibility",
+		ColorMode:        "Colors : %s",
+		ColorNormal:      "normal",
+		ColorRedGreen:    "red-green safe",
+		ColorBlueYellow:  "blue-yellow safe",
+		HighContrast:     "High contrast : %s",
+		NumberGlyphs:     "Number glyphs : %s",
+		Controls:         "Controls",
+		On:               "on",
+		Off:              "off",
+		GoBack:           "Go back",
+	},
+	Controls: ControlsLang{
+		Title:          "Controls",
+		Up:             "Move up",
+		Down:           "Move down",
+		Left:           "Move left",
+		Right:          "Move right",
+		Open:           "Open tile",
+		Flag:           "Toggle flag",
+		Chord:          "Open around",
+		Replay:         "Replay",
+		ZoomIn:         "Zoom in",
+		ZoomOut:        "Zoom out",
+		Recenter:       "Recenter",
+		RotateLeft:     "Rotate left",
+		RotateRight:    "Rotate right",
+		Minimap:        "Minimap",
+		WaitingKey:     "Press a key or a button for %q (Escape or left click to cancel)",
+		PressKey:       "...",
+		KeyChanged:     "%q is now on [%s]",
+		KeySwapped:     "[%s] was used by %q, which now uses [%s]",
+		KeyReserved:    "[%s] is reserved",
+		KeyNoName:      "This key can't be used",
+		MenuFullscreen: "[%s] also toggles fullscreen in the main menu",
+		KeyConflict:    "[%s] is used by %s, only the first one gets it",
+		ConflictMark:   "%s (!)",
+		ResetDefaults:  "Reset to defaults",
+		DefaultsReset:  "The default keys are restored",
 	},
 	Game: GameLang{
 		TilesHidden:         "Tiles hidden: %d/%d",
 		FlagsUsed:           "Flags used: %d",
-		bombsRemaining:      "Bombs remaining: %d",
-		livesLeft:           "Lives left: %d/%d",
+		BombsRemaining:      "Bombs remaining: %d",
+		LivesLeft:           "Lives left: %d/%d",
+		LivesInfinite:       "Lives left: ∞",
 		MovedTo:             "Moved to tile @%d;%d",
 		BombExploded:        "Bomb exploded @%d;%d",
 		OpenedSingleTile:    "Opened tile @%d;%d",
-		OpenedMultipleTiles: "Opened %d tiles from @%d;%d",
+		OpenedMultipleTiles: Plural{One: "Opened %d tile from @%d;%d", Other: "Opened %d tiles from @%d;%d"},
 		FlagSetWrong:        "Wrong flag set on tile @%d;%d",
 		FlagSet:             "Set flag @%d;%d",
 		FlagUnset:           "Unset flag @%d;%d",
 		GameLost:            "Game lost (no lives left) press [%s] to replay",
 		GameWon:             "Game won, press [%s] to replay",
 		IncorrectFlags:      "At least one flag isn't right",
+		TotalTiles:          Plural{One: "%d tile", Other: "%d tiles"},
+		TotalBombsExploded:  Plural{One: "%d/%d bomb exploded", Other: "%d/%d bombs exploded"},
+		TotalLivesLeft:      Plural{One: "%d/%d life left", Other: "%d/%d lives left"},
+		TotalLivesNoLimit:   "Unlimited lives",
+		Time:                "Time: %s",
 		MainMenuBtn:         "Main menu",
 		SettingsBtn:         "Settings",
 	},
+	Pause: PauseLang{
+		Title:         "Paused",
+		Resume:        "Resume",
+		Restart:       "Restart this board",
+		NewGame:       "New game",
+		Settings:      "Settings",
+		QuitToMenu:    "Save and quit to menu",
+		QuitToDesktop: "Quit to desktop",
+	},
+	Results: ResultsLang{
+		Won:             "Board cleared",
+		Lost:            "Game over",
+		Time:            "Time",
+		TilesOpened:     "Tiles opened",
+		Flags:           "Flags",
+		FlagsValue:      "%d correct, %d wrong",
+		LivesUsed:       "Lives used",
+		NoLivesLimit:    "%d (no limit)",
+		HintsUndos:      "Hints / undos",
+		HintsUndosValue: "%d / %d",
+		Clicks:          "Clicks",
+		Efficiency:      "Efficiency",
+		EfficiencyValue: "%d%% (3BV %d)",
+		Seed:            "Board seed",
+		PersonalBest:    "Personal best",
+		NewBest:         "New personal best!",
+		NoBest:          "No win yet",
+		None:            "-",
+		Replay:          "Replay this board",
+		NewBoard:        "New board",
+		SaveReplay:      "Save replay",
+		ExportBoard:     "Export board",
+		MainMenu:        "Main menu",
+		Exported:        "Board exported",
+		ExportFailed:    "Export failed",
+		ReplaySaved:     "Replay saved",
+		ReplayFailed:    "The replay couldn't be saved",
+	},
+	Stats: StatsLang{
+		Title:          "Statistics",
+		Player:         "Player : %s",
+		AllPlayers:     "All players",
+		GridFilter:     "Grid : %s",
+		AllGrids:       "All grids",
+		GamesPlayed:    "Games played",
+		WinRate:        "Win rate",
+		WinRateValue:   "%d%% (%d won)",
+		CurrentStreak:  "Current streak",
+		LongestStreak:  "Longest streak",
+		Grid:           "Grid",
+		Played:         "Played",
+		Won:            "Won",
+		Best:           "Best",
+		Average:        "Average",
+		None:           "-",
+		Times:          "Times of the won games",
+		TimesRange:     "0:00 to %s",
+		Days:           "Games played by day",
+		DaysRange:      "The last %d days",
+		ExportCSV:      "Export CSV",
+		ExportJSON:     "Export JSON",
+		Back:           "Back",
+		Exported:       "History exported",
+		ExportFailed:   "Export failed",
+		LoadFailed:     "The history couldn't be read",
+		RecordsSkipped: "Some games of the history couldn't be read",
+	},
+	Dialog: DialogLang{
+		Yes:              "Yes",
+		No:               "No",
+		Ok:               "OK",
+		Cancel:           "Cancel",
+		LeaveGameTitle:   "Leave the game?",
+		LeaveGame:        "The game in progress can be continued from the main menu",
+		NewGameTitle:     "Start a new game?",
+		NewGame:          "The game in progress will be lost",
+		StartNewGame:     "New game",
+		ContinueGame:     "Continue",
+		RestartTitle:     "Restart this board?",
+		Restart:          "The progress on this board will be lost",
+		QuitTitle:        "Quit the game?",
+		Quit:             "The game in progress will be lost",
+		SaveFailed:       "Couldn't save the game",
+		InvalidConfig:    "Invalid config",
+		ConfigFallback:   "The default settings are used, changing the settings replaces the file",
+		InvalidLanguage:  "Invalid language",
+		LanguageFallback: "The texts are shown in English",
+		InvalidTheme:     "Invalid theme",
+		ThemeFallback:    "The default theme is used",
+	},
 }
 
 func (c *LangConfig) Check() error {
+	if c.Name == "" {
+		return fmt.Errorf("lang: missing name")
+	}
 	return nil
 }
+
+/*
+Keys of a language file's section, nil for a value that isn't a section.
+
+They are decoded as text like the Lang struct does, a key such as yes or no
+is read as a boolean otherwise when it isn't quoted
+*/
+type langKeys map[string]interface{}
+
+func (k *langKeys) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var keys map[string]interface{}
+	if err := unmarshal(&keys); err != nil {
+		*k = nil
+		return nil
+	}
+	*k = keys
+	return nil
+}
+
+/*
+Keys ("section.key") of the fallback language missing from the language file's content.
+
+The plural forms aren't compared since each language has its own
+*/
+func MissingLangKeys(data []byte) ([]string, error) {
+	defaults, err := yaml.Marshal(DefaultLang)
+	if err != nil {
+		return nil, fmt.Errorf("missing keys: %s", err)
+	}
+	var reference, content map[string]langKeys
+	if err := yaml.Unmarshal(defaults, &reference); err != nil {
+		return nil, fmt.Errorf("missing keys: %s", err)
+	}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("missing keys: couldn't unmarshal language file: %s", err)
+	}
+	missing := []string{}
+	for section, keys := range reference {
+		contentKeys, found := content[section]
+		if keys == nil {
+			if !found {
+				missing = append(missing, section)
+			}
+			continue
+		}
+		for key := range keys {
+			if _, found := contentKeys[key]; !found {
+				missing = append(missing, fmt.Sprintf("%s.%s", section, key))
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
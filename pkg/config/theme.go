@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// name of the manifest file in each theme directory
+const ThemeManifestFile = "theme.yml"
+
+// Color written as "#rrggbb" or "#rrggbbaa" in the theme files
+type ThemeColor sdl.Color
+
+func (c *ThemeColor) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err != nil {
+		return err
+	}
+	hex := strings.TrimPrefix(text, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return fmt.Errorf("invalid color %q (expected #rrggbb or #rrggbbaa)", text)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fmt.Errorf("invalid color %q: %s", text, err)
+	}
+	*c = ThemeColor{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}
+	return nil
+}
+
+func (c ThemeColor) MarshalYAML() (interface{}, error) {
+	if c.A == sdl.ALPHA_OPAQUE {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B), nil
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A), nil
+}
+
+// Name of the sprite used for each role on the board (its index for the spritesheets cut in a grid)
+type ThemeSprites struct {
+	Border       string `yaml:"border"`
+	Empty        string `yaml:"empty"`
+	Hover        string `yaml:"hover"`
+	Hidden       string `yaml:"hidden"`
+	Flag         string `yaml:"flag"`
+	Bomb         string `yaml:"bomb"`
+	BombExploded string `yaml:"bomb-exploded"`
+	// sprites of the tiles with 1 to 8 bombs around
+	Numbers []string `yaml:"numbers"`
+}
+
+// Either an atlas file, or an image cut in a grid of columns x rows sprites
+type ThemeSpritesheet struct {
+	Atlas   string       `yaml:"atlas"`
+	File    string       `yaml:"file"`
+	Columns int32        `yaml:"columns"`
+	Rows    int32        `yaml:"rows"`
+	Sprites ThemeSprites `yaml:"sprites"`
+	// for a grid, added to a sprite's index to get its "hover" variant drawn on the player's tile
+	HighlightOffset uint32 `yaml:"highlight-offset"`
+}
+
+// Every sprite name, in the order of the roles
+func (s ThemeSprites) Names() []string {
+	return append([]string{s.Border, s.Empty, s.Hover, s.Hidden, s.Flag, s.Bomb, s.BombExploded}, s.Numbers...)
+}
+
+// Colors of the menus and the HUD
+type ThemePalette struct {
+	Background        ThemeColor `yaml:"background"`
+	Text              ThemeColor `yaml:"text"`
+	TextDisabled      ThemeColor `yaml:"text-disabled"`
+	TextSeparator     ThemeColor `yaml:"text-separator"`
+	ButtonBackground  ThemeColor `yaml:"button-background"`
+	ButtonHover       ThemeColor `yaml:"button-hover"`
+	ButtonHoverMenu   ThemeColor `yaml:"button-hover-menu"`
+	ButtonHoverAccent ThemeColor `yaml:"button-hover-accent"`
+	HudBackground     ThemeColor `yaml:"hud-background"`
+}
+
+type ThemeFont struct {
+	File string `yaml:"file"`
+	Size int    `yaml:"size"`
+	// fonts used for the characters missing from the font, in order
+	Fallbacks []string `yaml:"fallbacks"`
+}
+
+// Content of a theme's manifest, the files are looked up in the theme's directory then in the resources path
+type ThemeConfig struct {
+	Name        string           `yaml:"name"`
+	Spritesheet ThemeSpritesheet `yaml:"spritesheet"`
+	Palette     ThemePalette     `yaml:"palette"`
+	Font        ThemeFont        `yaml:"font"`
+}
+
+func (t *ThemeConfig) Check() error {
+	sheet := &t.Spritesheet
+	if sheet.Atlas == "" {
+		if sheet.File == "" {
+			return fmt.Errorf("theme: missing spritesheet atlas or file")
+		}
+		if sheet.Columns < 1 || sheet.Rows < 1 {
+			return fmt.Errorf("theme: invalid spritesheet grid: got %dx%d (expected columns>0 and rows>0)", sheet.Columns, sheet.Rows)
+		}
+	}
+	if len(sheet.Sprites.Numbers) != 8 {
+		return fmt.Errorf("theme: invalid numbers sprites: got %d (expected 8)", len(sheet.Sprites.Numbers))
+	}
+	for _, name := range sheet.Sprites.Names() {
+		if name == "" {
+			return fmt.Errorf("theme: missing sprite name")
+		}
+	}
+	if t.Font.File == "" {
+		return fmt.Errorf("theme: missing font file")
+	}
+	if t.Font.Size < 1 {
+		return fmt.Errorf("theme: invalid font size: got %d (expected size>0)", t.Font.Size)
+	}
+	return nil
+}
+
+// Used when the selected theme can't be loaded, its files are in the resources path
+var DefaultTheme = ThemeConfig{
+	Name: "Classic",
+	Spritesheet: ThemeSpritesheet{
+		File:    "spritesheet_iso.png",
+		Columns: 15,
+		Rows:    2,
+		Sprites: ThemeSprites{
+			Border:       "0",
+			Empty:        "1",
+			Hover:        "2",
+			Hidden:       "3",
+			Flag:         "4",
+			Bomb:         "5",
+			BombExploded: "6",
+			Numbers:      []string{"7", "8", "9", "10", "11", "12", "13", "14"},
+		},
+		HighlightOffset: 15,
+	},
+	Palette: ThemePalette{
+		Background:        ThemeColor{R: 20, G: 20, B: 20, A: sdl.ALPHA_OPAQUE},
+		Text:              ThemeColor{R: 255, G: 255, B: 255, A: sdl.ALPHA_OPAQUE},
+		TextDisabled:      ThemeColor{R: 120, G: 120, B: 120, A: sdl.ALPHA_OPAQUE},
+		TextSeparator:     ThemeColor{R: 20, G: 20, B: 20, A: sdl.ALPHA_OPAQUE},
+		ButtonBackground:  ThemeColor{R: 0, G: 0, B: 0, A: sdl.ALPHA_OPAQUE},
+		ButtonHover:       ThemeColor{R: 255, G: 255, B: 255, A: sdl.ALPHA_OPAQUE},
+		ButtonHoverMenu:   ThemeColor{R: 120, G: 120, B: 120, A: sdl.ALPHA_OPAQUE},
+		ButtonHoverAccent: ThemeColor{R: 255, G: 255, B: 0, A: sdl.ALPHA_OPAQUE},
+		HudBackground:     ThemeColor{R: 0, G: 0, B: 0, A: sdl.ALPHA_OPAQUE},
+	},
+	Font: ThemeFont{
+		File: "PressStart2P.ttf",
+		Size: 12,
+	},
+}
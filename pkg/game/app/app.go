@@ -3,12 +3,22 @@ package app
 import (
 	"errors"
 	"fmt"
+	"log"
 	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
 	"minesweeper/pkg/game/scenes"
 	"minesweeper/pkg/game/scenes/game"
+	"minesweeper/pkg/game/scenes/menuControls"
 	"minesweeper/pkg/game/scenes/menuMain"
+	"minesweeper/pkg/game/scenes/menuPause"
+	"minesweeper/pkg/game/scenes/menuResults"
 	"minesweeper/pkg/game/scenes/menuSettings"
+	"minesweeper/pkg/game/scenes/menuStats"
+	"minesweeper/pkg/game/theme"
+	"os"
 	"strings"
 
 	"github.com/veandco/go-sdl2/img"
@@ -20,25 +30,37 @@ type Program struct {
 	fps          int32
 	timePerFrame uint64
 	config       config.Config
-	language     config.LangConfig
 	renderer     *rendering.CustomRenderer
 	icon         *sdl.Surface
-	font         *ttf.Font
+	audio        *audio.Player
+	gamepads     *input.Gamepads
 	isRunning    bool
 	sceneManager *scenes.SceneManager
 }
 
+// Error found while starting, the texts are in lang.Text to be shown in the loaded language
+type startupError struct {
+	title    *string
+	fallback *string
+	err      error
+}
+
 /*
 Generates a new program with the window/renderer and all the scenes
 */
 func NewProgram() (*Program, error) {
-	var cfg config.Config
+	// the settings missing from the file keep their default value
+	cfg := config.DefaultConfig
+	// problems shown in dialogs once the main menu is open
+	var startupErrors []startupError
 	err := config.LoadConfig(config.ConfigFilePath, &cfg)
 	if err == nil {
 		err = cfg.Check()
-		if err != nil {
-			fmt.Printf("Invalid config: %s (fallback to defaults)\n", err)
-		}
+	}
+	// without a config file the defaults are expected, an unreadable or invalid one is reported
+	if _, statErr := os.Stat(config.ConfigFilePath); err != nil && !os.IsNotExist(statErr) {
+		log.Printf("Invalid config: %s (fallback to defaults)\n", err)
+		startupErrors = append(startupErrors, startupError{&lang.Text.Dialog.InvalidConfig, &lang.Text.Dialog.ConfigFallback, err})
 	}
 	if err != nil {
 		cfg = config.DefaultConfig
@@ -62,24 +84,12 @@ func NewProgram() (*Program, error) {
 		windowFlags = windowFlags | sdl.WINDOW_FULLSCREEN_DESKTOP
 	}
 
-	var langCfg config.LangConfig
-	err = config.LoadConfig("data/lang.yml", &cfg)
-	if err == nil {
-		err = langCfg.Check()
-		if err != nil {
-			fmt.Printf("Invalid config: %s (fallback to defaults)\n", err)
-		}
-	}
+	language, err := lang.Load(cfg.Window.LangPath, cfg.Window.Language)
 	if err != nil {
-		langCfg = config.DefaultLang
-		/*
-			err = config.CheckConfig(&langCfg)
-			 if err != nil {
-				return nil, fmt.Errorf("couldn't load default config: %s", err)
-			}
-		*/
+		log.Printf("Invalid language: %s (fallback to English)\n", err)
+		startupErrors = append(startupErrors, startupError{&lang.Text.Dialog.InvalidLanguage, &lang.Text.Dialog.LanguageFallback, err})
+		language = lang.LoadDefault()
 	}
-	fmt.Printf("%+v\n", langCfg)
 
 	customRenderer, err := rendering.CreateCustomRenderer(
 		"13Noodles' Isometric minesweeper",
@@ -98,44 +108,81 @@ func NewProgram() (*Program, error) {
 		return nil, fmt.Errorf("icon load: %s", err)
 	}
 	customRenderer.SDLwindow.SetIcon(icon)
+	// the text fields start the text input while they are focused, the input methods must not take the game's keys
+	sdl.StopTextInput()
 	ttf.Init()
-	font, err := ttf.OpenFont(cfg.Window.ResourcesPath+cfg.Window.FontFile, 12)
+	currentTheme, err := theme.Load(customRenderer, cfg.Window.ThemesPath, cfg.Window.Theme, cfg.Window.ResourcesPath)
 	if err != nil {
-		return nil, fmt.Errorf("font load: %s", err)
+		log.Printf("Invalid theme: %s (fallback to the default theme)\n", err)
+		startupErrors = append(startupErrors, startupError{&lang.Text.Dialog.InvalidTheme, &lang.Text.Dialog.ThemeFallback, err})
+		currentTheme, err = theme.LoadDefault(customRenderer, cfg.Window.ResourcesPath, cfg.Window.FontFile)
+		if err != nil {
+			return nil, fmt.Errorf("default theme load: %s", err)
+		}
 	}
 
+	audioPlayer := audio.NewPlayer(cfg.Window.ResourcesPath, cfg.Audio)
+	gamepads := input.NewGamepads(cfg.Gamepad)
+
 	program := &Program{
 		fps:          cfg.Window.FPS,
 		timePerFrame: uint64(1000 / cfg.Window.FPS),
 		config:       cfg,
-		language:     langCfg,
 		renderer:     customRenderer,
 		icon:         icon,
-		font:         font,
+		audio:        audioPlayer,
+		gamepads:     gamepads,
 		isRunning:    true,
 	}
-	sceneManager, err := scenes.NewSceneManager(&program.isRunning, *program.renderer, cfg)
+	sceneManager, err := scenes.NewSceneManager(&program.isRunning, *program.renderer, cfg, audioPlayer, gamepads, currentTheme, language)
 	if err != nil {
 		return nil, fmt.Errorf("sceneManager load: %s", err)
 	}
-	default_scene, err := menuMain.Initialize(sceneManager, customRenderer, font, langCfg.MainMenu)
+	default_scene, err := menuMain.Initialize(sceneManager, customRenderer)
 	if err != nil {
 		return nil, fmt.Errorf("default scene load: %s", err)
 	}
-	settingsScene, err := menuSettings.Initialize(sceneManager, customRenderer, font)
+	settingsScene, err := menuSettings.Initialize(sceneManager, customRenderer)
+	if err != nil {
+		return nil, fmt.Errorf("scene load: %s", err)
+	}
+	controlsScene, err := menuControls.Initialize(sceneManager, customRenderer)
+	if err != nil {
+		return nil, fmt.Errorf("scene load: %s", err)
+	}
+	gameScene, err := game.Initialize(sceneManager, customRenderer)
+	if err != nil {
+		return nil, fmt.Errorf("scene load: %s", err)
+	}
+	pauseScene, err := menuPause.Initialize(sceneManager, customRenderer)
 	if err != nil {
 		return nil, fmt.Errorf("scene load: %s", err)
 	}
-	gameScene, err := game.Initialize(sceneManager, customRenderer, font)
+	resultsScene, err := menuResults.Initialize(sceneManager, customRenderer)
+	if err != nil {
+		return nil, fmt.Errorf("scene load: %s", err)
+	}
+	statsScene, err := menuStats.Initialize(sceneManager, customRenderer)
 	if err != nil {
 		return nil, fmt.Errorf("scene load: %s", err)
 	}
 
 	sceneManager.AddDefaultScene(default_scene, "main")
 	sceneManager.AddScene(settingsScene, "settings")
+	sceneManager.AddScene(controlsScene, "controls")
 	sceneManager.AddScene(gameScene, "game")
-	sceneManager.SetScene("main", true)
+	sceneManager.AddScene(pauseScene, "pause")
+	sceneManager.AddScene(resultsScene, "results")
+	sceneManager.AddScene(statsScene, "statistics")
+	sceneManager.Push("main")
+	for _, startupErr := range startupErrors {
+		message := fmt.Sprintf("%s\n%s", startupErr.err, *startupErr.fallback)
+		if err := sceneManager.ShowDialog(scenes.NewAlertDialog(*startupErr.title, message, nil)); err != nil {
+			log.Printf("startup error dialog: %s\n", err)
+		}
+	}
 	program.sceneManager = sceneManager
+	audioPlayer.PlayMusic()
 	return program, nil
 }
 
@@ -144,8 +191,14 @@ func (p *Program) Destroy() {
 	if p.icon != nil {
 		p.icon.Free()
 	}
-	if p.font != nil {
-		p.font.Close()
+	if p.sceneManager != nil {
+		p.sceneManager.Destroy()
+	}
+	if p.audio != nil {
+		p.audio.Destroy()
+	}
+	if p.gamepads != nil {
+		p.gamepads.Destroy()
 	}
 	if p.renderer != nil {
 		p.renderer.Destroy()
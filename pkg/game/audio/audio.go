@@ -0,0 +1,158 @@
+package audio
+
+import (
+	"log"
+	"minesweeper/pkg/config"
+
+	"github.com/veandco/go-sdl2/mix"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type SoundId int
+
+const (
+	SoundMove SoundId = iota
+	SoundOpen
+	SoundCascade
+	SoundFlag
+	SoundUnflag
+	SoundExplosion
+	SoundWin
+	SoundLoss
+	SoundClick
+	soundCount
+)
+
+// files relative to the resources path
+var soundFiles = [soundCount]string{
+	SoundMove:      "sounds/move.wav",
+	SoundOpen:      "sounds/open.wav",
+	SoundCascade:   "sounds/cascade.wav",
+	SoundFlag:      "sounds/flag.wav",
+	SoundUnflag:    "sounds/unflag.wav",
+	SoundExplosion: "sounds/explosion.wav",
+	SoundWin:       "sounds/win.wav",
+	SoundLoss:      "sounds/loss.wav",
+	SoundClick:     "sounds/click.wav",
+}
+
+const musicFile = "sounds/music.wav"
+
+// channels mixed at the same time, a sound is dropped when they are all playing
+const mixChannels = 16
+
+/*
+Plays the sound effects and the background music.
+
+If there is no audio device (or SDL_mixer fails to start) the player stays silent,
+every method can still be called. A missing file only silences its own sound
+*/
+type Player struct {
+	enabled bool
+	sounds  [soundCount]*mix.Chunk
+	music   *mix.Music
+	config  config.AudioConfig
+}
+
+func NewPlayer(resourcesPath string, cfg config.AudioConfig) *Player {
+	p := &Player{config: cfg}
+	if err := sdl.InitSubSystem(sdl.INIT_AUDIO); err != nil {
+		log.Printf("audio: no audio device, the game will be silent: %s\n", err)
+		return p
+	}
+	if err := mix.OpenAudio(mix.DEFAULT_FREQUENCY, mix.DEFAULT_FORMAT, mix.DEFAULT_CHANNELS, mix.DEFAULT_CHUNKSIZE); err != nil {
+		log.Printf("audio: couldn't open the audio device, the game will be silent: %s\n", err)
+		sdl.QuitSubSystem(sdl.INIT_AUDIO)
+		return p
+	}
+	p.enabled = true
+	mix.AllocateChannels(mixChannels)
+	for id, file := range soundFiles {
+		chunk, err := mix.LoadWAV(resourcesPath + file)
+		if err != nil {
+			log.Printf("audio: sound load: %s\n", err)
+			continue
+		}
+		p.sounds[id] = chunk
+	}
+	music, err := mix.LoadMUS(resourcesPath + musicFile)
+	if err != nil {
+		log.Printf("audio: music load: %s\n", err)
+	} else {
+		p.music = music
+	}
+	p.SetConfig(cfg)
+	return p
+}
+
+// Frees the sounds and closes the audio device
+func (p *Player) Destroy() {
+	if !p.enabled {
+		return
+	}
+	mix.HaltChannel(-1)
+	mix.HaltMusic()
+	for id, chunk := range p.sounds {
+		if chunk != nil {
+			chunk.Free()
+			p.sounds[id] = nil
+		}
+	}
+	if p.music != nil {
+		p.music.Free()
+		p.music = nil
+	}
+	mix.CloseAudio()
+	sdl.QuitSubSystem(sdl.INIT_AUDIO)
+	p.enabled = false
+}
+
+func (p *Player) IsEnabled() bool {
+	return p.enabled
+}
+
+// Applies the volumes and the mute setting, to the sounds already playing too
+func (p *Player) SetConfig(cfg config.AudioConfig) {
+	p.config = cfg
+	if !p.enabled {
+		return
+	}
+	mix.Volume(-1, mixVolume(cfg, cfg.SfxVolume))
+	mix.VolumeMusic(mixVolume(cfg, cfg.MusicVolume))
+}
+
+func (p *Player) Play(id SoundId) {
+	if !p.enabled || p.config.Mute || id < 0 || id >= soundCount || p.sounds[id] == nil {
+		return
+	}
+	// every channel busy: the sound is skipped
+	p.sounds[id].Play(-1, 0)
+}
+
+// Starts the background music (looping) if it isn't already playing
+func (p *Player) PlayMusic() {
+	if !p.enabled || p.music == nil || mix.PlayingMusic() {
+		return
+	}
+	if err := p.music.Play(-1); err != nil {
+		log.Printf("audio: music play: %s\n", err)
+	}
+}
+
+// Volume from percents (0-100) to SDL_mixer's range (0-128), scaled by the master volume
+func mixVolume(cfg config.AudioConfig, volume int) int {
+	if cfg.Mute {
+		return 0
+	}
+	return clampPercent(cfg.MasterVolume) * clampPercent(volume) * mix.MAX_VOLUME / 10000
+}
+
+func clampPercent(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
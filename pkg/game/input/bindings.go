@@ -0,0 +1,74 @@
+package input
+
+import (
+	"minesweeper/pkg/config"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func key(code sdl.Keycode) config.Binding {
+	return config.Binding{Kind: config.BindKey, Key: code}
+}
+
+func pad(button sdl.GameControllerButton) config.Binding {
+	return config.Binding{Kind: config.BindGamepadButton, Button: uint8(button)}
+}
+
+func axis(a sdl.GameControllerAxis, direction int32) config.Binding {
+	return config.Binding{Kind: config.BindGamepadAxis, Button: uint8(a), Direction: direction}
+}
+
+// Bindings of every menu: leaving it, moving the focus over its buttons and clicking the focused one
+func MenuBindings() Bindings {
+	return Bindings{
+		Menu:          {key(config.KeyBack), pad(sdl.CONTROLLER_BUTTON_B)},
+		Confirm:       {key(sdl.K_RETURN), key(sdl.K_KP_ENTER), pad(sdl.CONTROLLER_BUTTON_A)},
+		FocusNext:     {key(sdl.K_TAB)},
+		FocusPrevious: {{Kind: config.BindKey, Key: sdl.K_TAB, Mod: sdl.KMOD_SHIFT}},
+		MoveUp:        {key(sdl.K_UP), pad(sdl.CONTROLLER_BUTTON_DPAD_UP), axis(sdl.CONTROLLER_AXIS_LEFTY, -1)},
+		MoveDown:      {key(sdl.K_DOWN), pad(sdl.CONTROLLER_BUTTON_DPAD_DOWN), axis(sdl.CONTROLLER_AXIS_LEFTY, 1)},
+		MoveLeft:      {key(sdl.K_LEFT), pad(sdl.CONTROLLER_BUTTON_DPAD_LEFT), axis(sdl.CONTROLLER_AXIS_LEFTX, -1)},
+		MoveRight:     {key(sdl.K_RIGHT), pad(sdl.CONTROLLER_BUTTON_DPAD_RIGHT), axis(sdl.CONTROLLER_AXIS_LEFTX, 1)},
+	}
+}
+
+// Bindings of the pause menu, Start also resumes the game
+func PauseBindings() Bindings {
+	bindings := MenuBindings()
+	bindings[Menu] = append(bindings[Menu], pad(sdl.CONTROLLER_BUTTON_START))
+	return bindings
+}
+
+// Bindings of the main menu, with the fullscreen toggle (B doesn't quit the game)
+func MainMenuBindings() Bindings {
+	bindings := MenuBindings()
+	bindings[Menu] = []config.Binding{key(config.KeyBack)}
+	bindings[Fullscreen] = []config.Binding{key(config.KeyMenuFullscreen)}
+	return bindings
+}
+
+// Bindings of the game, from the controls of the config (checked), the reserved keys and the focus of its buttons
+func GameBindings(controls config.GameControls) Bindings {
+	b := &controls.Bindings
+	return Bindings{
+		MoveUp:      b.KeyUp,
+		MoveDown:    b.KeyDown,
+		MoveLeft:    b.KeyLeft,
+		MoveRight:   b.KeyRight,
+		Open:        b.KeyOpen,
+		Flag:        b.KeyFlag,
+		Chord:       b.KeyChord,
+		Replay:      b.KeyReplay,
+		ZoomIn:      append(append([]config.Binding{}, b.KeyZoomIn...), key(config.KeyZoomInAlt)),
+		ZoomOut:     append(append([]config.Binding{}, b.KeyZoomOut...), key(config.KeyZoomOutAlt)),
+		Recenter:    b.KeyRecenter,
+		RotateLeft:  b.KeyRotateLeft,
+		RotateRight: b.KeyRotateRight,
+		Minimap:     b.KeyMinimap,
+		Menu:        {key(config.KeyBack), pad(sdl.CONTROLLER_BUTTON_START)},
+		// the game's actions come first, they keep their bindings
+		FocusNext:     {key(sdl.K_TAB)},
+		FocusPrevious: {{Kind: config.BindKey, Key: sdl.K_TAB, Mod: sdl.KMOD_SHIFT}},
+		Confirm:       {key(sdl.K_RETURN), key(sdl.K_KP_ENTER)},
+	}
+}
@@ -0,0 +1,98 @@
+package input
+
+import (
+	"log"
+	"minesweeper/pkg/config"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	rumbleLowFrequency  = 0xC000
+	rumbleHighFrequency = 0x8000
+)
+
+/*
+Game controllers connected to the game.
+
+SDL sends an added event for each controller already plugged in at start, then one
+per connection, so the controllers are only opened by ProcessEvent
+*/
+type Gamepads struct {
+	controllers map[sdl.JoystickID]*sdl.GameController
+	config      config.GamepadConfig
+	mappings    map[string]bool // mappings already given to SDL
+}
+
+func NewGamepads(cfg config.GamepadConfig) *Gamepads {
+	g := &Gamepads{
+		controllers: map[sdl.JoystickID]*sdl.GameController{},
+		mappings:    map[string]bool{},
+	}
+	g.SetConfig(cfg)
+	return g
+}
+
+// Applies the axis threshold and adds the new mappings (SDL can't remove them)
+func (g *Gamepads) SetConfig(cfg config.GamepadConfig) {
+	g.config = cfg
+	SetAxisThreshold(cfg.AxisThreshold)
+	for _, mapping := range cfg.Mappings {
+		if g.mappings[mapping] {
+			continue
+		}
+		g.mappings[mapping] = true
+		if sdl.GameControllerAddMapping(mapping) == -1 {
+			log.Printf("gamepad: invalid mapping %q: %s\n", mapping, sdl.GetError())
+		}
+	}
+}
+
+// Opens and closes the controllers when they are connected and disconnected
+func (g *Gamepads) ProcessEvent(e sdl.Event) {
+	t, ok := e.(*sdl.ControllerDeviceEvent)
+	if !ok {
+		return
+	}
+	switch t.Type {
+	case sdl.CONTROLLERDEVICEADDED:
+		// Which is the device index here
+		controller := sdl.GameControllerOpen(int(t.Which))
+		if controller == nil {
+			log.Printf("gamepad: couldn't open controller %d: %s\n", t.Which, sdl.GetError())
+			return
+		}
+		id := controller.Joystick().InstanceID()
+		if _, found := g.controllers[id]; found {
+			// already opened, SDL counts the references
+			controller.Close()
+			return
+		}
+		g.controllers[id] = controller
+		log.Printf("gamepad: %q connected\n", controller.Name())
+	case sdl.CONTROLLERDEVICEREMOVED:
+		if controller, found := g.controllers[t.Which]; found {
+			log.Printf("gamepad: %q disconnected\n", controller.Name())
+			controller.Close()
+			delete(g.controllers, t.Which)
+		}
+	}
+}
+
+// Rumbles every controller for the duration, if the config enables it
+func (g *Gamepads) Rumble(durationMS uint32) {
+	if !g.config.Rumble {
+		return
+	}
+	for _, controller := range g.controllers {
+		// controllers without rumble return an error, they are ignored
+		controller.Rumble(rumbleLowFrequency, rumbleHighFrequency, durationMS)
+	}
+}
+
+func (g *Gamepads) Destroy() {
+	for id, controller := range g.controllers {
+		controller.Close()
+		delete(g.controllers, id)
+	}
+}
@@ -0,0 +1,280 @@
+package input
+
+import (
+	"minesweeper/pkg/config"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Abstract input of the scenes, triggered by any number of bindings
+type Action int
+
+const (
+	ActionNone Action = iota
+	MoveUp
+	MoveDown
+	MoveLeft
+	MoveRight
+	Open
+	Flag
+	Chord
+	Replay
+	ZoomIn
+	ZoomOut
+	Recenter
+	RotateLeft
+	RotateRight
+	Minimap
+	Menu // leaves the scene
+	Fullscreen
+	Confirm
+	FocusNext
+	FocusPrevious
+)
+
+// held buttons (not keys, the system repeats them) of these actions are repeated
+var repeatedActions = map[Action]bool{
+	MoveUp:        true,
+	MoveDown:      true,
+	MoveLeft:      true,
+	MoveRight:     true,
+	ZoomIn:        true,
+	ZoomOut:       true,
+	FocusNext:     true,
+	FocusPrevious: true,
+}
+
+const (
+	repeatDelay    = 400 // ms before the first repeat
+	repeatInterval = 100
+)
+
+// Action triggered by an input event
+type Event struct {
+	Action  Action
+	Pressed bool // false when the binding is released
+	Repeat  bool // repeated while held
+	Binding config.Binding
+	X, Y    int32 // pointer position, for the mouse and wheel bindings
+}
+
+// axis value past which the axis bindings are pressed, they are released under half of it
+var axisThreshold int32 = 16000
+
+// Sets the axis value past which a stick or a trigger binding is pressed (the game controllers config)
+func SetAxisThreshold(threshold int16) {
+	axisThreshold = int32(threshold)
+}
+
+// Direction of the axis (1 or -1) if it is pushed past the threshold, else 0
+func AxisDirection(value int16) int32 {
+	switch {
+	case int32(value) > axisThreshold:
+		return 1
+	case int32(value) < -axisThreshold:
+		return -1
+	}
+	return 0
+}
+
+// Bindings of each action
+type Bindings map[Action][]config.Binding
+
+type heldBinding struct {
+	action  Action
+	elapsed uint64 // ms since the press
+	next    uint64 // elapsed time of the next repeat
+}
+
+/*
+Actions of a scene and their bindings.
+
+The scene gives its events to Process and its frame time to Update, and consumes the
+returned actions. The just pressed and repeated states are the ones since the previous Update
+*/
+type Context struct {
+	actions         map[config.Binding]Action
+	held            map[config.Binding]*heldBinding
+	justPressed     map[Action]bool
+	repeated        map[Action]bool
+	nextJustPressed map[Action]bool
+	nextRepeated    map[Action]bool
+}
+
+// A binding used by several actions triggers the first one
+func NewContext(bindings Bindings) *Context {
+	c := &Context{
+		actions:         map[config.Binding]Action{},
+		held:            map[config.Binding]*heldBinding{},
+		justPressed:     map[Action]bool{},
+		repeated:        map[Action]bool{},
+		nextJustPressed: map[Action]bool{},
+		nextRepeated:    map[Action]bool{},
+	}
+	for action := ActionNone + 1; action <= FocusPrevious; action++ {
+		for _, binding := range bindings[action] {
+			if _, found := c.actions[binding]; !found {
+				c.actions[binding] = action
+			}
+		}
+	}
+	return c
+}
+
+// Action of the key with its modifiers, or of the key alone when no binding uses these modifiers
+func (c *Context) keyAction(binding config.Binding) (config.Binding, Action, bool) {
+	if action, found := c.actions[binding]; found {
+		return binding, action, true
+	}
+	binding.Mod = 0
+	action, found := c.actions[binding]
+	return binding, action, found
+}
+
+// Actions triggered by the event
+func (c *Context) Process(e sdl.Event) []Event {
+	switch t := e.(type) {
+	case *sdl.KeyboardEvent:
+		if t.State == sdl.PRESSED {
+			binding, action, found := c.keyAction(config.Binding{
+				Kind: config.BindKey,
+				Key:  t.Keysym.Sym,
+				Mod:  config.BindingMod(t.Keysym.Mod),
+			})
+			if !found {
+				return nil
+			}
+			return []Event{c.press(binding, action, t.Repeat != 0, 0, 0)}
+		}
+		// the modifiers may have been released first
+		events := []Event{}
+		for binding, held := range c.held {
+			if binding.Kind == config.BindKey && binding.Key == t.Keysym.Sym {
+				events = append(events, c.release(binding, held.action, 0, 0))
+			}
+		}
+		return events
+
+	case *sdl.MouseButtonEvent:
+		binding := config.Binding{Kind: config.BindMouseButton, Button: t.Button}
+		return c.button(binding, t.State == sdl.PRESSED, t.X, t.Y)
+
+	case *sdl.ControllerButtonEvent:
+		binding := config.Binding{Kind: config.BindGamepadButton, Button: t.Button}
+		return c.button(binding, t.State == sdl.PRESSED, 0, 0)
+
+	case *sdl.ControllerAxisEvent:
+		events := []Event{}
+		for _, direction := range []int32{1, -1} {
+			binding := config.Binding{Kind: config.BindGamepadAxis, Button: t.Axis, Direction: direction}
+			_, held := c.held[binding]
+			value := int32(t.Value) * direction
+			// released under half of the threshold, so a stick near the threshold doesn't flicker
+			if (!held && value > axisThreshold) || (held && value < axisThreshold/2) {
+				events = append(events, c.button(binding, !held, 0, 0)...)
+			}
+		}
+		return events
+
+	case *sdl.MouseWheelEvent:
+		amount := t.Y
+		if t.Direction == sdl.MOUSEWHEEL_FLIPPED {
+			amount = -amount
+		}
+		binding := config.Binding{Kind: config.BindWheel, Direction: 1}
+		if amount < 0 {
+			binding.Direction = -1
+			amount = -amount
+		}
+		action, found := c.actions[binding]
+		if !found {
+			return nil
+		}
+		x, y, _ := sdl.GetMouseState()
+		// a step per wheel notch
+		events := make([]Event, 0, amount)
+		for i := int32(0); i < amount; i++ {
+			c.nextJustPressed[action] = true
+			events = append(events, Event{Action: action, Pressed: true, Binding: binding, X: x, Y: y})
+		}
+		return events
+	}
+	return nil
+}
+
+func (c *Context) button(binding config.Binding, pressed bool, x, y int32) []Event {
+	action, found := c.actions[binding]
+	if !found {
+		return nil
+	}
+	if pressed {
+		return []Event{c.press(binding, action, false, x, y)}
+	}
+	if _, held := c.held[binding]; !held {
+		return nil
+	}
+	return []Event{c.release(binding, action, x, y)}
+}
+
+func (c *Context) press(binding config.Binding, action Action, repeat bool, x, y int32) Event {
+	if repeat {
+		c.nextRepeated[action] = true
+	} else {
+		c.nextJustPressed[action] = true
+		c.held[binding] = &heldBinding{action: action, next: repeatDelay}
+	}
+	return Event{Action: action, Pressed: true, Repeat: repeat, Binding: binding, X: x, Y: y}
+}
+
+func (c *Context) release(binding config.Binding, action Action, x, y int32) Event {
+	delete(c.held, binding)
+	return Event{Action: action, Pressed: false, Binding: binding, X: x, Y: y}
+}
+
+// Starts a new frame and returns the repeats of the held buttons
+func (c *Context) Update(deltaMS uint64) []Event {
+	c.justPressed, c.nextJustPressed = c.nextJustPressed, map[Action]bool{}
+	c.repeated, c.nextRepeated = c.nextRepeated, map[Action]bool{}
+	events := []Event{}
+	for binding, held := range c.held {
+		if binding.Kind == config.BindKey || !repeatedActions[held.action] {
+			continue
+		}
+		held.elapsed += deltaMS
+		for held.elapsed >= held.next {
+			held.next += repeatInterval
+			c.repeated[held.action] = true
+			events = append(events, Event{Action: held.action, Pressed: true, Repeat: true, Binding: binding})
+		}
+	}
+	return events
+}
+
+// Releases every binding, for a scene that stops receiving the events
+func (c *Context) Reset() {
+	c.held = map[config.Binding]*heldBinding{}
+	c.justPressed = map[Action]bool{}
+	c.repeated = map[Action]bool{}
+	c.nextJustPressed = map[Action]bool{}
+	c.nextRepeated = map[Action]bool{}
+}
+
+// True if a binding of the action was pressed before the last Update
+func (c *Context) JustPressed(action Action) bool {
+	return c.justPressed[action]
+}
+
+// True if a binding of the action was repeated before the last Update
+func (c *Context) Repeated(action Action) bool {
+	return c.repeated[action]
+}
+
+// True while a binding of the action is held
+func (c *Context) Held(action Action) bool {
+	for _, held := range c.held {
+		if held.action == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package lang
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"minesweeper/pkg/config"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// extension of the language files, named by their locale (like "fr.yml")
+const fileExtension = ".yml"
+
+// Texts of the current language, updated by Use. The scenes' widgets data keep pointers to them
+var Text = config.DefaultLang
+
+// locale of Text, for the plural forms
+var locale = config.FallbackLocale
+
+/*
+Loaded language.
+
+The scenes keep the language they were built with and rebuild their widgets when
+the scene manager's language changed
+*/
+type Language struct {
+	Locale string
+	Config config.LangConfig
+}
+
+// Locales of the language files of langPath, sorted
+func List(langPath string) []string {
+	entries, err := ioutil.ReadDir(langPath)
+	if err != nil {
+		log.Printf("lang list: %s\n", err)
+		return nil
+	}
+	locales := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), fileExtension) {
+			locales = append(locales, strings.TrimSuffix(entry.Name(), fileExtension))
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Path of the locale's file in langPath
+func FilePath(langPath, locale string) string {
+	return filepath.Join(langPath, locale+fileExtension)
+}
+
+// Loads the locale's file from langPath, the keys missing from it keep the English text
+func Load(langPath, locale string) (*Language, error) {
+	cfg := config.DefaultLang
+	if err := config.LoadConfig(FilePath(langPath, locale), &cfg); err != nil {
+		return nil, fmt.Errorf("lang %q: %s", locale, err)
+	}
+	if err := cfg.Check(); err != nil {
+		return nil, fmt.Errorf("lang %q: %s", locale, err)
+	}
+	return &Language{Locale: locale, Config: cfg}, nil
+}
+
+// Name of the language in its file (its locale if the file can't be read)
+func Name(langPath, locale string) string {
+	language, err := Load(langPath, locale)
+	if err != nil {
+		return locale
+	}
+	return language.Config.Name
+}
+
+// Built-in English texts
+func LoadDefault() *Language {
+	return &Language{Locale: config.FallbackLocale, Config: config.DefaultLang}
+}
+
+// Makes the language's texts the current ones
+func (l *Language) Use() {
+	Text = l.Config
+	locale = l.Locale
+}
+
+// Form of the text for the count n in the current language
+func Plural(p config.Plural, n int) string {
+	return p.Form(locale, n)
+}
@@ -0,0 +1,102 @@
+package rendering
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"gopkg.in/yaml.v2"
+)
+
+// Sprite of an atlas file, rect is its position in the image
+type AtlasSprite struct {
+	Rect sdl.Rect `yaml:"rect"`
+	// position in the sprite of the cell's top-left corner, for sprites bigger than the cell (like tall isometric tiles)
+	Pivot    sdl.Point              `yaml:"pivot"`
+	Variants map[string]AtlasSprite `yaml:"variants"`
+}
+
+type AtlasCell struct {
+	W int32 `yaml:"w"`
+	H int32 `yaml:"h"`
+}
+
+// Content of an atlas file (YAML or JSON)
+type AtlasFile struct {
+	Image   string                 `yaml:"image"` // relative to the atlas file
+	Cell    AtlasCell              `yaml:"cell"`
+	Sprites map[string]AtlasSprite `yaml:"sprites"`
+}
+
+/*
+Spritesheet with the named sprites of an atlas file.
+
+The variants are also reachable by the name "sprite:variant"
+*/
+func NewAtlas(renderer *CustomRenderer, atlasPath string) (*Spritesheet, error) {
+	data, err := ioutil.ReadFile(atlasPath)
+	if err != nil {
+		return nil, fmt.Errorf("atlas: couldn't read file: %s", err)
+	}
+	var atlas AtlasFile
+	if err := yaml.UnmarshalStrict(data, &atlas); err != nil {
+		return nil, fmt.Errorf("atlas: couldn't unmarshal file: %s", err)
+	}
+	if atlas.Image == "" {
+		return nil, fmt.Errorf("atlas: missing image")
+	}
+	if atlas.Cell.W < 1 || atlas.Cell.H < 1 {
+		return nil, fmt.Errorf("atlas: invalid cell size: got %dx%d (expected w>0 and h>0)", atlas.Cell.W, atlas.Cell.H)
+	}
+	if len(atlas.Sprites) == 0 {
+		return nil, fmt.Errorf("atlas: no sprites")
+	}
+	texture, err := loadTexture(renderer, filepath.Join(filepath.Dir(atlasPath), atlas.Image))
+	if err != nil {
+		return nil, err
+	}
+	_, _, w, h, _ := texture.Query()
+	s := &Spritesheet{
+		names:   map[string]uint32{},
+		cell:    sdl.Rect{W: atlas.Cell.W, H: atlas.Cell.H},
+		texture: texture,
+	}
+	// sorted so the ids don't change between loads
+	names := make([]string, 0, len(atlas.Sprites))
+	for name := range atlas.Sprites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sprite := atlas.Sprites[name]
+		if err := checkAtlasRect(name, sprite.Rect, w, h); err != nil {
+			s.Destroy()
+			return nil, err
+		}
+		id := s.addFrame(name, sprite.Rect, sprite.Pivot)
+		variants := make([]string, 0, len(sprite.Variants))
+		for variant := range sprite.Variants {
+			variants = append(variants, variant)
+		}
+		sort.Strings(variants)
+		for _, variant := range variants {
+			variantSprite := sprite.Variants[variant]
+			variantName := name + ":" + variant
+			if err := checkAtlasRect(variantName, variantSprite.Rect, w, h); err != nil {
+				s.Destroy()
+				return nil, err
+			}
+			s.SetVariant(id, variant, s.addFrame(variantName, variantSprite.Rect, variantSprite.Pivot))
+		}
+	}
+	return s, nil
+}
+
+func checkAtlasRect(name string, rect sdl.Rect, w, h int32) error {
+	if rect.W < 1 || rect.H < 1 || rect.X < 0 || rect.Y < 0 || rect.X+rect.W > w || rect.Y+rect.H > h {
+		return fmt.Errorf("atlas: sprite %q: rect %v out of the %dx%d image", name, rect, w, h)
+	}
+	return nil
+}
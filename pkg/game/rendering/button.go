@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/veandco/go-sdl2/ttf"
 )
 
 type ButtonActionId int
@@ -101,7 +100,11 @@ func (b *Button) IsSelectable() bool {
 	return b.selectable
 }
 
-func (b *Button) UpdateTexture(renderer *sdl.Renderer, font *ttf.Font) error {
+func (b *Button) Bounds() sdl.Rect {
+	return b.Rect
+}
+
+func (b *Button) UpdateTexture(renderer *sdl.Renderer, font *Font) error {
 	if b.Text != "" {
 		surface, err := font.RenderUTF8Solid(b.Text, b.color)
 		if err != nil {
@@ -122,7 +125,7 @@ func (b *Button) UpdateTexture(renderer *sdl.Renderer, font *ttf.Font) error {
 	}
 	return nil
 }
-func (b *Button) SetText(text string, renderer *sdl.Renderer, font *ttf.Font, color sdl.Color) error {
+func (b *Button) SetText(text string, renderer *sdl.Renderer, font *Font, color sdl.Color) error {
 	b.Text = text
 	b.color = color
 	b.UpdateTexture(renderer, font)
@@ -175,6 +178,11 @@ func (b *Button) Height() int32 {
 	return b.Rect.H
 }
 
+// Size of the text (or of the image), without the background
+func (b *Button) ContentSize() (int32, int32) {
+	return b.innerRect.W, b.innerRect.H
+}
+
 func (b *Button) IsHovered() bool {
 	return b.hovered
 }
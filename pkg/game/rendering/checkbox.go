@@ -0,0 +1,73 @@
+package rendering
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// On/off toggle, changed by a click or by Confirm while focused
+type Checkbox struct {
+	control
+	Checked  bool
+	textOf   func(checked bool) string
+	OnChange func(checked bool) // called when the player toggles it
+}
+
+func NewCheckbox(renderer *CustomRenderer, font *Font, checked bool, textOf func(checked bool) string, color, backgroundColor, hoverColor sdl.Color) *Checkbox {
+	c := &Checkbox{
+		control: newControl(renderer, font, color, backgroundColor, hoverColor),
+		textOf:  textOf,
+	}
+	h := c.lineHeight()
+	c.setGraphicsSize(h, h)
+	c.SetChecked(checked)
+	return c
+}
+
+// Sets the state without calling OnChange
+func (c *Checkbox) SetChecked(checked bool) {
+	c.Checked = checked
+	c.setText(c.textOf(checked))
+}
+
+// Updates the text, when it depends on more than the state
+func (c *Checkbox) Refresh() {
+	c.setText(c.textOf(c.Checked))
+}
+
+func (c *Checkbox) toggle() {
+	c.SetChecked(!c.Checked)
+	if c.OnChange != nil {
+		c.OnChange(c.Checked)
+	}
+}
+
+func (c *Checkbox) ProcessMouse(e sdl.Event) bool {
+	switch t := e.(type) {
+	case *sdl.MouseMotionEvent:
+		c.hovered = c.OnControl(sdl.Point{X: t.X, Y: t.Y})
+	case *sdl.MouseButtonEvent:
+		if t.Button == sdl.BUTTON_LEFT && t.State == sdl.PRESSED && c.OnControl(sdl.Point{X: t.X, Y: t.Y}) {
+			c.toggle()
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checkbox) Step(dx, dy int) bool {
+	return false
+}
+
+func (c *Checkbox) Activate() {
+	c.toggle()
+}
+
+func (c *Checkbox) Draw(r *CustomRenderer) {
+	c.drawBase(r)
+	box := c.graphicsRect()
+	r.SetDrawColor(c.color)
+	r.SDLrenderer.DrawRect(&box)
+	if c.Checked {
+		inside := sdl.Rect{X: box.X + box.W/4, Y: box.Y + box.H/4, W: box.W / 2, H: box.H / 2}
+		r.SDLrenderer.FillRect(&inside)
+	}
+	c.drawFrame(r)
+}
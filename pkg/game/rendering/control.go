@@ -0,0 +1,258 @@
+package rendering
+
+import (
+	"log"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Base of the widgets changing a value (slider, checkbox, dropdown, stepper): a text on the left,
+the control's graphics on its right, and a background with the hover and selection frames.
+
+The text is given by a function of the value, so it can show the value ("Volume : 50%")
+*/
+type control struct {
+	Rect            sdl.Rect
+	innerRect       sdl.Rect // the text and the graphics, in Rect
+	renderer        *CustomRenderer
+	font            *Font
+	Text            string
+	texture         *sdl.Texture
+	textRect        sdl.Rect // size of the texture
+	graphics        sdl.Point
+	color           sdl.Color
+	backgroundColor sdl.Color
+	hoverColor      sdl.Color
+	pos             sdl.Point
+	centerX         bool
+	centerY         bool
+	hovered         bool
+	selected        bool
+	selectable      bool
+}
+
+// space between the text and the graphics
+const controlSpacing = 10
+
+func newControl(renderer *CustomRenderer, font *Font, color, backgroundColor, hoverColor sdl.Color) control {
+	return control{
+		renderer:        renderer,
+		font:            font,
+		color:           color,
+		backgroundColor: backgroundColor,
+		hoverColor:      hoverColor,
+		centerX:         true,
+		centerY:         true,
+		selectable:      true,
+	}
+}
+
+// Renders the text, the graphics (as high as a line) keep their size
+func (c *control) setText(text string) {
+	if text == c.Text && c.texture != nil {
+		return
+	}
+	texture, err := c.renderer.NewTextTexture(text, c.font, c.color)
+	if err != nil {
+		log.Printf("control text: %s\n", err)
+		return
+	}
+	if c.texture != nil {
+		c.texture.Destroy()
+	}
+	c.Text = text
+	c.texture = texture
+	c.textRect = sdl.Rect{}
+	if texture != nil {
+		_, _, c.textRect.W, c.textRect.H, _ = texture.Query()
+	}
+	c.updatePos()
+}
+
+// height of a line of text, the unit of the graphics
+func (c *control) lineHeight() int32 {
+	return int32(c.font.Height())
+}
+
+func (c *control) setGraphicsSize(w, h int32) {
+	c.graphics = sdl.Point{X: w, Y: h}
+	c.updatePos()
+}
+
+// Size of the text and the graphics, without the background
+func (c *control) ContentSize() (int32, int32) {
+	w, h := c.textRect.W, c.textRect.H
+	if c.graphics.X > 0 {
+		if w > 0 {
+			w += controlSpacing
+		}
+		w += c.graphics.X
+	}
+	if c.graphics.Y > h {
+		h = c.graphics.Y
+	}
+	return w, h
+}
+
+// Area of the graphics on screen, right of the text
+func (c *control) graphicsRect() sdl.Rect {
+	return sdl.Rect{
+		X: c.innerRect.X + c.innerRect.W - c.graphics.X,
+		Y: c.innerRect.Y + (c.innerRect.H-c.graphics.Y)/2,
+		W: c.graphics.X,
+		H: c.graphics.Y,
+	}
+}
+
+func (c *control) updatePos() {
+	c.innerRect.W, c.innerRect.H = c.ContentSize()
+	if c.Rect.W < c.innerRect.W {
+		c.Rect.W = c.innerRect.W
+	}
+	if c.Rect.H < c.innerRect.H {
+		c.Rect.H = c.innerRect.H
+	}
+	if c.centerX {
+		c.Rect.X = c.pos.X - c.Rect.W/2
+	} else {
+		c.Rect.X = c.pos.X
+	}
+	if c.centerY {
+		c.Rect.Y = c.pos.Y - c.Rect.H/2
+	} else {
+		c.Rect.Y = c.pos.Y
+	}
+	// a larger background keeps the text on the left and the graphics on the right
+	inset := (c.Rect.W - c.innerRect.W) / 2
+	if max := c.lineHeight() / 2; inset > max {
+		inset = max
+	}
+	c.innerRect.X = c.Rect.X + inset
+	c.innerRect.W = c.Rect.W - 2*inset
+	c.innerRect.Y = c.Rect.Y + (c.Rect.H-c.innerRect.H)/2
+}
+
+func (c *control) SetBackgroundSize(w int32, h int32) {
+	c.Rect.W = w
+	c.Rect.H = h
+	c.updatePos()
+}
+
+func (c *control) SetCenter(x int32, y int32) {
+	c.pos = sdl.Point{X: x, Y: y}
+	c.centerX = true
+	c.centerY = true
+	c.updatePos()
+}
+
+func (c *control) SetTopLeft(x int32, y int32) {
+	c.pos = sdl.Point{X: x, Y: y}
+	c.centerX = false
+	c.centerY = false
+	c.updatePos()
+}
+
+func (c *control) SetX(x int32, isCenter bool) {
+	c.pos.X = x
+	c.centerX = isCenter
+	c.updatePos()
+}
+
+func (c *control) SetY(y int32, isCenter bool) {
+	c.pos.Y = y
+	c.centerY = isCenter
+	c.updatePos()
+}
+
+func (c *control) Width() int32 {
+	return c.Rect.W
+}
+
+func (c *control) Height() int32 {
+	return c.Rect.H
+}
+
+func (c *control) Selected(value bool) {
+	if c.selectable {
+		c.selected = value
+	}
+}
+
+func (c *control) IsSelected() bool {
+	return c.selected
+}
+
+func (c *control) SetSelectable(value bool) {
+	c.selectable = value
+	if !c.selectable {
+		c.selected = false
+	}
+}
+
+func (c *control) IsSelectable() bool {
+	return c.selectable
+}
+
+func (c *control) Bounds() sdl.Rect {
+	return c.Rect
+}
+
+func (c *control) SetHovered(state bool) {
+	c.hovered = state
+}
+
+func (c *control) OnControl(pos sdl.Point) bool {
+	return pos.InRect(&c.Rect)
+}
+
+func (c *control) Destroy() {
+	if c.texture != nil {
+		c.texture.Destroy()
+		c.texture = nil
+	}
+}
+
+// Default behaviors, the controls override the ones they use
+
+func (c *control) Cancel() bool {
+	return false
+}
+
+func (c *control) Captures() bool {
+	return false
+}
+
+func (c *control) Update(deltaMS uint64) {}
+
+// Draws the background and the text, the controls draw their graphics over it
+func (c *control) drawBase(r *CustomRenderer) {
+	r.SetDrawColor(c.backgroundColor)
+	r.SDLrenderer.FillRect(&c.Rect)
+	if c.texture != nil {
+		dst := sdl.Rect{X: c.innerRect.X, Y: c.innerRect.Y + (c.innerRect.H-c.textRect.H)/2, W: c.textRect.W, H: c.textRect.H}
+		r.SDLrenderer.Copy(c.texture, nil, &dst)
+	}
+}
+
+// Draws the hover or selection frame, over everything else
+func (c *control) drawFrame(r *CustomRenderer) {
+	if c.hovered {
+		r.SetDrawColor(c.hoverColor)
+		r.SDLrenderer.DrawRect(&c.Rect)
+	} else if c.selected {
+		r.SetDrawColor(c.color)
+		r.SDLrenderer.DrawRect(&c.Rect)
+	}
+}
+
+// clamps the value between min and max
+func clampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
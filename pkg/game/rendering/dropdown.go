@@ -0,0 +1,234 @@
+package rendering
+
+import (
+	"log"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Choice among options, shown in a popup list under the dropdown (over it near the bottom of the
+window). The list opens on a click or Confirm, the arrows then move in it, Confirm or a click
+chooses, Menu or a click outside closes it.
+
+While it is open, it takes all the mouse events and the scenes draw it over the other widgets
+*/
+type Dropdown struct {
+	control
+	Options     []string
+	Index       int // of the chosen option
+	textOf      func(option string) string
+	OnChange    func(index int) // called when the player chooses another option
+	open        bool
+	highlighted int // option under the mouse or the arrows, in the open list
+	optionTex   []*sdl.Texture
+	optionSizes []sdl.Point
+	popup       sdl.Rect // area of the open list
+	rowHeight   int32
+}
+
+func NewDropdown(renderer *CustomRenderer, font *Font, options []string, index int, textOf func(option string) string, color, backgroundColor, hoverColor sdl.Color) *Dropdown {
+	d := &Dropdown{
+		control: newControl(renderer, font, color, backgroundColor, hoverColor),
+		textOf:  textOf,
+	}
+	h := d.lineHeight()
+	d.rowHeight = h + h/2
+	d.setGraphicsSize(h, h)
+	d.SetOptions(options, index)
+	return d
+}
+
+// Replaces the options, without calling OnChange
+func (d *Dropdown) SetOptions(options []string, index int) {
+	d.destroyOptions()
+	d.Options = options
+	d.optionTex = make([]*sdl.Texture, len(options))
+	d.optionSizes = make([]sdl.Point, len(options))
+	for i, option := range options {
+		texture, err := d.renderer.NewTextTexture(option, d.font, d.color)
+		if err != nil {
+			log.Printf("dropdown option %q: %s\n", option, err)
+			continue
+		}
+		d.optionTex[i] = texture
+		if texture != nil {
+			_, _, d.optionSizes[i].X, d.optionSizes[i].Y, _ = texture.Query()
+		}
+	}
+	d.SetIndex(index)
+}
+
+// Chooses the option without calling OnChange
+func (d *Dropdown) SetIndex(index int) {
+	if len(d.Options) == 0 {
+		d.Index = 0
+		d.setText(d.textOf(""))
+		return
+	}
+	d.Index = clampInt(index, 0, len(d.Options)-1)
+	d.setText(d.textOf(d.Options[d.Index]))
+}
+
+func (d *Dropdown) choose(index int) {
+	d.close()
+	if index == d.Index {
+		return
+	}
+	d.SetIndex(index)
+	if d.OnChange != nil {
+		d.OnChange(d.Index)
+	}
+}
+
+// Opens the list under the dropdown, or over it if it doesn't fit in the window
+func (d *Dropdown) openList() {
+	if len(d.Options) == 0 {
+		return
+	}
+	d.open = true
+	d.highlighted = d.Index
+	padding := d.rowHeight - d.lineHeight()
+	d.popup = sdl.Rect{X: d.Rect.X, Y: d.Rect.Y + d.Rect.H, W: d.Rect.W, H: d.rowHeight * int32(len(d.Options))}
+	for _, size := range d.optionSizes {
+		if size.X+2*padding > d.popup.W {
+			d.popup.W = size.X + 2*padding
+		}
+	}
+	_, windowHeight := d.renderer.SDLwindow.GetSize()
+	if d.popup.Y+d.popup.H > windowHeight && d.Rect.Y-d.popup.H >= 0 {
+		d.popup.Y = d.Rect.Y - d.popup.H
+	}
+}
+
+func (d *Dropdown) close() {
+	d.open = false
+}
+
+func (d *Dropdown) IsOpen() bool {
+	return d.open
+}
+
+// option at the position in the open list, -1 if none
+func (d *Dropdown) optionAt(pos sdl.Point) int {
+	if !pos.InRect(&d.popup) {
+		return -1
+	}
+	return int((pos.Y - d.popup.Y) / d.rowHeight)
+}
+
+func (d *Dropdown) ProcessMouse(e sdl.Event) bool {
+	switch t := e.(type) {
+	case *sdl.MouseMotionEvent:
+		pos := sdl.Point{X: t.X, Y: t.Y}
+		d.hovered = d.OnControl(pos)
+		if d.open {
+			if i := d.optionAt(pos); i != -1 {
+				d.highlighted = i
+			}
+			return true
+		}
+	case *sdl.MouseButtonEvent:
+		if t.Button != sdl.BUTTON_LEFT || t.State != sdl.PRESSED {
+			return d.open
+		}
+		pos := sdl.Point{X: t.X, Y: t.Y}
+		if d.open {
+			if i := d.optionAt(pos); i != -1 {
+				d.choose(i)
+			} else {
+				d.close()
+			}
+			return true
+		}
+		if d.OnControl(pos) {
+			d.openList()
+			return true
+		}
+	case *sdl.MouseWheelEvent:
+		if d.open {
+			d.Step(0, -int(t.Y))
+			return true
+		}
+	}
+	return false
+}
+
+// Moves in the open list (the closed dropdown lets the focus move)
+func (d *Dropdown) Step(dx, dy int) bool {
+	if !d.open {
+		return false
+	}
+	d.highlighted = clampInt(d.highlighted+dy, 0, len(d.Options)-1)
+	return true
+}
+
+func (d *Dropdown) Activate() {
+	if d.open {
+		d.choose(d.highlighted)
+	} else {
+		d.openList()
+	}
+}
+
+func (d *Dropdown) Cancel() bool {
+	if !d.open {
+		return false
+	}
+	d.close()
+	return true
+}
+
+func (d *Dropdown) Captures() bool {
+	return d.open
+}
+
+func (d *Dropdown) destroyOptions() {
+	for _, texture := range d.optionTex {
+		if texture != nil {
+			texture.Destroy()
+		}
+	}
+	d.optionTex = nil
+}
+
+func (d *Dropdown) Destroy() {
+	d.destroyOptions()
+	d.control.Destroy()
+}
+
+func (d *Dropdown) Draw(r *CustomRenderer) {
+	d.drawBase(r)
+	// arrow pointing down
+	arrow := d.graphicsRect()
+	r.SetDrawColor(d.color)
+	for i := int32(0); i < arrow.H/2; i++ {
+		y := arrow.Y + arrow.H/4 + i
+		r.SDLrenderer.DrawLine(arrow.X+i, y, arrow.X+arrow.W-1-i, y)
+	}
+	d.drawFrame(r)
+	if !d.open {
+		return
+	}
+	r.SetDrawColor(d.backgroundColor)
+	r.SDLrenderer.FillRect(&d.popup)
+	r.SetDrawColor(d.color)
+	r.SDLrenderer.DrawRect(&d.popup)
+	padding := d.rowHeight - d.lineHeight()
+	for i, texture := range d.optionTex {
+		row := sdl.Rect{X: d.popup.X, Y: d.popup.Y + int32(i)*d.rowHeight, W: d.popup.W, H: d.rowHeight}
+		if i == d.highlighted {
+			r.SetDrawColor(d.hoverColor)
+			r.SDLrenderer.DrawRect(&row)
+		}
+		if i == d.Index {
+			mark := sdl.Rect{X: row.X + padding/4, Y: row.Y + row.H/2 - padding/4, W: padding / 2, H: padding / 2}
+			r.SetDrawColor(d.color)
+			r.SDLrenderer.FillRect(&mark)
+		}
+		if texture != nil {
+			size := d.optionSizes[i]
+			r.SDLrenderer.Copy(texture, nil, &sdl.Rect{X: row.X + padding, Y: row.Y + (row.H-size.Y)/2, W: size.X, H: size.Y})
+		}
+	}
+}
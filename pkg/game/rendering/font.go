@@ -0,0 +1,272 @@
+package rendering
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"unicode"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// Font file of the fallback chain and its characters (nil if unknown, the font is then used for everything)
+type fontFace struct {
+	path     string
+	coverage glyphCoverage
+}
+
+func (f *fontFace) has(r rune) bool {
+	return f.coverage == nil || f.coverage.has(r)
+}
+
+type fontKey struct {
+	path string
+	size int
+}
+
+/*
+Primary font and its fallbacks (the current language's ones, then the theme's ones).
+
+Text is split in runs of characters having a glyph in the same font, each run is rendered
+with the first font of the chain having its glyphs (the primary font when none has them).
+The fonts are opened once per point size
+*/
+type FontManager struct {
+	primary   fontFace
+	fallbacks []fontFace // the theme's ones
+	language  []fontFace
+	chain     []*fontFace
+	opened    map[fontKey]*ttf.Font
+	fonts     map[int]*Font
+}
+
+// Font of the chain at a point size
+type Font struct {
+	manager *FontManager
+	size    int
+}
+
+type textRun struct {
+	face *fontFace
+	text string
+}
+
+// The fallback files that can't be read are skipped
+func NewFontManager(primary string, fallbacks []string) *FontManager {
+	m := &FontManager{
+		opened: map[fontKey]*ttf.Font{},
+		fonts:  map[int]*Font{},
+	}
+	var err error
+	if m.primary, err = loadFontFace(primary); err != nil {
+		// the primary font may still be opened by SDL_ttf, it is then used for every character
+		log.Printf("font %q: %s\n", primary, err)
+		m.primary = fontFace{path: primary}
+	}
+	m.fallbacks = loadFallbacks(fallbacks)
+	m.updateChain()
+	return m
+}
+
+func loadFontFace(path string) (fontFace, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fontFace{}, err
+	}
+	coverage, err := parseGlyphCoverage(data)
+	if err != nil {
+		return fontFace{}, err
+	}
+	return fontFace{path: path, coverage: coverage}, nil
+}
+
+func loadFallbacks(paths []string) []fontFace {
+	faces := make([]fontFace, 0, len(paths))
+	for _, path := range paths {
+		face, err := loadFontFace(path)
+		if err != nil {
+			log.Printf("fallback font %q skipped: %s\n", path, err)
+			continue
+		}
+		faces = append(faces, face)
+	}
+	return faces
+}
+
+func (m *FontManager) updateChain() {
+	m.chain = []*fontFace{&m.primary}
+	for i := range m.language {
+		m.chain = append(m.chain, &m.language[i])
+	}
+	for i := range m.fallbacks {
+		m.chain = append(m.chain, &m.fallbacks[i])
+	}
+}
+
+// Sets the fallback fonts of the language, tried before the theme's ones
+func (m *FontManager) SetLanguageFallbacks(paths []string) {
+	m.language = loadFallbacks(paths)
+	m.updateChain()
+}
+
+// Font at the point size, the primary font is opened right away to report its errors
+func (m *FontManager) Font(size int) (*Font, error) {
+	if font, found := m.fonts[size]; found {
+		return font, nil
+	}
+	if _, err := m.open(&m.primary, size); err != nil {
+		return nil, err
+	}
+	font := &Font{manager: m, size: size}
+	m.fonts[size] = font
+	return font, nil
+}
+
+func (m *FontManager) open(face *fontFace, size int) (*ttf.Font, error) {
+	key := fontKey{path: face.path, size: size}
+	if font, found := m.opened[key]; found {
+		return font, nil
+	}
+	font, err := ttf.OpenFont(face.path, size)
+	if err != nil {
+		return nil, fmt.Errorf("font %q: %s", face.path, err)
+	}
+	m.opened[key] = font
+	return font, nil
+}
+
+// Splits the text in runs of the first font having the characters' glyphs, the spaces stay in the current run
+func (m *FontManager) runs(text string) []textRun {
+	runs := []textRun{}
+	start := 0
+	var current *fontFace
+	for i, r := range text {
+		face := current
+		if current == nil || !(unicode.IsSpace(r) || current.has(r)) {
+			face = m.chain[0]
+			for _, f := range m.chain {
+				if f.has(r) {
+					face = f
+					break
+				}
+			}
+		}
+		if face != current {
+			if current != nil {
+				runs = append(runs, textRun{face: current, text: text[start:i]})
+			}
+			current = face
+			start = i
+		}
+	}
+	if current != nil {
+		runs = append(runs, textRun{face: current, text: text[start:]})
+	}
+	return runs
+}
+
+func (m *FontManager) Destroy() {
+	for key, font := range m.opened {
+		font.Close()
+		delete(m.opened, key)
+	}
+	m.fonts = map[int]*Font{}
+}
+
+func (f *Font) Size() int {
+	return f.size
+}
+
+// Height of a line of the primary font
+func (f *Font) Height() int {
+	font, err := f.manager.open(&f.manager.primary, f.size)
+	if err != nil {
+		return 0
+	}
+	return font.Height()
+}
+
+func (f *Font) RenderUTF8Solid(text string, color sdl.Color) (*sdl.Surface, error) {
+	return f.render(text, func(font *ttf.Font, run string) (*sdl.Surface, error) {
+		return font.RenderUTF8Solid(run, color)
+	})
+}
+
+func (f *Font) RenderUTF8Blended(text string, color sdl.Color) (*sdl.Surface, error) {
+	return f.render(text, func(font *ttf.Font, run string) (*sdl.Surface, error) {
+		return font.RenderUTF8Blended(run, color)
+	})
+}
+
+// Width of the rendered text (its runs side by side), to place a caret in it
+func (f *Font) TextWidth(text string) int32 {
+	var w int32
+	for _, run := range f.manager.runs(text) {
+		font, err := f.manager.open(run.face, f.size)
+		if err != nil {
+			return w
+		}
+		runWidth, _, err := font.SizeUTF8(run.text)
+		if err != nil {
+			return w
+		}
+		w += int32(runWidth)
+	}
+	return w
+}
+
+// Renders each run with its font, the runs are placed side by side on their baseline
+func (f *Font) render(text string, renderRun func(*ttf.Font, string) (*sdl.Surface, error)) (*sdl.Surface, error) {
+	runs := f.manager.runs(text)
+	if len(runs) == 0 {
+		runs = []textRun{{face: &f.manager.primary, text: text}}
+	}
+	if len(runs) == 1 {
+		font, err := f.manager.open(runs[0].face, f.size)
+		if err != nil {
+			return nil, err
+		}
+		return renderRun(font, runs[0].text)
+	}
+	surfaces := make([]*sdl.Surface, 0, len(runs))
+	ascents := make([]int32, 0, len(runs))
+	defer func() {
+		for _, surface := range surfaces {
+			surface.Free()
+		}
+	}()
+	var w, baseline, descent int32
+	for _, run := range runs {
+		font, err := f.manager.open(run.face, f.size)
+		if err != nil {
+			return nil, err
+		}
+		surface, err := renderRun(font, run.text)
+		if err != nil {
+			return nil, err
+		}
+		surfaces = append(surfaces, surface)
+		ascent := int32(font.Ascent())
+		ascents = append(ascents, ascent)
+		w += surface.W
+		if ascent > baseline {
+			baseline = ascent
+		}
+		if surface.H-ascent > descent {
+			descent = surface.H - ascent
+		}
+	}
+	result, err := sdl.CreateRGBSurfaceWithFormat(0, w, baseline+descent, 32, uint32(sdl.PIXELFORMAT_RGBA32))
+	if err != nil {
+		return nil, err
+	}
+	var x int32
+	for i, surface := range surfaces {
+		// the runs don't overlap, their pixels are copied as they are
+		surface.SetBlendMode(sdl.BLENDMODE_NONE)
+		surface.Blit(nil, result, &sdl.Rect{X: x, Y: baseline - ascents[i], W: surface.W, H: surface.H})
+		x += surface.W
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package rendering
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+type runeRange struct {
+	first rune
+	last  rune
+}
+
+// Characters having a glyph in a font, as sorted ranges
+type glyphCoverage []runeRange
+
+func (c glyphCoverage) has(r rune) bool {
+	i := sort.Search(len(c), func(i int) bool { return c[i].last >= r })
+	return i < len(c) && c[i].first <= r
+}
+
+// Adds the character, the characters must be added in increasing order
+func (c glyphCoverage) add(r rune) glyphCoverage {
+	if n := len(c); n > 0 && c[n-1].last+1 == r {
+		c[n-1].last = r
+		return c
+	}
+	return append(c, runeRange{first: r, last: r})
+}
+
+var errInvalidFont = errors.New("invalid font file")
+
+// Big endian reader of the font file, reading out of it sets err
+type fontReader struct {
+	data []byte
+	err  error
+}
+
+func (r *fontReader) u16(offset int) int {
+	if offset < 0 || offset+2 > len(r.data) {
+		r.err = errInvalidFont
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(r.data[offset:]))
+}
+
+func (r *fontReader) u32(offset int) int {
+	if offset < 0 || offset+4 > len(r.data) {
+		r.err = errInvalidFont
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(r.data[offset:]))
+}
+
+/*
+Reads the characters mapped to a glyph by the font's cmap table (TrueType/OpenType,
+the first font of a collection), from its unicode subtable in format 12 or 4
+*/
+func parseGlyphCoverage(data []byte) (glyphCoverage, error) {
+	r := &fontReader{data: data}
+	fontOffset := 0
+	if len(data) >= 4 && string(data[:4]) == "ttcf" {
+		fontOffset = r.u32(12)
+	}
+	cmap := -1
+	tableCount := r.u16(fontOffset + 4)
+	for i := 0; i < tableCount && r.err == nil; i++ {
+		record := fontOffset + 12 + 16*i
+		if record+4 <= len(data) && string(data[record:record+4]) == "cmap" {
+			cmap = r.u32(record + 8)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if cmap < 0 {
+		return nil, errors.New("font without cmap table")
+	}
+	format4, format12 := -1, -1
+	subtableCount := r.u16(cmap + 2)
+	for i := 0; i < subtableCount && r.err == nil; i++ {
+		platform, encoding := r.u16(cmap+4+8*i), r.u16(cmap+6+8*i)
+		offset := cmap + r.u32(cmap+8+8*i)
+		switch r.u16(offset) {
+		case 12:
+			if (platform == 3 && encoding == 10) || platform == 0 {
+				format12 = offset
+			}
+		case 4:
+			if (platform == 3 && encoding == 1) || platform == 0 {
+				format4 = offset
+			}
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	var coverage glyphCoverage
+	switch {
+	case format12 >= 0:
+		coverage = r.format12(format12)
+	case format4 >= 0:
+		coverage = r.format4(format4)
+	default:
+		return nil, errors.New("font without unicode cmap subtable")
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return coverage, nil
+}
+
+// Segmented coverage (32 bits characters)
+func (r *fontReader) format12(offset int) glyphCoverage {
+	var coverage glyphCoverage
+	groupCount := r.u32(offset + 12)
+	for i := 0; i < groupCount && r.err == nil; i++ {
+		group := offset + 16 + 12*i
+		first, last, glyph := r.u32(group), r.u32(group+4), r.u32(group+8)
+		if glyph == 0 {
+			first++ // mapped to the missing glyph
+		}
+		if first <= last && (len(coverage) == 0 || rune(first) > coverage[len(coverage)-1].last) {
+			coverage = append(coverage, runeRange{first: rune(first), last: rune(last)})
+		}
+	}
+	return coverage
+}
+
+// Segment mapping to delta values (16 bits characters)
+func (r *fontReader) format4(offset int) glyphCoverage {
+	var coverage glyphCoverage
+	segCount := r.u16(offset+6) / 2
+	ends := offset + 14
+	starts := ends + 2*segCount + 2
+	deltas := starts + 2*segCount
+	rangeOffsets := deltas + 2*segCount
+	for i := 0; i < segCount && r.err == nil; i++ {
+		first, last := r.u16(starts+2*i), r.u16(ends+2*i)
+		delta, rangeOffset := r.u16(deltas+2*i), r.u16(rangeOffsets+2*i)
+		for c := first; c <= last && r.err == nil; c++ {
+			glyph := 0
+			if rangeOffset == 0 {
+				glyph = (c + delta) & 0xffff
+			} else if glyph = r.u16(rangeOffsets + 2*i + rangeOffset + 2*(c-first)); glyph != 0 {
+				glyph = (glyph + delta) & 0xffff
+			}
+			if glyph != 0 && (len(coverage) == 0 || rune(c) > coverage[len(coverage)-1].last) {
+				coverage = coverage.add(rune(c))
+			}
+		}
+	}
+	return coverage
+}
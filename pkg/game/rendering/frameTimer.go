@@ -0,0 +1,55 @@
+package rendering
+
+import (
+	"log"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Measures how long the frames take to be drawn and logs the average/max every reportMS
+type FrameTimer struct {
+	name       string
+	reportMS   uint64
+	lastReport uint64
+	start      uint64
+	frames     uint64
+	total      uint64
+	max        uint64
+}
+
+func NewFrameTimer(name string, reportMS uint64) *FrameTimer {
+	return &FrameTimer{
+		name:       name,
+		reportMS:   reportMS,
+		lastReport: sdl.GetTicks64(),
+	}
+}
+
+func (t *FrameTimer) Start() {
+	t.start = sdl.GetPerformanceCounter()
+}
+
+func (t *FrameTimer) Stop() {
+	elapsed := sdl.GetPerformanceCounter() - t.start
+	t.frames += 1
+	t.total += elapsed
+	if elapsed > t.max {
+		t.max = elapsed
+	}
+	now := sdl.GetTicks64()
+	if now-t.lastReport >= t.reportMS {
+		t.report()
+		t.lastReport = now
+	}
+}
+
+func (t *FrameTimer) report() {
+	if t.frames == 0 {
+		return
+	}
+	freq := float64(sdl.GetPerformanceFrequency()) / 1000
+	log.Printf("%s: %d frames, avg %.3fms, max %.3fms\n", t.name, t.frames, float64(t.total)/float64(t.frames)/freq, float64(t.max)/freq)
+	t.frames = 0
+	t.total = 0
+	t.max = 0
+}
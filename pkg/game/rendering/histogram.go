@@ -0,0 +1,83 @@
+package rendering
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// Bars of the counts from left to right, scaled to the largest one, over an axis line
+type Histogram struct {
+	Rect      sdl.Rect
+	counts    []int
+	color     sdl.Color
+	axisColor sdl.Color
+}
+
+// gap between the bars, in pixels
+const histogramBarSpacing = 2
+
+func NewHistogram(w, h int32, counts []int, color, axisColor sdl.Color) *Histogram {
+	return &Histogram{
+		Rect:      sdl.Rect{X: 0, Y: 0, W: w, H: h},
+		counts:    counts,
+		color:     color,
+		axisColor: axisColor,
+	}
+}
+
+func (hg *Histogram) SetCounts(counts []int) {
+	hg.counts = counts
+}
+
+func (hg *Histogram) SetCenter(x int32, y int32) {
+	hg.Rect.X = x - hg.Rect.W/2
+	hg.Rect.Y = y - hg.Rect.H/2
+}
+func (hg *Histogram) SetTopLeft(x int32, y int32) {
+	hg.Rect.X = x
+	hg.Rect.Y = y
+}
+func (hg *Histogram) SetX(x int32, isCenter bool) {
+	if isCenter {
+		x -= hg.Rect.W / 2
+	}
+	hg.Rect.X = x
+}
+func (hg *Histogram) SetY(y int32, isCenter bool) {
+	if isCenter {
+		y -= hg.Rect.H / 2
+	}
+	hg.Rect.Y = y
+}
+func (hg *Histogram) Width() int32 {
+	return hg.Rect.W
+}
+func (hg *Histogram) Height() int32 {
+	return hg.Rect.H
+}
+
+func (hg *Histogram) Draw(r *CustomRenderer) {
+	bottom := hg.Rect.Y + hg.Rect.H - 1
+	r.SetDrawColor(hg.axisColor)
+	r.SDLrenderer.DrawLine(hg.Rect.X, bottom, hg.Rect.X+hg.Rect.W-1, bottom)
+	largest := 0
+	for _, count := range hg.counts {
+		if count > largest {
+			largest = count
+		}
+	}
+	if largest == 0 {
+		return
+	}
+	barWidth := hg.Rect.W / int32(len(hg.counts))
+	r.SetDrawColor(hg.color)
+	for i, count := range hg.counts {
+		h := int32(count) * (hg.Rect.H - 1) / int32(largest)
+		if h == 0 {
+			continue
+		}
+		r.SDLrenderer.FillRect(&sdl.Rect{
+			X: hg.Rect.X + int32(i)*barWidth + histogramBarSpacing/2,
+			Y: bottom - h,
+			W: barWidth - histogramBarSpacing,
+			H: h,
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package rendering
 
+import "github.com/veandco/go-sdl2/sdl"
+
 type Drawable interface {
 	Draw(r *CustomRenderer)
 }
@@ -20,4 +22,35 @@ type Selectable interface {
 	IsSelected() bool
 	SetSelectable(value bool)
 	IsSelectable() bool
+	// area on screen, for the spatial navigation
+	Bounds() sdl.Rect
+}
+
+/*
+Widget changing a value by itself (Slider, Checkbox, Dropdown, Stepper), reporting it through
+its OnChange callback. The scenes give it the mouse events, and the actions while it is focused
+*/
+type Control interface {
+	Selectable
+	// returns true if the event was used
+	ProcessMouse(e sdl.Event) bool
+	// arrows while focused, returns false to let the focus move
+	Step(dx, dy int) bool
+	// Confirm while focused
+	Activate()
+	// Menu while focused, returns false to let the scene handle it
+	Cancel() bool
+	// true while it takes all the mouse events and is drawn over the other widgets
+	Captures() bool
+	// for the repeats while held
+	Update(deltaMS uint64)
+	SetHovered(state bool)
+	Destroy()
+}
+
+// Control taking the keyboard while it is focused (TextField)
+type TextControl interface {
+	Control
+	// keyboard, text input and text editing events, returns false for the keys left to the scene
+	ProcessKey(e sdl.Event) bool
 }
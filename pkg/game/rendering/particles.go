@@ -0,0 +1,181 @@
+package rendering
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type particle struct {
+	x        float64 // world position (px)
+	y        float64
+	vx       float64 // velocity (px/s)
+	vy       float64
+	gravity  float64 // px/s²
+	ageMS    float64
+	lifeMS   float64
+	size     float64
+	color    sdl.Color
+	sprites  *Spritesheet // nil for a colored square
+	spriteID uint32
+}
+
+/*
+How the particles of an emission are generated, every value is picked randomly between its min and max.
+Angles are in radians (0 is right, -Pi/2 is up), positions and speeds in world pixels
+*/
+type ParticleConfig struct {
+	Count     int     // particles per burst (or per second for an emitter)
+	SpreadX   float64 // the particles start around the origin in a spreadX x spreadY rectangle
+	SpreadY   float64
+	SpeedMin  float64
+	SpeedMax  float64
+	AngleMin  float64
+	AngleMax  float64
+	Gravity   float64
+	LifeMinMS float64
+	LifeMaxMS float64
+	SizeMin   float64
+	SizeMax   float64
+	Colors    []sdl.Color // colored squares, used if there are no sprites
+	Sprites   *Spritesheet
+	SpriteIDs []uint32
+}
+
+// Emits the config's particles continuously during durationMS
+type ParticleEmitter struct {
+	X          float64
+	Y          float64
+	config     ParticleConfig
+	durationMS float64
+	elapsedMS  float64
+	pending    float64 // particles owed by the rate
+}
+
+// Particles living in world space, drawn with the same transform as the world
+type ParticleSystem struct {
+	particles []particle
+	emitters  []*ParticleEmitter
+	rand      *rand.Rand
+}
+
+func NewParticleSystem(seed int64) *ParticleSystem {
+	return &ParticleSystem{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (ps *ParticleSystem) between(min, max float64) float64 {
+	return min + (max-min)*ps.rand.Float64()
+}
+
+func (ps *ParticleSystem) spawn(x, y float64, config *ParticleConfig) {
+	angle := ps.between(config.AngleMin, config.AngleMax)
+	speed := ps.between(config.SpeedMin, config.SpeedMax)
+	p := particle{
+		x:       x + ps.between(-config.SpreadX/2, config.SpreadX/2),
+		y:       y + ps.between(-config.SpreadY/2, config.SpreadY/2),
+		vx:      math.Cos(angle) * speed,
+		vy:      math.Sin(angle) * speed,
+		gravity: config.Gravity,
+		lifeMS:  math.Max(1, ps.between(config.LifeMinMS, config.LifeMaxMS)),
+		size:    ps.between(config.SizeMin, config.SizeMax),
+		color:   ColorWhite,
+	}
+	if config.Sprites != nil && len(config.SpriteIDs) > 0 {
+		p.sprites = config.Sprites
+		p.spriteID = config.SpriteIDs[ps.rand.Intn(len(config.SpriteIDs))]
+	} else if len(config.Colors) > 0 {
+		p.color = config.Colors[ps.rand.Intn(len(config.Colors))]
+	}
+	ps.particles = append(ps.particles, p)
+}
+
+// Emits config.Count particles at once
+func (ps *ParticleSystem) Burst(x, y float64, config ParticleConfig) {
+	for i := 0; i < config.Count; i += 1 {
+		ps.spawn(x, y, &config)
+	}
+}
+
+// Emits config.Count particles per second during durationMS, the emitter can be moved while it runs
+func (ps *ParticleSystem) Emit(x, y float64, config ParticleConfig, durationMS float64) *ParticleEmitter {
+	emitter := &ParticleEmitter{X: x, Y: y, config: config, durationMS: durationMS}
+	ps.emitters = append(ps.emitters, emitter)
+	return emitter
+}
+
+/*
+Moves the particles and runs the emitters, returns true if the particles drawn changed: while
+there is something to draw, and on the update removing the last ones (to draw them gone)
+*/
+func (ps *ParticleSystem) Update(deltaMS uint64) bool {
+	wasActive := ps.IsActive()
+	dt := float64(deltaMS)
+	emitters := ps.emitters[:0]
+	for _, emitter := range ps.emitters {
+		step := math.Min(dt, emitter.durationMS-emitter.elapsedMS)
+		emitter.elapsedMS += step
+		emitter.pending += float64(emitter.config.Count) * step / 1000
+		for ; emitter.pending >= 1; emitter.pending -= 1 {
+			ps.spawn(emitter.X, emitter.Y, &emitter.config)
+		}
+		if emitter.elapsedMS < emitter.durationMS {
+			emitters = append(emitters, emitter)
+		}
+	}
+	ps.emitters = emitters
+
+	seconds := dt / 1000
+	alive := ps.particles[:0]
+	for _, p := range ps.particles {
+		p.ageMS += dt
+		if p.ageMS >= p.lifeMS {
+			continue
+		}
+		p.vy += p.gravity * seconds
+		p.x += p.vx * seconds
+		p.y += p.vy * seconds
+		alive = append(alive, p)
+	}
+	ps.particles = alive
+	return wasActive
+}
+
+func (ps *ParticleSystem) IsActive() bool {
+	return len(ps.particles) > 0 || len(ps.emitters) > 0
+}
+
+func (ps *ParticleSystem) Clear() {
+	ps.particles = ps.particles[:0]
+	ps.emitters = ps.emitters[:0]
+}
+
+// Draws the particles at world*scale + offset, fading out with their age
+func (ps *ParticleSystem) Draw(r *CustomRenderer, offsetX, offsetY, scale float64) {
+	if len(ps.particles) == 0 {
+		return
+	}
+	r.SDLrenderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	for _, p := range ps.particles {
+		size := math.Max(1, p.size*scale)
+		rect := sdl.Rect{
+			X: int32(p.x*scale + offsetX - size/2),
+			Y: int32(p.y*scale + offsetY - size/2),
+			W: int32(size),
+			H: int32(size),
+		}
+		alpha := uint8(255 * (1 - p.ageMS/p.lifeMS))
+		if p.sprites != nil {
+			p.sprites.SelectSprite(p.spriteID)
+			p.sprites.SetAlpha(alpha)
+			p.sprites.Draw(r, rect)
+			p.sprites.SetAlpha(255)
+		} else {
+			color := p.color
+			color.A = uint8(uint32(color.A) * uint32(alpha) / 255)
+			r.SetDrawColor(color)
+			r.SDLrenderer.FillRect(&rect)
+		}
+	}
+	r.SDLrenderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+}
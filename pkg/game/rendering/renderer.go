@@ -5,7 +5,6 @@ import (
 
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/veandco/go-sdl2/ttf"
 )
 
 type CustomRenderer struct {
@@ -54,7 +53,7 @@ func (r *CustomRenderer) SetDrawColor(color sdl.Color) {
 	r.SDLrenderer.SetDrawColor(color.R, color.G, color.B, color.A)
 }
 
-func (r *CustomRenderer) NewTextTexture(text string, font *ttf.Font, color sdl.Color) (*sdl.Texture, error) {
+func (r *CustomRenderer) NewTextTexture(text string, font *Font, color sdl.Color) (*sdl.Texture, error) {
 	if text != "" {
 		surface, err := font.RenderUTF8Blended(text, color)
 		if err != nil {
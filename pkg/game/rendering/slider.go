@@ -0,0 +1,119 @@
+package rendering
+
+import "github.com/veandco/go-sdl2/sdl"
+
+/*
+Value between Min and Max on a track, changed by dragging the knob (or clicking the track)
+and by Increment with the arrows while focused. A click on the label only focuses it
+*/
+type Slider struct {
+	control
+	Value     int
+	Min       int
+	Max       int
+	Increment int
+	textOf    func(value int) string
+	OnChange  func(value int) // called when the player changes the value
+	dragging  bool
+}
+
+// length of the track, in lines of text
+const sliderTrackLines = 8
+
+func NewSlider(renderer *CustomRenderer, font *Font, min, max, step, value int, textOf func(value int) string, color, backgroundColor, hoverColor sdl.Color) *Slider {
+	s := &Slider{
+		control:   newControl(renderer, font, color, backgroundColor, hoverColor),
+		Min:       min,
+		Max:       max,
+		Increment: step,
+		textOf:    textOf,
+	}
+	h := s.lineHeight()
+	s.setGraphicsSize(sliderTrackLines*h, h)
+	s.SetValue(value)
+	return s
+}
+
+// Sets the value without calling OnChange
+func (s *Slider) SetValue(value int) {
+	s.Value = clampInt(value, s.Min, s.Max)
+	s.setText(s.textOf(s.Value))
+}
+
+func (s *Slider) change(value int) {
+	previous := s.Value
+	s.SetValue(value)
+	if s.Value != previous && s.OnChange != nil {
+		s.OnChange(s.Value)
+	}
+}
+
+// value under the x position on the track, rounded to a step
+func (s *Slider) valueAt(x int32) int {
+	track := s.graphicsRect()
+	if track.W <= 0 || s.Max <= s.Min {
+		return s.Min
+	}
+	ratio := float64(x-track.X) / float64(track.W)
+	steps := int(ratio*float64(s.Max-s.Min)/float64(s.Increment) + 0.5)
+	return s.Min + steps*s.Increment
+}
+
+func (s *Slider) ProcessMouse(e sdl.Event) bool {
+	switch t := e.(type) {
+	case *sdl.MouseMotionEvent:
+		s.hovered = s.OnControl(sdl.Point{X: t.X, Y: t.Y})
+		if s.dragging {
+			s.change(s.valueAt(t.X))
+			return true
+		}
+	case *sdl.MouseButtonEvent:
+		if t.Button != sdl.BUTTON_LEFT {
+			return false
+		}
+		pos := sdl.Point{X: t.X, Y: t.Y}
+		if t.State == sdl.PRESSED && s.OnControl(pos) {
+			// a press on the label only focuses the slider
+			if track := s.graphicsRect(); pos.InRect(&track) {
+				s.dragging = true
+				s.change(s.valueAt(t.X))
+			}
+			return true
+		}
+		if t.State == sdl.RELEASED && s.dragging {
+			s.dragging = false
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Slider) Step(dx, dy int) bool {
+	if dx == 0 {
+		return false
+	}
+	s.change(s.Value + dx*s.Increment)
+	return true
+}
+
+func (s *Slider) Activate() {}
+
+// the drag keeps the mouse until the button is released, even out of the slider
+func (s *Slider) Captures() bool {
+	return s.dragging
+}
+
+func (s *Slider) Draw(r *CustomRenderer) {
+	s.drawBase(r)
+	track := s.graphicsRect()
+	line := sdl.Rect{X: track.X, Y: track.Y + track.H*3/8, W: track.W, H: track.H / 4}
+	r.SetDrawColor(s.color)
+	r.SDLrenderer.DrawRect(&line)
+	if s.Max > s.Min {
+		line.W = int32(int64(track.W) * int64(s.Value-s.Min) / int64(s.Max-s.Min))
+	}
+	r.SDLrenderer.FillRect(&line)
+	knob := sdl.Rect{X: track.X + line.W - track.H/4, Y: track.Y, W: track.H / 2, H: track.H}
+	r.SDLrenderer.FillRect(&knob)
+	s.drawFrame(r)
+}
@@ -3,21 +3,45 @@ package rendering
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// names of the usual sprite variants
+const (
+	VariantHover   = "hover"
+	VariantPressed = "pressed"
+)
+
+// Sprite of the sheet, its destination is precomputed relative to the cell it is drawn in
+type spriteFrame struct {
+	src sdl.Rect
+	// position and size of the destination in cell units (0;0;1;1 is the whole cell)
+	destX    float64
+	destY    float64
+	destW    float64
+	destH    float64
+	fitsCell bool // the sprite is drawn exactly on the cell
+	variants map[string]uint32
+}
+
+/*
+Texture holding sprites, either on a uniform grid (named by their index) or placed by an atlas.
+
+The sprites are selected by id, the names (and the variants) are resolved to ids once so
+drawing stays a single copy
+*/
 type Spritesheet struct {
-	spriteRect        sdl.Rect
-	currentSpriteRect sdl.Rect
-	currentSpriteId   uint32
-	columnCount       uint32
-	rowCount          uint32
-	texture           *sdl.Texture
+	frames          []spriteFrame
+	names           map[string]uint32
+	cell            sdl.Rect // reference size, drawn to the destination rect of Draw
+	currentSpriteId uint32
+	texture         *sdl.Texture
 }
 
-func NewSpritesheet(renderer *CustomRenderer, filePath string, columnCount, rowCount int32) (*Spritesheet, error) {
+func loadTexture(renderer *CustomRenderer, filePath string) (*sdl.Texture, error) {
 	surf, err := img.Load(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("surface creation error: %s", err)
@@ -27,42 +51,149 @@ func NewSpritesheet(renderer *CustomRenderer, filePath string, columnCount, rowC
 	if err != nil {
 		return nil, fmt.Errorf("texture creation error: %s", err)
 	}
+	return texture, nil
+}
+
+// Spritesheet cut in a grid of columnCount x rowCount sprites, named by their index (row by row)
+func NewSpritesheet(renderer *CustomRenderer, filePath string, columnCount, rowCount int32) (*Spritesheet, error) {
+	texture, err := loadTexture(renderer, filePath)
+	if err != nil {
+		return nil, err
+	}
 	_, _, w, h, _ := texture.Query()
-	spriteRect := sdl.Rect{
+	cell := sdl.Rect{
 		X: 0,
 		Y: 0,
 		W: w / columnCount,
 		H: h / rowCount,
 	}
-	return &Spritesheet{
-		spriteRect:        spriteRect,
-		columnCount:       uint32(columnCount),
-		rowCount:          uint32(rowCount),
-		texture:           texture,
-		currentSpriteRect: spriteRect,
-		currentSpriteId:   0,
-	}, nil
+	s := &Spritesheet{
+		frames:  make([]spriteFrame, 0, columnCount*rowCount),
+		names:   map[string]uint32{},
+		cell:    cell,
+		texture: texture,
+	}
+	for row := int32(0); row < rowCount; row++ {
+		for column := int32(0); column < columnCount; column++ {
+			s.addFrame(strconv.Itoa(len(s.frames)), sdl.Rect{X: column * cell.W, Y: row * cell.H, W: cell.W, H: cell.H}, sdl.Point{})
+		}
+	}
+	return s, nil
+}
+
+// Adds a sprite, pivot is the position in the sprite of the cell's top-left corner
+func (s *Spritesheet) addFrame(name string, src sdl.Rect, pivot sdl.Point) uint32 {
+	id := uint32(len(s.frames))
+	frame := spriteFrame{
+		src:   src,
+		destX: -float64(pivot.X) / float64(s.cell.W),
+		destY: -float64(pivot.Y) / float64(s.cell.H),
+		destW: float64(src.W) / float64(s.cell.W),
+		destH: float64(src.H) / float64(s.cell.H),
+	}
+	frame.fitsCell = pivot.X == 0 && pivot.Y == 0 && src.W == s.cell.W && src.H == s.cell.H
+	s.frames = append(s.frames, frame)
+	s.names[name] = id
+	return id
+}
+
+// Sets the sprite's variant to another sprite
+func (s *Spritesheet) SetVariant(id uint32, variant string, variantId uint32) error {
+	if id >= uint32(len(s.frames)) || variantId >= uint32(len(s.frames)) {
+		return errors.New("invalid id")
+	}
+	if s.frames[id].variants == nil {
+		s.frames[id].variants = map[string]uint32{}
+	}
+	s.frames[id].variants[variant] = variantId
+	return nil
+}
+
+// Gives each sprite of a grid the variant found offset sprites further (when it exists)
+func (s *Spritesheet) SetGridVariant(variant string, offset uint32) {
+	if offset == 0 {
+		return
+	}
+	for id := uint32(0); id+offset < uint32(len(s.frames)); id++ {
+		s.SetVariant(id, variant, id+offset)
+	}
+}
+
+func (s *Spritesheet) SpriteCount() uint32 {
+	return uint32(len(s.frames))
+}
+
+func (s *Spritesheet) SpriteId(name string) (uint32, error) {
+	id, found := s.names[name]
+	if !found {
+		return 0, fmt.Errorf("unknown sprite %q", name)
+	}
+	return id, nil
+}
+
+// Id of the sprite's variant, the sprite itself if it doesn't have it
+func (s *Spritesheet) VariantId(id uint32, variant string) uint32 {
+	if id < uint32(len(s.frames)) {
+		if variantId, found := s.frames[id].variants[variant]; found {
+			return variantId
+		}
+	}
+	return id
 }
 
 func (s *Spritesheet) SelectSprite(id uint32) error {
 	if id == s.currentSpriteId {
 		return nil
 	}
-	if id > s.columnCount*s.rowCount {
+	if id >= uint32(len(s.frames)) {
 		return errors.New("invalid id")
 	}
 	s.currentSpriteId = id
-	s.updateCurrentSpriteRect()
 	return nil
 }
 
-func (s *Spritesheet) updateCurrentSpriteRect() {
-	column := s.currentSpriteId % s.columnCount
-	row := (s.currentSpriteId - column) / s.columnCount
-	s.currentSpriteRect.X = int32(column) * s.spriteRect.W
-	s.currentSpriteRect.Y = int32(row) * s.spriteRect.H
+// Selects by name, prefer resolving the name once with SpriteId when drawing often
+func (s *Spritesheet) SelectSpriteName(name string) error {
+	id, err := s.SpriteId(name)
+	if err != nil {
+		return err
+	}
+	return s.SelectSprite(id)
+}
+
+// Selects the variant of the named sprite, or the sprite if it doesn't have it
+func (s *Spritesheet) SelectSpriteVariant(name, variant string) error {
+	id, err := s.SpriteId(name)
+	if err != nil {
+		return err
+	}
+	return s.SelectSprite(s.VariantId(id, variant))
 }
 
+// Draws the selected sprite on the cell dest, scaled and placed by its pivot
 func (s *Spritesheet) Draw(r *CustomRenderer, dest sdl.Rect) {
-	r.SDLrenderer.Copy(s.texture, &s.currentSpriteRect, &dest)
+	frame := &s.frames[s.currentSpriteId]
+	if frame.fitsCell {
+		r.SDLrenderer.Copy(s.texture, &frame.src, &dest)
+		return
+	}
+	w, h := float64(dest.W), float64(dest.H)
+	r.SDLrenderer.Copy(s.texture, &frame.src, &sdl.Rect{
+		X: dest.X + int32(frame.destX*w),
+		Y: dest.Y + int32(frame.destY*h),
+		W: int32(frame.destW * w),
+		H: int32(frame.destH * h),
+	})
+}
+
+// Transparency of the next draws (255 is opaque)
+func (s *Spritesheet) SetAlpha(alpha uint8) {
+	s.texture.SetAlphaMod(alpha)
+}
+
+func (s *Spritesheet) Destroy() {
+	if s.texture != nil {
+		s.texture.Destroy()
+		s.texture = nil
+	}
 }
@@ -0,0 +1,139 @@
+package rendering
+
+import "github.com/veandco/go-sdl2/sdl"
+
+/*
+Integer between Min and Max with "-" and "+" buttons, which repeat while they are held.
+The arrows change it by Increment while it is focused
+*/
+type Stepper struct {
+	control
+	Value     int
+	Min       int
+	Max       int
+	Increment int
+	textOf    func(value int) string
+	OnChange  func(value int) // called when the player changes the value
+	holding   int             // -1 or 1 while a button is held
+	holdMS    uint64
+}
+
+const (
+	stepperRepeatDelayMS    = 400 // before the first repeat
+	stepperRepeatIntervalMS = 80
+)
+
+func NewStepper(renderer *CustomRenderer, font *Font, min, max, step, value int, textOf func(value int) string, color, backgroundColor, hoverColor sdl.Color) *Stepper {
+	s := &Stepper{
+		control:   newControl(renderer, font, color, backgroundColor, hoverColor),
+		Min:       min,
+		Max:       max,
+		Increment: step,
+		textOf:    textOf,
+	}
+	h := s.lineHeight()
+	// the two buttons with a quarter of a line between them
+	s.setGraphicsSize(2*h+h/4, h)
+	s.SetValue(value)
+	return s
+}
+
+// Sets the value without calling OnChange
+func (s *Stepper) SetValue(value int) {
+	s.Value = clampInt(value, s.Min, s.Max)
+	s.setText(s.textOf(s.Value))
+}
+
+func (s *Stepper) change(direction int) {
+	previous := s.Value
+	s.SetValue(s.Value + direction*s.Increment)
+	if s.Value != previous && s.OnChange != nil {
+		s.OnChange(s.Value)
+	}
+}
+
+// areas of the "-" and "+" buttons
+func (s *Stepper) buttons() (sdl.Rect, sdl.Rect) {
+	area := s.graphicsRect()
+	minus := sdl.Rect{X: area.X, Y: area.Y, W: area.H, H: area.H}
+	plus := sdl.Rect{X: area.X + area.W - area.H, Y: area.Y, W: area.H, H: area.H}
+	return minus, plus
+}
+
+func (s *Stepper) ProcessMouse(e sdl.Event) bool {
+	switch t := e.(type) {
+	case *sdl.MouseMotionEvent:
+		s.hovered = s.OnControl(sdl.Point{X: t.X, Y: t.Y})
+	case *sdl.MouseButtonEvent:
+		if t.Button != sdl.BUTTON_LEFT {
+			return false
+		}
+		if t.State == sdl.RELEASED {
+			used := s.holding != 0
+			s.holding = 0
+			return used
+		}
+		pos := sdl.Point{X: t.X, Y: t.Y}
+		minus, plus := s.buttons()
+		if pos.InRect(&minus) {
+			s.holding = -1
+		} else if pos.InRect(&plus) {
+			s.holding = 1
+		} else {
+			return s.OnControl(pos)
+		}
+		s.holdMS = 0
+		s.change(s.holding)
+		return true
+	}
+	return false
+}
+
+func (s *Stepper) Step(dx, dy int) bool {
+	if dx == 0 {
+		return false
+	}
+	s.change(dx)
+	return true
+}
+
+func (s *Stepper) Activate() {}
+
+func (s *Stepper) Captures() bool {
+	return s.holding != 0
+}
+
+// Repeats the held button
+func (s *Stepper) Update(deltaMS uint64) {
+	if s.holding == 0 {
+		return
+	}
+	s.holdMS += deltaMS
+	for s.holdMS >= stepperRepeatDelayMS {
+		s.holdMS -= stepperRepeatIntervalMS
+		s.change(s.holding)
+	}
+}
+
+func (s *Stepper) Draw(r *CustomRenderer) {
+	s.drawBase(r)
+	minus, plus := s.buttons()
+	for _, button := range []struct {
+		rect    sdl.Rect
+		enabled bool
+		plus    bool
+	}{{minus, s.Value > s.Min, false}, {plus, s.Value < s.Max, true}} {
+		r.SetDrawColor(s.color)
+		r.SDLrenderer.DrawRect(&button.rect)
+		if !button.enabled {
+			// only the frame at the bounds
+			continue
+		}
+		x, y, size := button.rect.X+button.rect.W/2, button.rect.Y+button.rect.H/2, button.rect.W/4
+		r.SDLrenderer.DrawLine(x-size, y, x+size, y)
+		if button.plus {
+			r.SDLrenderer.DrawLine(x, y-size, x, y+size)
+		}
+	}
+	s.drawFrame(r)
+}
@@ -0,0 +1,507 @@
+package rendering
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type TableColumn struct {
+	Title    string
+	Sortable bool
+}
+
+type TableRow struct {
+	Cells []string
+	// sort keys of the cells (like a time shown as "1:05"), nil to sort on the texts
+	Values []float64
+}
+
+/*
+Rows of cells shown a few at a time, scrolled with the mouse wheel, the scrollbar (dragged
+or clicked) and the selection. The rows are clipped by the renderer's clip rect.
+
+A row is selected by a click or the up and down directions, activated by a double click or
+Confirm. A table has a header: a click on a sortable column's title sorts the rows by it
+(twice reverses the order), the left and right directions change the sorted column.
+NewList makes a table of a single column without a header
+*/
+type Table struct {
+	control
+	columns        []TableColumn
+	header         bool
+	rows           []TableRow
+	order          []int // indices of the rows, in the shown order
+	cells          [][]*sdl.Texture
+	cellSizes      [][]sdl.Point
+	titles         []*sdl.Texture
+	titleSizes     []sdl.Point
+	widths         []int32 // of the columns
+	visibleRows    int
+	rowHeight      int32
+	scroll         int32 // pixels of the rows above the visible area
+	selectedRow    int   // index in rows, -1 if none
+	sortColumn     int   // -1 if not sorted
+	sortDescending bool
+	OnSelect       func(row int) // index of the selected row in the rows given to SetRows
+	OnActivate     func(row int)
+	draggingBar    bool
+	dragOffset     int32 // between the mouse and the top of the scrollbar's thumb
+}
+
+const (
+	tableWheelRows   = 3 // rows scrolled by a step of the mouse wheel
+	tableCellPadding = 1 // on each side of a cell, in quarters of a line
+)
+
+func NewTable(renderer *CustomRenderer, font *Font, columns []TableColumn, rows []TableRow, visibleRows int, color, backgroundColor, hoverColor sdl.Color) *Table {
+	t := &Table{
+		control:     newControl(renderer, font, color, backgroundColor, hoverColor),
+		columns:     columns,
+		header:      true,
+		visibleRows: visibleRows,
+		selectedRow: -1,
+		sortColumn:  -1,
+	}
+	t.rowHeight = t.lineHeight() + t.lineHeight()/2
+	t.titles = make([]*sdl.Texture, len(columns))
+	t.titleSizes = make([]sdl.Point, len(columns))
+	for i, column := range columns {
+		t.titles[i], t.titleSizes[i] = t.textTexture(column.Title)
+	}
+	t.SetRows(rows)
+	return t
+}
+
+// Table of a single column of texts, without header
+func NewList(renderer *CustomRenderer, font *Font, items []string, visibleRows int, color, backgroundColor, hoverColor sdl.Color) *Table {
+	rows := make([]TableRow, len(items))
+	for i, item := range items {
+		rows[i] = TableRow{Cells: []string{item}}
+	}
+	t := NewTable(renderer, font, []TableColumn{{}}, rows, visibleRows, color, backgroundColor, hoverColor)
+	t.header = false
+	t.updateSize()
+	return t
+}
+
+func (t *Table) textTexture(text string) (*sdl.Texture, sdl.Point) {
+	texture, err := t.renderer.NewTextTexture(text, t.font, t.color)
+	if err != nil {
+		log.Printf("table: %s\n", err)
+		return nil, sdl.Point{}
+	}
+	size := sdl.Point{}
+	if texture != nil {
+		_, _, size.X, size.Y, _ = texture.Query()
+	}
+	return texture, size
+}
+
+// Replaces the rows, they keep the sorted column. The selection stays on the same index if it still exists
+func (t *Table) SetRows(rows []TableRow) {
+	t.destroyCells()
+	t.rows = rows
+	t.cells = make([][]*sdl.Texture, len(rows))
+	t.cellSizes = make([][]sdl.Point, len(rows))
+	for i, row := range rows {
+		t.cells[i] = make([]*sdl.Texture, len(t.columns))
+		t.cellSizes[i] = make([]sdl.Point, len(t.columns))
+		for column := range t.columns {
+			if column < len(row.Cells) {
+				t.cells[i][column], t.cellSizes[i][column] = t.textTexture(row.Cells[column])
+			}
+		}
+	}
+	if t.selectedRow >= len(rows) {
+		t.selectedRow = -1
+	}
+	t.sortRows()
+	t.updateSize()
+}
+
+func (t *Table) Rows() []TableRow {
+	return t.rows
+}
+
+// the columns are as large as their largest cell, the sorted column's arrow included
+func (t *Table) updateSize() {
+	t.widths = make([]int32, len(t.columns))
+	for column := range t.columns {
+		if t.header {
+			t.widths[column] = t.titleSizes[column].X + t.lineHeight()
+		}
+		for _, sizes := range t.cellSizes {
+			if sizes[column].X > t.widths[column] {
+				t.widths[column] = sizes[column].X
+			}
+		}
+		t.widths[column] += 2 * tableCellPadding * t.lineHeight() / 4
+	}
+	var w int32
+	for _, width := range t.widths {
+		w += width
+	}
+	t.setGraphicsSize(w+t.scrollbarWidth(), t.headerHeight()+int32(t.visibleRows)*t.rowHeight)
+	t.clampScroll()
+}
+
+func (t *Table) scrollbarWidth() int32 {
+	return t.lineHeight() / 2
+}
+
+func (t *Table) headerHeight() int32 {
+	if !t.header {
+		return 0
+	}
+	return t.rowHeight
+}
+
+// area of the rows on screen
+func (t *Table) body() sdl.Rect {
+	area := t.graphicsRect()
+	return sdl.Rect{X: area.X, Y: area.Y + t.headerHeight(), W: area.W - t.scrollbarWidth(), H: area.H - t.headerHeight()}
+}
+
+func (t *Table) scrollbar() sdl.Rect {
+	body := t.body()
+	return sdl.Rect{X: body.X + body.W, Y: body.Y, W: t.scrollbarWidth(), H: body.H}
+}
+
+// area of the scrollbar's thumb, empty if every row is shown
+func (t *Table) thumb() sdl.Rect {
+	bar := t.scrollbar()
+	total := int32(len(t.rows)) * t.rowHeight
+	if total <= bar.H {
+		return sdl.Rect{}
+	}
+	h := bar.H * bar.H / total
+	if h < t.rowHeight/2 {
+		h = t.rowHeight / 2
+	}
+	y := bar.Y + (bar.H-h)*t.scroll/t.maxScroll()
+	return sdl.Rect{X: bar.X, Y: y, W: bar.W, H: h}
+}
+
+func (t *Table) maxScroll() int32 {
+	max := int32(len(t.rows))*t.rowHeight - int32(t.visibleRows)*t.rowHeight
+	if max < 0 {
+		return 0
+	}
+	return max
+}
+
+func (t *Table) clampScroll() {
+	if t.scroll > t.maxScroll() {
+		t.scroll = t.maxScroll()
+	}
+	if t.scroll < 0 {
+		t.scroll = 0
+	}
+}
+
+func (t *Table) ScrollBy(pixels int32) {
+	t.scroll += pixels
+	t.clampScroll()
+}
+
+// Sorts the rows by the column, twice in a row reverses the order
+func (t *Table) SortBy(column int) {
+	if column < 0 || column >= len(t.columns) || !t.columns[column].Sortable {
+		return
+	}
+	if column == t.sortColumn {
+		t.sortDescending = !t.sortDescending
+	} else {
+		t.sortColumn = column
+		t.sortDescending = false
+	}
+	t.sortRows()
+}
+
+func (t *Table) SortColumn() (int, bool) {
+	return t.sortColumn, t.sortDescending
+}
+
+func (t *Table) sortRows() {
+	t.order = make([]int, len(t.rows))
+	for i := range t.order {
+		t.order[i] = i
+	}
+	if t.sortColumn != -1 {
+		column := t.sortColumn
+		sort.SliceStable(t.order, func(i, j int) bool {
+			a, b := t.rows[t.order[i]], t.rows[t.order[j]]
+			if t.sortDescending {
+				a, b = b, a
+			}
+			if column < len(a.Values) && column < len(b.Values) {
+				return a.Values[column] < b.Values[column]
+			}
+			return strings.ToLower(cell(a, column)) < strings.ToLower(cell(b, column))
+		})
+	}
+	t.showSelected()
+}
+
+func cell(row TableRow, column int) string {
+	if column < len(row.Cells) {
+		return row.Cells[column]
+	}
+	return ""
+}
+
+// position of the row in the shown order, -1 if none
+func (t *Table) position(row int) int {
+	for i, index := range t.order {
+		if index == row {
+			return i
+		}
+	}
+	return -1
+}
+
+// Index of the selected row in the rows given to SetRows, -1 if none
+func (t *Table) SelectedRow() int {
+	return t.selectedRow
+}
+
+// Selects the row (-1 for none) and scrolls to it, without calling OnSelect
+func (t *Table) SelectRow(row int) {
+	if row < -1 || row >= len(t.rows) {
+		return
+	}
+	t.selectedRow = row
+	t.showSelected()
+}
+
+func (t *Table) selectPosition(position int) {
+	row := t.order[clampInt(position, 0, len(t.order)-1)]
+	if row == t.selectedRow {
+		return
+	}
+	t.SelectRow(row)
+	if t.OnSelect != nil {
+		t.OnSelect(row)
+	}
+}
+
+// scrolls the least to show the selected row
+func (t *Table) showSelected() {
+	position := t.position(t.selectedRow)
+	if position == -1 {
+		return
+	}
+	top := int32(position) * t.rowHeight
+	if top < t.scroll {
+		t.scroll = top
+	} else if bottom := top + t.rowHeight - int32(t.visibleRows)*t.rowHeight; bottom > t.scroll {
+		t.scroll = bottom
+	}
+	t.clampScroll()
+}
+
+// scrolls so the thumb's top follows the mouse
+func (t *Table) dragBar(y int32) {
+	bar, thumb := t.scrollbar(), t.thumb()
+	if bar.H <= thumb.H {
+		return
+	}
+	t.scroll = (y - t.dragOffset - bar.Y) * t.maxScroll() / (bar.H - thumb.H)
+	t.clampScroll()
+}
+
+func (t *Table) ProcessMouse(e sdl.Event) bool {
+	switch ev := e.(type) {
+	case *sdl.MouseMotionEvent:
+		t.hovered = t.OnControl(sdl.Point{X: ev.X, Y: ev.Y})
+		if t.draggingBar {
+			t.dragBar(ev.Y)
+			return true
+		}
+	case *sdl.MouseWheelEvent:
+		if !t.hovered {
+			return false
+		}
+		t.ScrollBy(-ev.Y * tableWheelRows * t.rowHeight)
+		return true
+	case *sdl.MouseButtonEvent:
+		if ev.Button != sdl.BUTTON_LEFT {
+			return false
+		}
+		if ev.State == sdl.RELEASED {
+			used := t.draggingBar
+			t.draggingBar = false
+			return used
+		}
+		return t.click(sdl.Point{X: ev.X, Y: ev.Y}, ev.Clicks)
+	}
+	return false
+}
+
+func (t *Table) click(pos sdl.Point, clicks uint8) bool {
+	if !t.OnControl(pos) {
+		return false
+	}
+	bar, thumb, body := t.scrollbar(), t.thumb(), t.body()
+	switch {
+	case pos.InRect(&thumb):
+		t.draggingBar = true
+		t.dragOffset = pos.Y - thumb.Y
+	case pos.InRect(&bar):
+		// a page up or down
+		page := int32(t.visibleRows) * t.rowHeight
+		if pos.Y < thumb.Y {
+			page = -page
+		}
+		t.ScrollBy(page)
+	case pos.InRect(&body):
+		position := int((pos.Y - body.Y + t.scroll) / t.rowHeight)
+		if position >= len(t.order) {
+			return true
+		}
+		t.selectPosition(position)
+		if clicks >= 2 && t.OnActivate != nil {
+			t.OnActivate(t.selectedRow)
+		}
+	case t.header && pos.Y < body.Y:
+		x := body.X
+		for column, width := range t.widths {
+			if pos.X >= x && pos.X < x+width {
+				t.SortBy(column)
+				break
+			}
+			x += width
+		}
+	}
+	return true
+}
+
+/*
+Up and down move the selection (the focus leaves the table past its first or last row),
+left and right change the sorted column
+*/
+func (t *Table) Step(dx, dy int) bool {
+	if dy != 0 && len(t.order) > 0 {
+		position := t.position(t.selectedRow)
+		if position == -1 {
+			t.selectPosition(0)
+			return true
+		}
+		if position+dy < 0 || position+dy >= len(t.order) {
+			return false
+		}
+		t.selectPosition(position + dy)
+		return true
+	}
+	if dx != 0 && t.header {
+		for i := 1; i <= len(t.columns); i++ {
+			column := ((t.sortColumn+dx*i)%len(t.columns) + len(t.columns)) % len(t.columns)
+			if t.columns[column].Sortable {
+				t.sortColumn = column
+				t.sortDescending = false
+				t.sortRows()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *Table) Activate() {
+	if t.selectedRow != -1 && t.OnActivate != nil {
+		t.OnActivate(t.selectedRow)
+	}
+}
+
+func (t *Table) Captures() bool {
+	return t.draggingBar
+}
+
+func (t *Table) destroyCells() {
+	for _, row := range t.cells {
+		for _, texture := range row {
+			if texture != nil {
+				texture.Destroy()
+			}
+		}
+	}
+	t.cells = nil
+}
+
+func (t *Table) Destroy() {
+	t.destroyCells()
+	for _, texture := range t.titles {
+		if texture != nil {
+			texture.Destroy()
+		}
+	}
+	t.titles = nil
+	t.control.Destroy()
+}
+
+// draws the texture in the cell, vertically centered
+func (t *Table) drawCell(r *CustomRenderer, texture *sdl.Texture, size sdl.Point, x, y int32) {
+	if texture == nil {
+		return
+	}
+	padding := tableCellPadding * t.lineHeight() / 4
+	r.SDLrenderer.Copy(texture, nil, &sdl.Rect{X: x + padding, Y: y + (t.rowHeight-size.Y)/2, W: size.X, H: size.Y})
+}
+
+func (t *Table) Draw(r *CustomRenderer) {
+	t.drawBase(r)
+	body := t.body()
+	r.SetDrawColor(t.color)
+	if t.header {
+		x := body.X
+		for column, width := range t.widths {
+			t.drawCell(r, t.titles[column], t.titleSizes[column], x, body.Y-t.rowHeight)
+			if column == t.sortColumn {
+				// arrow pointing up for the ascending order, down for the descending one
+				size := t.lineHeight() / 4
+				arrowX, arrowY := x+width-2*size, body.Y-t.rowHeight/2
+				for i := int32(0); i < size; i++ {
+					y := arrowY - size/2 + i
+					if t.sortDescending {
+						y = arrowY + size/2 - i
+					}
+					r.SDLrenderer.DrawLine(arrowX-i, y, arrowX+i, y)
+				}
+			}
+			x += width
+		}
+		r.SDLrenderer.DrawLine(body.X, body.Y-1, body.X+body.W-1, body.Y-1)
+	}
+
+	previousClip := r.SDLrenderer.GetClipRect()
+	r.SDLrenderer.SetClipRect(&body)
+	first := int(t.scroll / t.rowHeight)
+	for position := first; position < len(t.order) && position <= first+t.visibleRows; position++ {
+		row := t.order[position]
+		y := body.Y + int32(position)*t.rowHeight - t.scroll
+		if row == t.selectedRow {
+			selection := sdl.Rect{X: body.X, Y: y, W: body.W, H: t.rowHeight}
+			r.SetDrawColor(t.hoverColor)
+			r.SDLrenderer.DrawRect(&selection)
+		}
+		x := body.X
+		for column, width := range t.widths {
+			t.drawCell(r, t.cells[row][column], t.cellSizes[row][column], x, y)
+			x += width
+		}
+	}
+	if previousClip.W > 0 && previousClip.H > 0 {
+		r.SDLrenderer.SetClipRect(&previousClip)
+	} else {
+		r.SDLrenderer.SetClipRect(nil)
+	}
+
+	bar, thumb := t.scrollbar(), t.thumb()
+	r.SetDrawColor(t.color)
+	r.SDLrenderer.DrawRect(&bar)
+	r.SDLrenderer.FillRect(&thumb)
+	t.drawFrame(r)
+}
@@ -0,0 +1,557 @@
+package rendering
+
+import (
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Single line text input, with a caret, a selection, the clipboard (Ctrl+C, Ctrl+X, Ctrl+V and
+Ctrl+A), a max length and a validation.
+
+The text comes from SDL's text input, started while the field is focused, so the keyboard
+layouts and the input methods work: the IME's composition is shown at the caret, underlined,
+until it is committed. The other keys (Tab, Enter, Escape, up and down) are left to the scene:
+Confirm calls OnSubmit
+*/
+type TextField struct {
+	control
+	runes       []rune
+	caret       int // in runes
+	anchor      int // other end of the selection, the caret's position if nothing is selected
+	composition []rune
+	compCaret   int // caret in the composition
+	MaxLength   int // in characters, 0 for no limit
+	// characters that can be typed, nil for all the printable ones
+	Accept func(r rune) bool
+	// error shown by the field (and given by Error) while the text isn't valid, nil to accept any text
+	Validate func(text string) error
+	OnChange func(text string) // called on each edit
+	OnSubmit func(text string) // called by Confirm, if the text is valid
+	err      error
+	// rendered text, with the composition
+	display        string
+	displayTexture *sdl.Texture
+	displaySize    sdl.Point
+	scroll         int32 // of the text in the box, to keep the caret visible
+	blinkMS        uint64
+	selecting      bool // dragging a selection with the mouse
+	editing        bool // SDL's text input is started
+	errorColor     sdl.Color
+}
+
+const (
+	caretBlinkMS = 500
+	// width of the box, in average characters
+	textFieldColumns = 16
+)
+
+func NewTextField(renderer *CustomRenderer, font *Font, label string, text string, maxLength int, color, backgroundColor, hoverColor sdl.Color) *TextField {
+	f := &TextField{
+		control:    newControl(renderer, font, color, backgroundColor, hoverColor),
+		MaxLength:  maxLength,
+		errorColor: sdl.Color{R: 220, G: 50, B: 50, A: sdl.ALPHA_OPAQUE},
+	}
+	f.setText(label)
+	h := f.lineHeight()
+	f.setGraphicsSize(textFieldColumns*font.TextWidth("m")+h/2, h+h/4)
+	f.SetValue(text)
+	return f
+}
+
+func (f *TextField) SetErrorColor(color sdl.Color) {
+	f.errorColor = color
+}
+
+// Replaces the text without calling OnChange, the caret goes to its end
+func (f *TextField) SetValue(text string) {
+	f.runes = f.filter([]rune(text))
+	if f.MaxLength > 0 && len(f.runes) > f.MaxLength {
+		f.runes = f.runes[:f.MaxLength]
+	}
+	f.caret = len(f.runes)
+	f.anchor = f.caret
+	f.composition = nil
+	f.validate()
+	f.updateDisplay()
+}
+
+func (f *TextField) Value() string {
+	return string(f.runes)
+}
+
+// Validation error of the text, nil if it is valid
+func (f *TextField) Error() error {
+	return f.err
+}
+
+func (f *TextField) validate() {
+	f.err = nil
+	if f.Validate != nil {
+		f.err = f.Validate(string(f.runes))
+	}
+}
+
+// keeps the accepted characters
+func (f *TextField) filter(runes []rune) []rune {
+	kept := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		if !unicode.IsPrint(r) || (f.Accept != nil && !f.Accept(r)) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+func (f *TextField) selection() (int, int) {
+	if f.anchor < f.caret {
+		return f.anchor, f.caret
+	}
+	return f.caret, f.anchor
+}
+
+func (f *TextField) hasSelection() bool {
+	return f.anchor != f.caret
+}
+
+func (f *TextField) SelectAll() {
+	f.anchor = 0
+	f.caret = len(f.runes)
+	f.updateDisplay()
+}
+
+// Replaces the selection by the text (filtered and cut to the max length)
+func (f *TextField) insert(text string) {
+	runes := f.filter([]rune(text))
+	start, end := f.selection()
+	if f.MaxLength > 0 {
+		room := f.MaxLength - (len(f.runes) - (end - start))
+		if room < 0 {
+			room = 0
+		}
+		if len(runes) > room {
+			runes = runes[:room]
+		}
+	}
+	if len(runes) == 0 && start == end {
+		return
+	}
+	edited := make([]rune, 0, len(f.runes)-(end-start)+len(runes))
+	edited = append(edited, f.runes[:start]...)
+	edited = append(edited, runes...)
+	edited = append(edited, f.runes[end:]...)
+	f.runes = edited
+	f.caret = start + len(runes)
+	f.anchor = f.caret
+	f.changed()
+}
+
+// Deletes the selection, or the character before (direction -1) or after (1) the caret
+func (f *TextField) delete(direction int) {
+	if !f.hasSelection() {
+		f.anchor = clampInt(f.caret+direction, 0, len(f.runes))
+	}
+	f.insert("")
+}
+
+func (f *TextField) changed() {
+	f.validate()
+	f.updateDisplay()
+	if f.OnChange != nil {
+		f.OnChange(string(f.runes))
+	}
+}
+
+// Moves the caret, extending the selection if selecting (Shift held)
+func (f *TextField) moveCaret(position int, selecting bool) {
+	f.caret = clampInt(position, 0, len(f.runes))
+	if !selecting {
+		f.anchor = f.caret
+	}
+	f.updateDisplay()
+}
+
+// start of the word before the caret (direction -1) or end of the word after it (1)
+func (f *TextField) wordEdge(direction int) int {
+	i := f.caret
+	if direction < 0 {
+		for i > 0 && unicode.IsSpace(f.runes[i-1]) {
+			i--
+		}
+		for i > 0 && !unicode.IsSpace(f.runes[i-1]) {
+			i--
+		}
+		return i
+	}
+	for i < len(f.runes) && unicode.IsSpace(f.runes[i]) {
+		i++
+	}
+	for i < len(f.runes) && !unicode.IsSpace(f.runes[i]) {
+		i++
+	}
+	return i
+}
+
+func (f *TextField) copySelection() {
+	if !f.hasSelection() {
+		return
+	}
+	start, end := f.selection()
+	if err := sdl.SetClipboardText(string(f.runes[start:end])); err != nil {
+		log.Printf("text field copy: %s\n", err)
+	}
+}
+
+func (f *TextField) paste() {
+	text, err := sdl.GetClipboardText()
+	if err != nil {
+		log.Printf("text field paste: %s\n", err)
+		return
+	}
+	// a single line
+	text = strings.Join(strings.Fields(text), " ")
+	f.insert(text)
+}
+
+/*
+Handles the keyboard, text input and IME composition events while the field is focused.
+
+Returns false for the keys left to the scene (Tab, Enter, Escape, up and down)
+*/
+func (f *TextField) ProcessKey(e sdl.Event) bool {
+	switch t := e.(type) {
+	case *sdl.TextInputEvent:
+		f.composition = nil
+		f.insert(t.GetText())
+		return true
+	case *sdl.TextEditingEvent:
+		f.composition = []rune(t.GetText())
+		f.compCaret = clampInt(int(t.Start), 0, len(f.composition))
+		f.updateDisplay()
+		return true
+	case *sdl.KeyboardEvent:
+		if len(f.composition) > 0 {
+			// the input method uses the keys while it composes
+			return true
+		}
+		if t.State != sdl.PRESSED {
+			return f.isEditingKey(t.Keysym)
+		}
+		return f.processKeyDown(t.Keysym)
+	}
+	return false
+}
+
+func shortcutMod(mod uint16) bool {
+	return mod&(sdl.KMOD_CTRL|sdl.KMOD_GUI) != 0
+}
+
+// keys used by the field, their releases are used too
+func (f *TextField) isEditingKey(key sdl.Keysym) bool {
+	switch key.Sym {
+	case sdl.K_LEFT, sdl.K_RIGHT, sdl.K_HOME, sdl.K_END, sdl.K_BACKSPACE, sdl.K_DELETE:
+		return true
+	case sdl.K_a, sdl.K_c, sdl.K_v, sdl.K_x:
+		// shortcuts or characters
+		return true
+	}
+	// the characters come as text input, their keys must not trigger the scene's actions
+	return key.Sym >= sdl.K_SPACE && key.Sym < sdl.K_DELETE && key.Mod&(sdl.KMOD_CTRL|sdl.KMOD_ALT|sdl.KMOD_GUI) == 0
+}
+
+func (f *TextField) processKeyDown(key sdl.Keysym) bool {
+	selecting := key.Mod&sdl.KMOD_SHIFT != 0
+	byWord := key.Mod&sdl.KMOD_CTRL != 0
+	switch key.Sym {
+	case sdl.K_LEFT:
+		if byWord {
+			f.moveCaret(f.wordEdge(-1), selecting)
+		} else if f.hasSelection() && !selecting {
+			start, _ := f.selection()
+			f.moveCaret(start, false)
+		} else {
+			f.moveCaret(f.caret-1, selecting)
+		}
+	case sdl.K_RIGHT:
+		if byWord {
+			f.moveCaret(f.wordEdge(1), selecting)
+		} else if f.hasSelection() && !selecting {
+			_, end := f.selection()
+			f.moveCaret(end, false)
+		} else {
+			f.moveCaret(f.caret+1, selecting)
+		}
+	case sdl.K_HOME:
+		f.moveCaret(0, selecting)
+	case sdl.K_END:
+		f.moveCaret(len(f.runes), selecting)
+	case sdl.K_BACKSPACE:
+		if byWord && !f.hasSelection() {
+			f.anchor = f.wordEdge(-1)
+		}
+		f.delete(-1)
+	case sdl.K_DELETE:
+		if byWord && !f.hasSelection() {
+			f.anchor = f.wordEdge(1)
+		}
+		f.delete(1)
+	case sdl.K_a, sdl.K_c, sdl.K_v, sdl.K_x:
+		if !shortcutMod(key.Mod) {
+			return true
+		}
+		switch key.Sym {
+		case sdl.K_a:
+			f.SelectAll()
+		case sdl.K_c:
+			f.copySelection()
+		case sdl.K_x:
+			f.copySelection()
+			f.delete(0)
+		case sdl.K_v:
+			f.paste()
+		}
+	default:
+		return f.isEditingKey(key)
+	}
+	f.blinkMS = 0
+	return true
+}
+
+// Starts SDL's text input while the field is focused
+func (f *TextField) Selected(value bool) {
+	f.control.Selected(value)
+	if f.selected && !f.editing {
+		f.editing = true
+		sdl.StartTextInput()
+		f.updateInputRect()
+	} else if !f.selected && f.editing {
+		f.editing = false
+		f.composition = nil
+		sdl.StopTextInput()
+		f.updateDisplay()
+	}
+}
+
+// area of the box's text on screen
+func (f *TextField) textRect() sdl.Rect {
+	box := f.graphicsRect()
+	inset := f.lineHeight() / 4
+	return sdl.Rect{X: box.X + inset, Y: box.Y, W: box.W - 2*inset, H: box.H}
+}
+
+// x of the position (in runes of the displayed text) in the text, without the scroll
+func (f *TextField) offsetOf(i int) int32 {
+	display := []rune(f.display)
+	return f.font.TextWidth(string(display[:clampInt(i, 0, len(display))]))
+}
+
+// position of the caret in the displayed text (after the composition's caret)
+func (f *TextField) displayCaret() int {
+	if len(f.composition) > 0 {
+		start, _ := f.selection()
+		return start + f.compCaret
+	}
+	return f.caret
+}
+
+// Renders the text with the composition (replacing the selection) and scrolls to the caret
+func (f *TextField) updateDisplay() {
+	display := f.runes
+	if len(f.composition) > 0 {
+		start, end := f.selection()
+		display = append(append(append([]rune{}, f.runes[:start]...), f.composition...), f.runes[end:]...)
+	}
+	if text := string(display); text != f.display || f.displayTexture == nil {
+		f.display = text
+		if f.displayTexture != nil {
+			f.displayTexture.Destroy()
+			f.displayTexture = nil
+		}
+		f.displaySize = sdl.Point{}
+		texture, err := f.renderer.NewTextTexture(text, f.font, f.color)
+		if err != nil {
+			log.Printf("text field: %s\n", err)
+		} else if texture != nil {
+			f.displayTexture = texture
+			_, _, f.displaySize.X, f.displaySize.Y, _ = texture.Query()
+		}
+	}
+	area := f.textRect()
+	caretX := f.offsetOf(f.displayCaret())
+	if caretX-f.scroll > area.W {
+		f.scroll = caretX - area.W
+	} else if caretX < f.scroll {
+		f.scroll = caretX
+	}
+	if f.displaySize.X-f.scroll < area.W {
+		// no empty space after the text when it was scrolled
+		f.scroll = f.displaySize.X - area.W
+	}
+	if f.scroll < 0 {
+		f.scroll = 0
+	}
+	f.updateInputRect()
+}
+
+// Tells SDL where the caret is, the input method's candidates are shown next to it
+func (f *TextField) updateInputRect() {
+	if !f.editing {
+		return
+	}
+	area := f.textRect()
+	rect := sdl.Rect{X: area.X + f.offsetOf(f.displayCaret()) - f.scroll, Y: area.Y, W: 1, H: area.H}
+	sdl.SetTextInputRect(&rect)
+}
+
+func (f *TextField) SetBackgroundSize(w int32, h int32) {
+	f.control.SetBackgroundSize(w, h)
+	f.updateInputRect()
+}
+
+func (f *TextField) SetTopLeft(x int32, y int32) {
+	f.control.SetTopLeft(x, y)
+	f.updateInputRect()
+}
+
+func (f *TextField) SetCenter(x int32, y int32) {
+	f.control.SetCenter(x, y)
+	f.updateInputRect()
+}
+
+// position in the text (in runes) under the x of the screen
+func (f *TextField) positionAt(x int32) int {
+	area := f.textRect()
+	x = x - area.X + f.scroll
+	for i := 1; i <= len(f.runes); i++ {
+		left, right := f.font.TextWidth(string(f.runes[:i-1])), f.font.TextWidth(string(f.runes[:i]))
+		if x < (left+right)/2 {
+			return i - 1
+		}
+	}
+	return len(f.runes)
+}
+
+func (f *TextField) ProcessMouse(e sdl.Event) bool {
+	switch t := e.(type) {
+	case *sdl.MouseMotionEvent:
+		f.hovered = f.OnControl(sdl.Point{X: t.X, Y: t.Y})
+		if f.selecting {
+			f.moveCaret(f.positionAt(t.X), true)
+			return true
+		}
+	case *sdl.MouseButtonEvent:
+		if t.Button != sdl.BUTTON_LEFT {
+			return false
+		}
+		if t.State == sdl.RELEASED {
+			used := f.selecting
+			f.selecting = false
+			return used
+		}
+		if !f.OnControl(sdl.Point{X: t.X, Y: t.Y}) || len(f.composition) > 0 {
+			return false
+		}
+		if t.Clicks >= 2 {
+			f.SelectAll()
+		} else {
+			f.selecting = true
+			f.moveCaret(f.positionAt(t.X), sdl.GetModState()&sdl.KMOD_SHIFT != 0)
+		}
+		f.blinkMS = 0
+		return true
+	}
+	return false
+}
+
+// The left and right directions move the caret (the keys come through ProcessKey), the focus leaves the field with Tab or up and down
+func (f *TextField) Step(dx, dy int) bool {
+	if dx == 0 {
+		return false
+	}
+	f.moveCaret(f.caret+dx, false)
+	return true
+}
+
+func (f *TextField) Activate() {
+	if f.err == nil && f.OnSubmit != nil {
+		f.OnSubmit(string(f.runes))
+	}
+}
+
+func (f *TextField) Cancel() bool {
+	if f.hasSelection() {
+		f.moveCaret(f.caret, false)
+		return true
+	}
+	return false
+}
+
+func (f *TextField) Captures() bool {
+	return f.selecting
+}
+
+// Blinks the caret
+func (f *TextField) Update(deltaMS uint64) {
+	f.blinkMS = (f.blinkMS + deltaMS) % (2 * caretBlinkMS)
+}
+
+func (f *TextField) Destroy() {
+	if f.editing {
+		f.editing = false
+		sdl.StopTextInput()
+	}
+	if f.displayTexture != nil {
+		f.displayTexture.Destroy()
+		f.displayTexture = nil
+	}
+	f.control.Destroy()
+}
+
+func (f *TextField) Draw(r *CustomRenderer) {
+	f.drawBase(r)
+	box := f.graphicsRect()
+	frameColor := f.color
+	if f.err != nil {
+		frameColor = f.errorColor
+	}
+	r.SetDrawColor(frameColor)
+	r.SDLrenderer.DrawRect(&box)
+
+	area := f.textRect()
+	previousClip := r.SDLrenderer.GetClipRect()
+	r.SDLrenderer.SetClipRect(&area)
+	x := area.X - f.scroll
+	y := area.Y + (area.H-f.lineHeight())/2
+	if f.hasSelection() && len(f.composition) == 0 {
+		start, end := f.selection()
+		left, right := f.offsetOf(start), f.offsetOf(end)
+		selection := sdl.Rect{X: x + left, Y: y, W: right - left, H: f.lineHeight()}
+		r.SetDrawColor(f.hoverColor)
+		r.SDLrenderer.FillRect(&selection)
+	}
+	if f.displayTexture != nil {
+		r.SDLrenderer.Copy(f.displayTexture, nil, &sdl.Rect{X: x, Y: y, W: f.displaySize.X, H: f.displaySize.Y})
+	}
+	r.SetDrawColor(f.color)
+	if len(f.composition) > 0 {
+		// the composition is underlined until the input method commits it
+		start, _ := f.selection()
+		left, right := f.offsetOf(start), f.offsetOf(start+len(f.composition))
+		underline := y + f.lineHeight() - 1
+		r.SDLrenderer.DrawLine(x+left, underline, x+right, underline)
+	}
+	if f.editing && f.blinkMS < caretBlinkMS {
+		caretX := x + f.offsetOf(f.displayCaret())
+		r.SDLrenderer.DrawLine(caretX, y, caretX, y+f.lineHeight()-1)
+	}
+	if previousClip.W > 0 && previousClip.H > 0 {
+		r.SDLrenderer.SetClipRect(&previousClip)
+	} else {
+		r.SDLrenderer.SetClipRect(nil)
+	}
+	f.drawFrame(r)
+}
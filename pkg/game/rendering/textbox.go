@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/veandco/go-sdl2/ttf"
 )
 
 type Textbox struct {
@@ -17,7 +16,7 @@ type Textbox struct {
 	centerY bool
 }
 
-func NewTextbox(rect sdl.Rect, centerX bool, centerY bool, text string, renderer *sdl.Renderer, font *ttf.Font, color sdl.Color) (*Textbox, error) {
+func NewTextbox(rect sdl.Rect, centerX bool, centerY bool, text string, renderer *sdl.Renderer, font *Font, color sdl.Color) (*Textbox, error) {
 	tbox := &Textbox{}
 	if text != "" {
 		surface, err := font.RenderUTF8Solid(text, color)
@@ -42,7 +41,7 @@ func NewTextbox(rect sdl.Rect, centerX bool, centerY bool, text string, renderer
 	return tbox, nil
 }
 
-func (tbox *Textbox) SetText(text string, renderer *sdl.Renderer, font *ttf.Font, color sdl.Color) error {
+func (tbox *Textbox) SetText(text string, renderer *sdl.Renderer, font *Font, color sdl.Color) error {
 	if text == tbox.Text {
 		return nil
 	}
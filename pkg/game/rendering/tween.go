@@ -0,0 +1,273 @@
+package rendering
+
+// Anything driven by the frames' delta time
+type Animation interface {
+	// Advances the animation, returns false once it is finished
+	Update(deltaMS uint64) bool
+	// Jumps to the end of the animation (the callbacks are still called)
+	Finish()
+	// Total duration (ms), delays included
+	Duration() uint64
+}
+
+/*
+Interpolates a value from From to To during DurationMS (after DelayMS).
+
+OnStart is called when the delay is over, OnUpdate on every update with the eased value
+and OnComplete once the value reached To
+*/
+type Tween struct {
+	From       float64
+	To         float64
+	DurationMS uint64
+	DelayMS    uint64
+	Easing     EasingFunc
+	OnStart    func()
+	OnUpdate   func(value float64)
+	OnComplete func()
+	elapsed    uint64
+	started    bool
+	done       bool
+}
+
+func NewTween(from, to float64, durationMS uint64, easing EasingFunc) *Tween {
+	if easing == nil {
+		easing = EaseLinear
+	}
+	return &Tween{
+		From:       from,
+		To:         to,
+		DurationMS: durationMS,
+		Easing:     easing,
+	}
+}
+
+func (t *Tween) Update(deltaMS uint64) bool {
+	if t.done {
+		return false
+	}
+	t.elapsed += deltaMS
+	if t.elapsed < t.DelayMS {
+		return true
+	}
+	if !t.started {
+		t.started = true
+		if t.OnStart != nil {
+			t.OnStart()
+		}
+	}
+	if t.elapsed-t.DelayMS >= t.DurationMS {
+		t.Finish()
+		return false
+	}
+	if t.OnUpdate != nil {
+		t.OnUpdate(t.Value())
+	}
+	return true
+}
+
+func (t *Tween) Finish() {
+	if t.done {
+		return
+	}
+	if !t.started {
+		t.started = true
+		if t.OnStart != nil {
+			t.OnStart()
+		}
+	}
+	t.elapsed = t.DelayMS + t.DurationMS
+	t.done = true
+	if t.OnUpdate != nil {
+		t.OnUpdate(t.To)
+	}
+	if t.OnComplete != nil {
+		t.OnComplete()
+	}
+}
+
+func (t *Tween) Duration() uint64 {
+	return t.DelayMS + t.DurationMS
+}
+
+// Progress of the tween (0 to 1) without easing
+func (t *Tween) Progress() float64 {
+	if t.elapsed <= t.DelayMS {
+		return 0
+	}
+	if t.DurationMS == 0 || t.elapsed-t.DelayMS >= t.DurationMS {
+		return 1
+	}
+	return float64(t.elapsed-t.DelayMS) / float64(t.DurationMS)
+}
+
+// Current eased value
+func (t *Tween) Value() float64 {
+	return t.From + (t.To-t.From)*t.Easing(t.Progress())
+}
+
+func (t *Tween) IsDone() bool {
+	return t.done
+}
+
+// Calls a function once
+type callback struct {
+	f    func()
+	done bool
+}
+
+func (c *callback) Update(deltaMS uint64) bool {
+	c.Finish()
+	return false
+}
+
+func (c *callback) Finish() {
+	if !c.done {
+		c.done = true
+		c.f()
+	}
+}
+
+func (c *callback) Duration() uint64 {
+	return 0
+}
+
+type timelineEntry struct {
+	startMS   uint64
+	fedMS     uint64
+	animation Animation
+	running   bool
+}
+
+// Animations started at given times, relative to the start of the timeline
+type Timeline struct {
+	entries    []*timelineEntry
+	elapsed    uint64
+	OnComplete func()
+	done       bool
+}
+
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Starts the animation atMS after the start of the timeline
+func (tl *Timeline) Add(atMS uint64, a Animation) *Timeline {
+	tl.entries = append(tl.entries, &timelineEntry{startMS: atMS, animation: a, running: true})
+	return tl
+}
+
+// Starts the animation when the timeline's current last animation ends
+func (tl *Timeline) Append(a Animation) *Timeline {
+	return tl.Add(tl.Duration(), a)
+}
+
+// Calls f atMS after the start of the timeline
+func (tl *Timeline) Call(atMS uint64, f func()) *Timeline {
+	return tl.Add(atMS, &callback{f: f})
+}
+
+func (tl *Timeline) Update(deltaMS uint64) bool {
+	if tl.done {
+		return false
+	}
+	tl.elapsed += deltaMS
+	running := false
+	for _, entry := range tl.entries {
+		if !entry.running {
+			continue
+		}
+		if tl.elapsed < entry.startMS {
+			running = true
+			continue
+		}
+		local := tl.elapsed - entry.startMS
+		entry.running = entry.animation.Update(local - entry.fedMS)
+		entry.fedMS = local
+		running = running || entry.running
+	}
+	if !running {
+		tl.complete()
+	}
+	return running
+}
+
+func (tl *Timeline) Finish() {
+	for _, entry := range tl.entries {
+		if entry.running {
+			entry.animation.Finish()
+			entry.running = false
+		}
+	}
+	tl.complete()
+}
+
+func (tl *Timeline) complete() {
+	if tl.done {
+		return
+	}
+	tl.done = true
+	if tl.OnComplete != nil {
+		tl.OnComplete()
+	}
+}
+
+func (tl *Timeline) Duration() uint64 {
+	var duration uint64
+	for _, entry := range tl.entries {
+		if end := entry.startMS + entry.animation.Duration(); end > duration {
+			duration = end
+		}
+	}
+	return duration
+}
+
+// Runs a set of independent animations
+type Animator struct {
+	animations []Animation
+}
+
+func (a *Animator) Add(animation Animation) {
+	a.animations = append(a.animations, animation)
+}
+
+/*
+Updates every animation and forgets the finished ones.
+Returns true if an animation ran during this update, the scene needs to be redrawn
+*/
+func (a *Animator) Update(deltaMS uint64) bool {
+	if len(a.animations) == 0 {
+		return false
+	}
+	// the animations' callbacks may add new animations
+	current := a.animations
+	a.animations = nil
+	running := current[:0]
+	for _, animation := range current {
+		if animation.Update(deltaMS) {
+			running = append(running, animation)
+		}
+	}
+	a.animations = append(running, a.animations...)
+	return true
+}
+
+func (a *Animator) IsRunning() bool {
+	return len(a.animations) > 0
+}
+
+// Jumps every animation to its end
+func (a *Animator) FinishAll() {
+	for len(a.animations) > 0 {
+		current := a.animations
+		a.animations = nil
+		for _, animation := range current {
+			animation.Finish()
+		}
+	}
+}
+
+// Drops every animation without calling their callbacks
+func (a *Animator) Clear() {
+	a.animations = nil
+}
@@ -0,0 +1,252 @@
+package scenes
+
+import (
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/theme"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Choice of a dialog closed with Escape (or B)
+const DialogCancelled = -1
+
+// alpha of the black drawn over the scene behind a dialog or an overlay
+const dialogDimAlpha = 160
+
+/*
+Modal popup shown over the current scene by SceneManager.ShowDialog: a title, a message (one
+line per "\n") and a row of buttons. It takes every input until one of its buttons is clicked
+or it is cancelled, then calls its callback with the index of the button (or DialogCancelled).
+
+The buttons are focused with the keyboard and the game controller like in the menus
+*/
+type Dialog struct {
+	title   string
+	message string
+	options []string
+	cancel  int // choice when cancelled
+	onClose func(choice int)
+	widgets []rendering.Widget // the title, the message's lines then the buttons
+	buttons []*rendering.Button
+	layout  *Layout
+	focus   *FocusManager
+	input   *input.Context
+}
+
+func newDialog(title, message string, options []string, cancel int, onClose func(choice int)) *Dialog {
+	return &Dialog{
+		title:   title,
+		message: message,
+		options: options,
+		cancel:  cancel,
+		onClose: onClose,
+		focus:   NewFocusManager(),
+		input:   input.NewContext(input.MenuBindings()),
+	}
+}
+
+// Yes/No question, cancelling answers no
+func NewConfirmDialog(title, message string, onClose func(confirmed bool)) *Dialog {
+	return newDialog(title, message, []string{lang.Text.Dialog.Yes, lang.Text.Dialog.No}, 1, func(choice int) {
+		if onClose != nil {
+			onClose(choice == 0)
+		}
+	})
+}
+
+// Message with an OK button, onClose may be nil
+func NewAlertDialog(title, message string, onClose func()) *Dialog {
+	return newDialog(title, message, []string{lang.Text.Dialog.Ok}, 0, func(int) {
+		if onClose != nil {
+			onClose()
+		}
+	})
+}
+
+// One button per option, onClose gets the index of the chosen one or DialogCancelled
+func NewChoiceDialog(title, message string, options []string, onClose func(choice int)) *Dialog {
+	return newDialog(title, message, options, DialogCancelled, onClose)
+}
+
+// Creates the widgets with the current theme, the first button is focused
+func (d *Dialog) build(renderer *rendering.CustomRenderer, font *rendering.Font) error {
+	lineHeight := int32(font.Height())
+	lines := []string{d.title, ""}
+	if d.message != "" {
+		lines = append(lines, strings.Split(d.message, "\n")...)
+	}
+	d.layout = NewVBox().WithSpacing(lineHeight / 4).WithPadding(UniformInsets(lineHeight))
+	for _, line := range lines {
+		tbox, err := rendering.NewTextbox(sdl.Rect{}, true, true, line, renderer.SDLrenderer, font, theme.Palette.Text)
+		if err != nil {
+			d.destroy()
+			return err
+		}
+		d.widgets = append(d.widgets, tbox)
+		d.layout.Add(NewWidgetLayout(tbox).WithMinSize(0, lineHeight))
+	}
+	buttons := NewHBox().WithSpacing(lineHeight / 2)
+	for i, option := range d.options {
+		btn := rendering.NewButton(
+			sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
+			true,
+			true,
+			true,
+			option,
+			rendering.ButtonActionId(i),
+			theme.Palette.Text,
+			&theme.Palette.ButtonBackground,
+			&theme.Palette.ButtonHoverMenu,
+		)
+		if err := btn.UpdateTexture(renderer.SDLrenderer, font); err != nil {
+			d.destroy()
+			return err
+		}
+		d.widgets = append(d.widgets, btn)
+		d.buttons = append(d.buttons, btn)
+		buttons.Add(NewWidgetLayout(btn).WithPadding(UniformInsets(lineHeight / 2)))
+	}
+	d.layout.Add(buttons.WithAlign(AlignCenter, AlignCenter).WithMargin(Insets{Top: lineHeight / 2}))
+	d.focus.SetWidgets(d.widgets)
+	d.focus.Focus(len(d.widgets) - len(d.buttons))
+	return nil
+}
+
+func (d *Dialog) destroy() {
+	for _, widget := range d.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		} else if tbox, ok := widget.(*rendering.Textbox); ok {
+			tbox.Destroy()
+		}
+	}
+	d.widgets = nil
+	d.buttons = nil
+}
+
+// Centers the dialog on the screen
+func (d *Dialog) resize(w, h int32) {
+	d.layout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h})
+}
+
+/*
+Handles the event, returns true once a choice is made: the scene manager then closes the
+dialog and calls its callback with the choice
+*/
+func (d *Dialog) processEvent(e sdl.Event) (bool, int) {
+	if t, ok := e.(*sdl.MouseButtonEvent); ok && t.State == sdl.PRESSED && t.Button == sdl.BUTTON_LEFT {
+		for i, btn := range d.buttons {
+			if btn.OnButton(sdl.Point{X: t.X, Y: t.Y}) {
+				return true, i
+			}
+		}
+	}
+	for _, action := range d.input.Process(e) {
+		if action.Pressed {
+			if done, choice := d.processAction(action.Action); done {
+				return true, choice
+			}
+		}
+	}
+	return false, 0
+}
+
+func (d *Dialog) processAction(action input.Action) (bool, int) {
+	if action == input.Menu {
+		return true, d.cancel
+	}
+	if _, clicked := d.focus.ProcessAction(action); clicked != nil {
+		if btn, ok := clicked.(*rendering.Button); ok {
+			return true, int(btn.ActionId)
+		}
+	}
+	return false, 0
+}
+
+func (d *Dialog) update(deltaMS uint64) (bool, int) {
+	for _, action := range d.input.Update(deltaMS) {
+		if done, choice := d.processAction(action.Action); done {
+			return true, choice
+		}
+	}
+	mousePos := sdl.Point{}
+	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
+	for _, btn := range d.buttons {
+		btn.SetHovered(mousePos.InRect(&btn.Rect))
+	}
+	return false, 0
+}
+
+// Dims the screen and draws the panel of a dialog or an overlay scene over it
+func DrawOverlayPanel(r *rendering.CustomRenderer, panel sdl.Rect) {
+	w, h := r.SDLwindow.GetSize()
+	r.SDLrenderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	r.SDLrenderer.SetDrawColor(0, 0, 0, dialogDimAlpha)
+	r.SDLrenderer.FillRect(&sdl.Rect{X: 0, Y: 0, W: w, H: h})
+	r.SDLrenderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+	r.SetDrawColor(theme.Palette.Background)
+	r.SDLrenderer.FillRect(&panel)
+	r.SetDrawColor(theme.Palette.Text)
+	r.SDLrenderer.DrawRect(&panel)
+}
+
+func (d *Dialog) draw(r *rendering.CustomRenderer) {
+	DrawOverlayPanel(r, d.layout.Rect)
+	d.layout.Draw(r)
+}
+
+// Shows the dialog over the current scene, after the dialogs already shown are closed
+func (sm *SceneManager) ShowDialog(d *Dialog) error {
+	if err := d.build(&sm.renderer, sm.theme.Font); err != nil {
+		return err
+	}
+	w, h := sm.renderer.SDLwindow.GetSize()
+	d.resize(w, h)
+	sm.dialogs = append(sm.dialogs, d)
+	return nil
+}
+
+// True while a dialog takes the input
+func (sm *SceneManager) HasDialog() bool {
+	return len(sm.dialogs) > 0
+}
+
+// closes the shown dialog then calls its callback, which may show another dialog or change the scene
+func (sm *SceneManager) closeDialog(choice int) {
+	d := sm.dialogs[0]
+	sm.dialogs = sm.dialogs[1:]
+	d.destroy()
+	sm.audio.Play(audio.SoundClick)
+	if d.onClose != nil {
+		d.onClose(choice)
+	}
+}
+
+/*
+Gives the event to the shown dialog. The scene only gets the releases, so the keys and buttons
+it saw pressed aren't held after the dialog is closed
+*/
+func (sm *SceneManager) processDialogEvent(e sdl.Event) {
+	if done, choice := sm.dialogs[0].processEvent(e); done {
+		sm.closeDialog(choice)
+		return
+	}
+	release := false
+	switch t := e.(type) {
+	case *sdl.KeyboardEvent:
+		release = t.State == sdl.RELEASED
+	case *sdl.MouseButtonEvent:
+		release = t.State == sdl.RELEASED
+	case *sdl.ControllerButtonEvent:
+		release = t.State == sdl.RELEASED
+	case *sdl.ControllerAxisEvent:
+		release = input.AxisDirection(t.Value) == 0
+	}
+	if release {
+		sm.top().ProcessEvent(e)
+	}
+}
@@ -0,0 +1,269 @@
+package scenes
+
+import (
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Keyboard and game controller focus over a scene's selectable widgets.
+
+Tab/Shift+Tab go through the widgets in their order (wrapping around), the directions go to
+the nearest widget on that side, Confirm clicks the focused widget. The scenes handle Menu
+(Escape) themselves, usually by going back.
+
+A focused rendering.Control gets the actions first: the arrows change a slider, Confirm
+toggles a checkbox, Menu closes an open dropdown. A focused text field gets the keyboard
+before the scene's bindings (ProcessKey)
+*/
+type FocusManager struct {
+	widgets []rendering.Widget
+	focused int // index in widgets, -1 if none
+}
+
+func NewFocusManager() *FocusManager {
+	return &FocusManager{focused: -1}
+}
+
+// Replaces the widgets (after a rebuild), the focus stays on the same index if it can still be focused
+func (f *FocusManager) SetWidgets(widgets []rendering.Widget) {
+	focused := f.focused
+	f.widgets = widgets
+	f.focused = -1
+	if focused != -1 && focused < len(widgets) {
+		f.Focus(focused)
+	}
+}
+
+func focusable(w rendering.Widget) (rendering.Selectable, bool) {
+	selectable, ok := w.(rendering.Selectable)
+	if !ok || !selectable.IsSelectable() {
+		return nil, false
+	}
+	if btn, ok := w.(*rendering.Button); ok && !btn.Shown {
+		return nil, false
+	}
+	return selectable, true
+}
+
+// Focused widget, nil if none
+func (f *FocusManager) Focused() rendering.Selectable {
+	if f.focused == -1 {
+		return nil
+	}
+	selectable, _ := f.widgets[f.focused].(rendering.Selectable)
+	return selectable
+}
+
+// Focuses the i-th widget, returns false if it can't be focused
+func (f *FocusManager) Focus(i int) bool {
+	if i < 0 || i >= len(f.widgets) {
+		return false
+	}
+	selectable, ok := focusable(f.widgets[i])
+	if !ok {
+		return false
+	}
+	f.Clear()
+	selectable.Selected(true)
+	f.focused = i
+	return true
+}
+
+func (f *FocusManager) Clear() {
+	if focused := f.Focused(); focused != nil {
+		focused.Selected(false)
+	}
+	f.focused = -1
+}
+
+// Focuses the next focusable widget in the given direction (1 or -1), wrapping around
+func (f *FocusManager) step(direction int) bool {
+	count := len(f.widgets)
+	start := f.focused
+	if start == -1 {
+		// the first step lands on the first (or last) widget
+		start = count
+		if direction > 0 {
+			start = -1
+		}
+	}
+	for i := 1; i <= count; i++ {
+		widgetID := ((start+direction*i)%count + count) % count
+		if widgetID == f.focused {
+			return false
+		}
+		if f.Focus(widgetID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FocusManager) Next() bool {
+	return f.step(1)
+}
+
+func (f *FocusManager) Previous() bool {
+	return f.step(-1)
+}
+
+/*
+Focuses the nearest widget whose center is on the (dx, dy) side of the focused one's.
+The distance across the direction counts double, so the widgets in line are preferred.
+
+Without focus, the first widget is focused
+*/
+func (f *FocusManager) Move(dx, dy int32) bool {
+	focused := f.Focused()
+	if focused == nil {
+		return f.Next()
+	}
+	from := focused.Bounds()
+	fromX, fromY := from.X+from.W/2, from.Y+from.H/2
+	best := -1
+	var bestScore int32
+	for i, w := range f.widgets {
+		selectable, ok := focusable(w)
+		if !ok || i == f.focused {
+			continue
+		}
+		to := selectable.Bounds()
+		x, y := to.X+to.W/2-fromX, to.Y+to.H/2-fromY
+		along, across := x*dx+y*dy, x*dy+y*dx
+		if along <= 0 {
+			continue
+		}
+		if across < 0 {
+			across = -across
+		}
+		score := along + 2*across
+		if best == -1 || score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best != -1 && f.Focus(best)
+}
+
+/*
+Handles the navigation actions (focus, directions and Confirm).
+
+Returns true if the action was used, and the focused widget when Confirm clicks it
+*/
+func (f *FocusManager) ProcessAction(action input.Action) (bool, rendering.Selectable) {
+	if control, ok := f.Focused().(rendering.Control); ok {
+		switch action {
+		case input.MoveUp, input.MoveDown, input.MoveLeft, input.MoveRight:
+			dx, dy := actionDirection(action)
+			if control.Step(dx, dy) {
+				return true, nil
+			}
+		case input.Confirm:
+			control.Activate()
+			return true, nil
+		case input.Menu:
+			if control.Cancel() {
+				return true, nil
+			}
+		}
+		if control.Captures() {
+			// an open dropdown keeps the focus
+			return true, nil
+		}
+	}
+	switch action {
+	case input.FocusNext:
+		return f.Next(), nil
+	case input.FocusPrevious:
+		return f.Previous(), nil
+	case input.MoveUp:
+		return f.Move(0, -1), nil
+	case input.MoveDown:
+		return f.Move(0, 1), nil
+	case input.MoveLeft:
+		return f.Move(-1, 0), nil
+	case input.MoveRight:
+		return f.Move(1, 0), nil
+	case input.Confirm:
+		focused := f.Focused()
+		return focused != nil, focused
+	}
+	return false, nil
+}
+
+func actionDirection(action input.Action) (int, int) {
+	switch action {
+	case input.MoveUp:
+		return 0, -1
+	case input.MoveDown:
+		return 0, 1
+	case input.MoveLeft:
+		return -1, 0
+	case input.MoveRight:
+		return 1, 0
+	}
+	return 0, 0
+}
+
+/*
+Gives the mouse event to the controls (the one capturing the mouse alone), a control used
+by a click gets the focus.
+
+Returns true if a control used the event
+*/
+func (f *FocusManager) ProcessMouse(e sdl.Event) bool {
+	switch e.(type) {
+	case *sdl.MouseMotionEvent, *sdl.MouseButtonEvent, *sdl.MouseWheelEvent:
+	default:
+		return false
+	}
+	for i, w := range f.widgets {
+		if control, ok := w.(rendering.Control); ok && control.Captures() {
+			control.ProcessMouse(e)
+			f.focusClicked(i, e)
+			return true
+		}
+	}
+	used := false
+	for i, w := range f.widgets {
+		if control, ok := w.(rendering.Control); ok && control.ProcessMouse(e) {
+			f.focusClicked(i, e)
+			used = true
+		}
+	}
+	return used
+}
+
+// Gives the keyboard and text events to the focused text field, returns true if it used the event
+func (f *FocusManager) ProcessKey(e sdl.Event) bool {
+	if field, ok := f.Focused().(rendering.TextControl); ok {
+		return field.ProcessKey(e)
+	}
+	return false
+}
+
+func (f *FocusManager) focusClicked(i int, e sdl.Event) {
+	if t, ok := e.(*sdl.MouseButtonEvent); ok && t.State == sdl.PRESSED && i != f.focused {
+		f.Focus(i)
+	}
+}
+
+// Updates the controls (for the repeats while held)
+func (f *FocusManager) Update(deltaMS uint64) {
+	for _, w := range f.widgets {
+		if control, ok := w.(rendering.Control); ok {
+			control.Update(deltaMS)
+		}
+	}
+}
+
+// Draws the controls capturing the mouse (an open dropdown list) over the other widgets
+func (f *FocusManager) DrawCapturing(r *rendering.CustomRenderer) {
+	for _, w := range f.widgets {
+		if control, ok := w.(rendering.Control); ok && control.Captures() {
+			control.Draw(r)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	glyphScale             = 0.22 // glyph height relative to the tile's height
+	glyphScaleHighContrast = 0.32
+	glyphFontScale         = 3 // the glyphs are rendered bigger than the theme's font, they are scaled down with the tiles
+	cursorOutlineWidth     = 4 // px at zoom 1, in high contrast
+)
+
+// Colors of the board's overlays for a color mode, the minimap's ones as 0xRRGGBBAA
+type displayPalette struct {
+	numbers [8]sdl.Color // glyphs of the tiles with 1 to 8 bombs around
+	outline sdl.Color    // of the glyphs
+	cursor  sdl.Color    // outline of the player's tile
+	minimap minimapPalette
+}
+
+type minimapPalette struct {
+	border   uint32
+	hidden   uint32
+	revealed uint32
+	number   uint32
+	flagged  uint32
+	exploded uint32
+	bomb     uint32
+}
+
+/*
+Palettes by config.ColorModes.
+
+"red-green" uses the Okabe-Ito colors, readable with a protanopia or a deuteranopia,
+"blue-yellow" avoids the blue/yellow pairs confused with a tritanopia.
+The numbers' colors are told apart by their lightness too
+*/
+var displayPalettes = map[string]displayPalette{
+	"normal": {
+		numbers: [8]sdl.Color{
+			{R: 74, G: 163, B: 255, A: 255},
+			{R: 92, G: 214, B: 92, A: 255},
+			{R: 255, G: 80, B: 80, A: 255},
+			{R: 176, G: 112, B: 255, A: 255},
+			{R: 255, G: 160, B: 48, A: 255},
+			{R: 64, G: 224, B: 208, A: 255},
+			{R: 255, G: 255, B: 255, A: 255},
+			{R: 192, G: 192, B: 192, A: 255},
+		},
+		outline: sdl.Color{R: 0, G: 0, B: 0, A: 255},
+		cursor:  sdl.Color{R: 255, G: 255, B: 0, A: 255},
+		minimap: minimapPalette{
+			border:   0x141414ff,
+			hidden:   0x5a5a5aff,
+			revealed: 0xc8c8c8ff,
+			number:   0x9696c8ff,
+			flagged:  0xffd200ff,
+			exploded: 0xff2020ff,
+			bomb:     0x802020ff,
+		},
+	},
+	"red-green": {
+		numbers: [8]sdl.Color{
+			{R: 86, G: 180, B: 233, A: 255},
+			{R: 230, G: 159, B: 0, A: 255},
+			{R: 204, G: 121, B: 167, A: 255},
+			{R: 0, G: 114, B: 178, A: 255},
+			{R: 213, G: 94, B: 0, A: 255},
+			{R: 0, G: 158, B: 115, A: 255},
+			{R: 255, G: 255, B: 255, A: 255},
+			{R: 240, G: 228, B: 66, A: 255},
+		},
+		outline: sdl.Color{R: 0, G: 0, B: 0, A: 255},
+		cursor:  sdl.Color{R: 86, G: 180, B: 233, A: 255},
+		minimap: minimapPalette{
+			border:   0x141414ff,
+			hidden:   0x5a5a5aff,
+			revealed: 0xc8c8c8ff,
+			number:   0x56b4e9ff,
+			flagged:  0xf0e442ff,
+			exploded: 0xcc79a7ff,
+			bomb:     0x0072b2ff,
+		},
+	},
+	"blue-yellow": {
+		numbers: [8]sdl.Color{
+			{R: 0, G: 194, B: 194, A: 255},
+			{R: 255, G: 77, B: 77, A: 255},
+			{R: 255, G: 153, B: 204, A: 255},
+			{R: 102, G: 224, B: 255, A: 255},
+			{R: 179, G: 0, B: 0, A: 255},
+			{R: 140, G: 140, B: 140, A: 255},
+			{R: 255, G: 255, B: 255, A: 255},
+			{R: 255, G: 209, B: 209, A: 255},
+		},
+		outline: sdl.Color{R: 0, G: 0, B: 0, A: 255},
+		cursor:  sdl.Color{R: 255, G: 0, B: 128, A: 255},
+		minimap: minimapPalette{
+			border:   0x141414ff,
+			hidden:   0x5a5a5aff,
+			revealed: 0xc8c8c8ff,
+			number:   0x00c2c2ff,
+			flagged:  0xff4d4dff,
+			exploded: 0xff0080ff,
+			bomb:     0x800040ff,
+		},
+	},
+}
+
+// Numbers rendered with the theme's font, in white so they are tinted by SetColorMod
+type numberGlyphs struct {
+	textures [8]*sdl.Texture
+	sizes    [8]sdl.Rect
+}
+
+func newNumberGlyphs(renderer *sdl.Renderer, font *rendering.Font) (*numberGlyphs, error) {
+	g := &numberGlyphs{}
+	for i := range g.textures {
+		surface, err := font.RenderUTF8Blended(strconv.Itoa(i+1), sdl.Color{R: 255, G: 255, B: 255, A: 255})
+		if err != nil {
+			g.destroy()
+			return nil, fmt.Errorf("glyph creation error: %s", err)
+		}
+		g.textures[i], err = renderer.CreateTextureFromSurface(surface)
+		g.sizes[i] = sdl.Rect{W: surface.W, H: surface.H}
+		surface.Free()
+		if err != nil {
+			g.destroy()
+			return nil, fmt.Errorf("glyph texture creation error: %s", err)
+		}
+	}
+	return g, nil
+}
+
+func (g *numberGlyphs) destroy() {
+	for i, texture := range g.textures {
+		if texture != nil {
+			texture.Destroy()
+			g.textures[i] = nil
+		}
+	}
+}
+
+// Applies the accessibility settings, the board is redrawn if they changed
+func (s *GameScene) setDisplay(cfg config.AccessibilityConfig) {
+	if cfg == s.display {
+		return
+	}
+	s.display = cfg
+	palette, found := displayPalettes[cfg.ColorMode]
+	if !found {
+		palette = displayPalettes["normal"]
+	}
+	s.displayPalette = palette
+	s.invalidateBoard()
+	s.needsRedraw = true
+}
+
+// The theme's number sprites keep their colors, the color modes draw the glyphs in theirs over them
+func (s *GameScene) glyphsShown() bool {
+	colored := s.display.ColorMode != "" && s.display.ColorMode != config.ColorModes[0]
+	return s.glyphs != nil && (s.display.NumberGlyphs || s.display.HighContrast || colored)
+}
+
+// Draws the number of the tile's sprite with the font, centered on the top face of dest
+func (s *GameScene) drawTileGlyph(renderer *rendering.CustomRenderer, spriteID tileSpriteID, dest sdl.Rect, alpha uint8) {
+	if spriteID < tileSprite1 || spriteID > tileSprite8 || !s.glyphsShown() {
+		return
+	}
+	i := spriteID - tileSprite1
+	texture, size := s.glyphs.textures[i], s.glyphs.sizes[i]
+	scale, outline := glyphScale, int32(1)
+	if s.display.HighContrast {
+		scale, outline = glyphScaleHighContrast, 2
+	}
+	h := int32(math.Max(1, float64(dest.H)*scale))
+	w := h * size.W / maxInt32(1, size.H)
+	rect := sdl.Rect{X: dest.X + (dest.W-w)/2, Y: dest.Y + dest.H/4 - h/2, W: w, H: h}
+	outline = maxInt32(1, outline*h/16)
+
+	texture.SetAlphaMod(alpha)
+	c := s.displayPalette.outline
+	texture.SetColorMod(c.R, c.G, c.B)
+	for _, offset := range [8]sdl.Point{{X: -1, Y: -1}, {X: 0, Y: -1}, {X: 1, Y: -1}, {X: -1, Y: 0}, {X: 1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: 1}, {X: 1, Y: 1}} {
+		shadow := rect
+		shadow.X += offset.X * outline
+		shadow.Y += offset.Y * outline
+		renderer.SDLrenderer.Copy(texture, nil, &shadow)
+	}
+	c = s.displayPalette.numbers[i]
+	texture.SetColorMod(c.R, c.G, c.B)
+	renderer.SDLrenderer.Copy(texture, nil, &rect)
+}
+
+// Draws a thick outline around the top face of the tile drawn on dest (in high contrast)
+func (s *GameScene) drawTileOutline(renderer *rendering.CustomRenderer, dest sdl.Rect, zoom float64) {
+	if !s.display.HighContrast {
+		return
+	}
+	renderer.SetDrawColor(s.displayPalette.cursor)
+	width := int32(math.Max(1, cursorOutlineWidth*zoom))
+	centerX, centerY := dest.X+dest.W/2, dest.Y+dest.H/4
+	for i := int32(0); i < width; i++ {
+		renderer.SDLrenderer.DrawLines([]sdl.Point{
+			{X: centerX, Y: dest.Y + i},
+			{X: dest.X + dest.W - 2*i, Y: centerY},
+			{X: centerX, Y: dest.Y + dest.H/2 - i},
+			{X: dest.X + 2*i, Y: centerY},
+			{X: centerX, Y: dest.Y + i},
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package game
+
+import (
+	"math"
+	"sort"
+
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	tileRevealStepMS     = 35  // delay between two steps of a reveal cascade
+	tileRevealDurationMS = 220 // duration of a tile's reveal
+	endRevealMaxMS       = 1500
+	flagDropDurationMS   = 450
+	cursorMoveDurationMS = 110
+	shakeDurationMS      = 450
+	explosionRumbleMS    = 300  // game controllers rumble
+	shakeAmplitude       = 12.0 // px
+)
+
+type tileAnimKind int
+
+const (
+	tileAnimReveal tileAnimKind = iota
+	tileAnimFlagDrop
+)
+
+/*
+Animation of a tile's content.
+
+While it is animated, the content isn't drawn in the board (the tile still looks hidden while pending)
+and drawTileAnims draws it over the board instead
+*/
+type tileAnim struct {
+	kind     tileAnimKind
+	pending  bool
+	progress float64
+	tween    *rendering.Tween
+}
+
+// Highlight moving from a tile to the player's tile
+type cursorAnim struct {
+	fromCol  int32
+	fromRow  int32
+	progress float64
+}
+
+// Drops every animation, used when a new grid is loaded
+func (s *GameScene) clearAnimations() {
+	s.animator.Clear()
+	s.tileAnims = map[sdl.Point]*tileAnim{}
+	s.cursorAnim = nil
+	s.camera.shakeX = 0
+	s.camera.shakeY = 0
+	s.particles.Clear()
+}
+
+// Starts an animation of the tile's content, replacing the previous one
+func (s *GameScene) animateTile(col, row int32, kind tileAnimKind, tween *rendering.Tween) {
+	pos := sdl.Point{X: col, Y: row}
+	anim := &tileAnim{kind: kind, pending: tween.DelayMS > 0, tween: tween}
+	s.tileAnims[pos] = anim
+	tween.OnStart = func() {
+		anim.pending = false
+		s.invalidateTile(col, row)
+	}
+	tween.OnUpdate = func(value float64) {
+		anim.progress = value
+	}
+	tween.OnComplete = func() {
+		if s.tileAnims[pos] == anim {
+			delete(s.tileAnims, pos)
+			s.invalidateTile(col, row)
+		}
+	}
+	s.animator.Add(tween)
+}
+
+// The tile rises into place after delayMS
+func (s *GameScene) animateTileReveal(col, row int32, delayMS uint64) {
+	tween := rendering.NewTween(0, 1, tileRevealDurationMS, rendering.EaseOutBack)
+	tween.DelayMS = delayMS
+	s.animateTile(col, row, tileAnimReveal, tween)
+}
+
+// The flag falls on the tile
+func (s *GameScene) animateFlagDrop(col, row int32) {
+	s.animateTile(col, row, tileAnimFlagDrop, rendering.NewTween(0, 1, flagDropDurationMS, rendering.EaseOutBounce))
+}
+
+// The highlight slides from the tile to the player's tile
+func (s *GameScene) animateCursor(fromCol, fromRow int32) {
+	anim := &cursorAnim{fromCol: fromCol, fromRow: fromRow}
+	s.cursorAnim = anim
+	tween := rendering.NewTween(0, 1, cursorMoveDurationMS, rendering.EaseOutQuad)
+	tween.OnUpdate = func(value float64) {
+		anim.progress = value
+	}
+	tween.OnComplete = func() {
+		if s.cursorAnim == anim {
+			s.cursorAnim = nil
+		}
+	}
+	s.animator.Add(tween)
+}
+
+// Shakes the view, fading out
+func (s *GameScene) shakeCamera() {
+	tween := rendering.NewTween(0, 1, shakeDurationMS, rendering.EaseLinear)
+	tween.OnUpdate = func(progress float64) {
+		decay := (1 - progress) * (1 - progress)
+		s.camera.shakeX = shakeAmplitude * decay * math.Sin(progress*2*math.Pi*7)
+		s.camera.shakeY = shakeAmplitude * decay * math.Cos(progress*2*math.Pi*5)
+	}
+	tween.OnComplete = func() {
+		s.camera.shakeX = 0
+		s.camera.shakeY = 0
+	}
+	s.animator.Add(tween)
+}
+
+// Content sprite drawn in the board for the tile, the animated tiles are drawn by drawTileAnims
+func (s *GameScene) boardContentSprite(col, row int32) tileSpriteID {
+	if anim, found := s.tileAnims[sdl.Point{X: col, Y: row}]; found {
+		if anim.pending {
+			return tileSpriteHidden
+		}
+		return tileNoSprite
+	}
+	return s.tileValueToId(s.grid.tiles[col][row])
+}
+
+// Draws the animated tiles' content over the board, back to front
+func (s *GameScene) drawTileAnims(renderer *rendering.CustomRenderer, transform boardTransform) {
+	if len(s.tileAnims) == 0 {
+		return
+	}
+	positions := make([]sdl.Point, 0, len(s.tileAnims))
+	for pos, anim := range s.tileAnims {
+		if !anim.pending {
+			positions = append(positions, pos)
+		}
+	}
+	depth := func(p sdl.Point) int32 {
+		vcol, vrow := s.gridToView(p.X, p.Y)
+		return vcol + vrow
+	}
+	sort.Slice(positions, func(i, j int) bool { return depth(positions[i]) < depth(positions[j]) })
+	for _, pos := range positions {
+		anim := s.tileAnims[pos]
+		spriteID := s.tileValueToId(s.grid.tiles[pos.X][pos.Y])
+		if spriteID == tileNoSprite {
+			continue
+		}
+		rect := transform.rect(s.tileBoardRect(pos.X, pos.Y))
+		alpha := uint8(255)
+		switch anim.kind {
+		case tileAnimReveal:
+			rect.Y += int32((1 - anim.progress) * float64(rect.H) / 4)
+			alpha = uint8(math.Max(0, math.Min(1, anim.progress)) * 255)
+		case tileAnimFlagDrop:
+			rect.Y -= int32((1 - anim.progress) * float64(rect.H) / 2)
+		}
+		highlight := s.cursorAnim == nil && pos.X == s.player.pos.col && pos.Y == s.player.pos.row
+		s.spreadsheet.SelectSprite(s.spriteIndex(spriteID, highlight))
+		s.spreadsheet.SetAlpha(alpha)
+		s.spreadsheet.Draw(renderer, rect)
+		s.drawTileGlyph(renderer, spriteID, rect, alpha)
+	}
+	s.spreadsheet.SetAlpha(255)
+}
+
+// Draws the highlight between its previous and current tile
+func (s *GameScene) drawCursorAnim(renderer *rendering.CustomRenderer, transform boardTransform) {
+	from := s.tileBoardRect(s.cursorAnim.fromCol, s.cursorAnim.fromRow)
+	to := s.tileBoardRect(s.player.pos.col, s.player.pos.row)
+	p := s.cursorAnim.progress
+	rect := sdl.Rect{
+		X: from.X + int32(float64(to.X-from.X)*p),
+		Y: from.Y + int32(float64(to.Y-from.Y)*p),
+		W: to.W,
+		H: to.H,
+	}
+	s.spreadsheet.SelectSprite(s.spriteIndex(tileHover, false))
+	s.spreadsheet.Draw(renderer, transform.rect(rect))
+	s.drawTileOutline(renderer, transform.rect(rect), transform.zoom)
+}
@@ -0,0 +1,188 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// side of a chunk, in tiles (reduced if the textures would exceed the renderer's max size)
+const boardChunkTiles int32 = 16
+
+// above this amount of changed tiles, the whole chunk is redrawn instead of each tile
+const boardChunkMaxDirtyTiles = 32
+
+type boardLayer int
+
+const (
+	boardLayerGround boardLayer = iota
+	boardLayerContent
+	boardLayerCount
+)
+
+/*
+Pre-rendered part of the board.
+
+The ground and the content are kept in separate textures so the content of every tile
+is still drawn over the ground of every other tile once the chunks are assembled
+*/
+type boardChunk struct {
+	textures   [boardLayerCount]*sdl.Texture
+	bounds     sdl.Rect // position and size of the textures in board space
+	colStart   int32
+	colStop    int32
+	rowStart   int32
+	rowStop    int32
+	dirtyTiles []sdl.Point
+	fullRedraw bool
+}
+
+// Board pre-rendered in chunks of render-target textures, only the changed tiles are redrawn
+type boardCache struct {
+	chunks       []*boardChunk // row-major
+	chunkColumns int32
+	chunkRows    int32
+	chunkTiles   int32
+	tileSize     sdl.Rect
+	grid         *grid // grid and rotation the chunks were built for
+	rotation     int
+	enabled      bool
+}
+
+func (c *boardChunk) destroy() {
+	for i, texture := range c.textures {
+		if texture != nil {
+			texture.Destroy()
+			c.textures[i] = nil
+		}
+	}
+}
+
+func (c *boardCache) destroy() {
+	for _, chunk := range c.chunks {
+		chunk.destroy()
+	}
+	c.chunks = nil
+	c.chunkColumns = 0
+	c.chunkRows = 0
+}
+
+// Recreates the chunks for the view's size and the tile size, every chunk will be fully drawn on the next frame
+func (c *boardCache) rebuild(renderer *sdl.Renderer, columns, rows int32, tileSize sdl.Rect) error {
+	c.destroy()
+	c.tileSize = tileSize
+	if !c.enabled || columns < 1 || rows < 1 || tileSize.W < 2 || tileSize.H < 4 {
+		return nil
+	}
+	if !renderer.RenderTargetSupported() {
+		c.enabled = false
+		return fmt.Errorf("board cache: render targets not supported")
+	}
+	c.chunkTiles = boardChunkTiles
+	if info, err := renderer.GetInfo(); err == nil && info.MaxTextureWidth > 0 && info.MaxTextureHeight > 0 {
+		for c.chunkTiles > 1 && (c.chunkTiles*tileSize.W > info.MaxTextureWidth || (c.chunkTiles*tileSize.H)/2+tileSize.H > info.MaxTextureHeight) {
+			c.chunkTiles /= 2
+		}
+	}
+	c.chunkColumns = (columns + c.chunkTiles - 1) / c.chunkTiles
+	c.chunkRows = (rows + c.chunkTiles - 1) / c.chunkTiles
+	c.chunks = make([]*boardChunk, 0, c.chunkColumns*c.chunkRows)
+	for chunkRow := int32(0); chunkRow < c.chunkRows; chunkRow += 1 {
+		for chunkCol := int32(0); chunkCol < c.chunkColumns; chunkCol += 1 {
+			chunk := &boardChunk{
+				colStart:   chunkCol * c.chunkTiles,
+				colStop:    minInt32((chunkCol+1)*c.chunkTiles, columns),
+				rowStart:   chunkRow * c.chunkTiles,
+				rowStop:    minInt32((chunkRow+1)*c.chunkTiles, rows),
+				fullRedraw: true,
+			}
+			chunk.bounds = tileRangeBounds(chunk.colStart, chunk.colStop, chunk.rowStart, chunk.rowStop, tileSize)
+			for layer := range chunk.textures {
+				texture, err := renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, chunk.bounds.W, chunk.bounds.H)
+				if err != nil {
+					chunk.destroy()
+					c.destroy()
+					c.enabled = false
+					return fmt.Errorf("board cache: chunk texture: %s", err)
+				}
+				texture.SetBlendMode(sdl.BLENDMODE_BLEND)
+				chunk.textures[layer] = texture
+			}
+			c.chunks = append(c.chunks, chunk)
+		}
+	}
+	return nil
+}
+
+func (c *boardCache) chunkAt(col, row int32) *boardChunk {
+	if len(c.chunks) == 0 || col < 0 || row < 0 {
+		return nil
+	}
+	chunkCol := col / c.chunkTiles
+	chunkRow := row / c.chunkTiles
+	if chunkCol >= c.chunkColumns || chunkRow >= c.chunkRows {
+		return nil
+	}
+	return c.chunks[chunkRow*c.chunkColumns+chunkCol]
+}
+
+// Marks a view tile to be redrawn in its chunk
+func (c *boardCache) invalidateTile(col, row int32) {
+	chunk := c.chunkAt(col, row)
+	if chunk == nil || chunk.fullRedraw {
+		return
+	}
+	if len(chunk.dirtyTiles) >= boardChunkMaxDirtyTiles {
+		chunk.fullRedraw = true
+		chunk.dirtyTiles = chunk.dirtyTiles[:0]
+		return
+	}
+	chunk.dirtyTiles = append(chunk.dirtyTiles, sdl.Point{X: col, Y: row})
+}
+
+// Marks every chunk to be fully redrawn
+func (c *boardCache) invalidateAll() {
+	for _, chunk := range c.chunks {
+		chunk.fullRedraw = true
+		chunk.dirtyTiles = chunk.dirtyTiles[:0]
+	}
+}
+
+func (c *boardCache) isReady() bool {
+	return c.enabled && len(c.chunks) > 0
+}
+
+// Bounding box in board space of the sprites of the tiles [colStart;colStop[ x [rowStart;rowStop[
+func tileRangeBounds(colStart, colStop, rowStart, rowStop int32, tileSize sdl.Rect) sdl.Rect {
+	left := cartesianToIso(sdl.Point{X: colStart, Y: rowStop - 1}, tileSize)
+	right := cartesianToIso(sdl.Point{X: colStop - 1, Y: rowStart}, tileSize)
+	top := cartesianToIso(sdl.Point{X: colStart, Y: rowStart}, tileSize)
+	bottom := cartesianToIso(sdl.Point{X: colStop - 1, Y: rowStop - 1}, tileSize)
+	return sdl.Rect{
+		X: left.X,
+		Y: top.Y,
+		W: right.X - left.X + tileSize.W,
+		H: bottom.Y - top.Y + tileSize.H,
+	}
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt32(a int32) int32 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,182 @@
+package game
+
+import (
+	"math"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	cameraMinZoom = 0.25
+	cameraMaxZoom = 4.0
+	// zoom factor applied on each wheel notch or key press
+	cameraZoomStep = 1.25
+	// time (ms) for the camera to travel ~63% of the distance to its target
+	cameraEaseMS = 90.0
+	// distance (px) the mouse must travel while pressed before the click becomes a drag
+	cameraDragThreshold = 4
+)
+
+// Transformation from board space to a render target (the screen or a chunk's texture)
+type boardTransform struct {
+	offsetX float64
+	offsetY float64
+	zoom    float64
+}
+
+/*
+View on the board.
+
+Its position is the board space point shown at the center of the screen, it eases toward
+the player's tile unless the view was dragged away (until the player moves or it is recentered)
+*/
+type camera struct {
+	x           float64
+	y           float64
+	zoom        float64
+	targetX     float64
+	targetY     float64
+	targetZoom  float64
+	following   bool
+	dragging    bool
+	dragMoved   bool
+	dragStart   sdl.Point
+	boardBounds sdl.Rect
+	shakeX      float64 // screen offset added while shaking
+	shakeY      float64
+}
+
+func newCamera() camera {
+	return camera{
+		zoom:       1,
+		targetZoom: 1,
+		following:  true,
+	}
+}
+
+func (t boardTransform) point(x, y float64) (float64, float64) {
+	return x*t.zoom + t.offsetX, y*t.zoom + t.offsetY
+}
+
+// Rect in the target's space, both edges are rounded so the neighbouring rects don't leave gaps
+func (t boardTransform) rect(r sdl.Rect) sdl.Rect {
+	x0, y0 := t.point(float64(r.X), float64(r.Y))
+	x1, y1 := t.point(float64(r.X+r.W), float64(r.Y+r.H))
+	left, top := int32(math.Floor(x0)), int32(math.Floor(y0))
+	return sdl.Rect{X: left, Y: top, W: int32(math.Floor(x1)) - left, H: int32(math.Floor(y1)) - top}
+}
+
+// Transform from board space to the screen
+func (c *camera) transform(w, h int32) boardTransform {
+	return boardTransform{
+		offsetX: float64(w)/2 - c.x*c.zoom + c.shakeX,
+		offsetY: float64(h)/2 - c.y*c.zoom + c.shakeY,
+		zoom:    c.zoom,
+	}
+}
+
+// Screen point to board space
+func (c *camera) screenToBoard(p sdl.Point, w, h int32) (float64, float64) {
+	return (float64(p.X)-float64(w)/2)/c.zoom + c.x, (float64(p.Y)-float64(h)/2)/c.zoom + c.y
+}
+
+// Sets the point to follow, the camera only moves toward it while following
+func (c *camera) follow(x, y float64) {
+	c.targetX = x
+	c.targetY = y
+}
+
+// Jumps to the followed point and zoom without easing
+func (c *camera) snap() {
+	c.following = true
+	c.x = c.targetX
+	c.y = c.targetY
+	c.zoom = c.targetZoom
+}
+
+func (c *camera) recenter() {
+	c.following = true
+}
+
+func (c *camera) setZoom(zoom float64) {
+	c.targetZoom = math.Max(cameraMinZoom, math.Min(cameraMaxZoom, zoom))
+}
+
+// Zooms by factor keeping the board point under the screen point p at the same place
+func (c *camera) zoomAt(factor float64, p sdl.Point, w, h int32) {
+	bx, by := c.screenToBoard(p, w, h)
+	c.setZoom(c.targetZoom * factor)
+	if !c.following {
+		c.x = bx - (float64(p.X)-float64(w)/2)/c.targetZoom
+		c.y = by - (float64(p.Y)-float64(h)/2)/c.targetZoom
+		c.zoom = c.targetZoom
+		c.clamp()
+	}
+}
+
+func (c *camera) startDrag(p sdl.Point) {
+	c.dragging = true
+	c.dragMoved = false
+	c.dragStart = p
+}
+
+// Returns true if the camera moved
+func (c *camera) drag(p sdl.Point, xrel, yrel int32) bool {
+	if !c.dragging {
+		return false
+	}
+	if !c.dragMoved {
+		dx, dy := p.X-c.dragStart.X, p.Y-c.dragStart.Y
+		if dx*dx+dy*dy < cameraDragThreshold*cameraDragThreshold {
+			return false
+		}
+		c.dragMoved = true
+		xrel, yrel = dx, dy
+	}
+	c.following = false
+	c.x -= float64(xrel) / c.zoom
+	c.y -= float64(yrel) / c.zoom
+	c.clamp()
+	return true
+}
+
+// Returns true if the mouse was released without dragging (a click)
+func (c *camera) stopDrag() bool {
+	wasClick := c.dragging && !c.dragMoved
+	c.dragging = false
+	c.dragMoved = false
+	return wasClick
+}
+
+// Keeps the center of the screen on the board
+func (c *camera) clamp() {
+	if c.boardBounds.W == 0 {
+		return
+	}
+	c.x = math.Max(float64(c.boardBounds.X), math.Min(float64(c.boardBounds.X+c.boardBounds.W), c.x))
+	c.y = math.Max(float64(c.boardBounds.Y), math.Min(float64(c.boardBounds.Y+c.boardBounds.H), c.y))
+}
+
+// Eases the position and zoom toward their targets, returns true if the camera moved
+func (c *camera) update(deltaMS uint64) bool {
+	t := 1 - math.Exp(-float64(deltaMS)/cameraEaseMS)
+	moved := false
+	if c.following {
+		moved = ease(&c.x, c.targetX, t, 0.5/c.zoom) || moved
+		moved = ease(&c.y, c.targetY, t, 0.5/c.zoom) || moved
+	}
+	moved = ease(&c.zoom, c.targetZoom, t, 0.001) || moved
+	return moved
+}
+
+// Moves value toward target by the ratio t, snaps when closer than epsilon
+func ease(value *float64, target, t, epsilon float64) bool {
+	if *value == target {
+		return false
+	}
+	*value += (target - *value) * t
+	if math.Abs(target-*value) < epsilon {
+		*value = target
+	}
+	return true
+}
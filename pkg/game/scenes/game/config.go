@@ -2,14 +2,6 @@ package game
 
 import (
 	"minesweeper/pkg/game/rendering"
-
-	"github.com/veandco/go-sdl2/sdl"
-)
-
-const (
-	spritesheetFile          = "spritesheet_iso.png"
-	spritesheetColumns int32 = 15
-	spritesheetRows    int32 = 2
 )
 
 const (
@@ -17,12 +9,8 @@ const (
 	gridRows    = 10
 	bombPercent = 10
 	viewRange   = 22
-)
-
-var (
-	colorWhite    = sdl.Color{R: 255, G: 255, B: 255, A: sdl.ALPHA_OPAQUE}
-	colorDarkGrey = sdl.Color{R: 20, G: 20, B: 20, A: sdl.ALPHA_OPAQUE}
-	colorBack     = sdl.Color{R: 0, G: 0, B: 0, A: sdl.ALPHA_OPAQUE}
+	// time to watch the board being revealed before the results are shown
+	resultsDelayMS = 1500
 )
 
 const (
@@ -33,28 +21,29 @@ const (
 
 type tileSpriteID uint32
 
+// Roles of the board's sprites (in the order of config.ThemeSprites.Names), their id in the spritesheet is set by the theme
 const (
-	tileNoSprite           tileSpriteID = 666
-	tileSpriteBorder       tileSpriteID = 0
-	tileSpriteEmpty        tileSpriteID = 1
-	tileHover              tileSpriteID = 2
-	tileSpriteHidden       tileSpriteID = 3
-	tileSpriteFlag         tileSpriteID = 4
-	tileSpriteBomb         tileSpriteID = 5
-	tileSpriteBombExploded tileSpriteID = 6
-	tileSprite0            tileSpriteID = tileSpriteEmpty
-	tileSprite1            tileSpriteID = 7
-	tileSprite2            tileSpriteID = 8
-	tileSprite3            tileSpriteID = 9
-	tileSprite4            tileSpriteID = 10
-	tileSprite5            tileSpriteID = 11
-	tileSprite6            tileSpriteID = 12
-	tileSprite7            tileSpriteID = 13
-	tileSprite8            tileSpriteID = 14
+	tileSpriteBorder tileSpriteID = iota
+	tileSpriteEmpty
+	tileHover
+	tileSpriteHidden
+	tileSpriteFlag
+	tileSpriteBomb
+	tileSpriteBombExploded
+	tileSprite1
+	tileSprite2
+	tileSprite3
+	tileSprite4
+	tileSprite5
+	tileSprite6
+	tileSprite7
+	tileSprite8
+	tileSpriteCount
 )
+
 const (
-	textButtonExit     = "Main menu"
-	textButtonSettings = "Settings"
+	tileNoSprite tileSpriteID = 666
+	tileSprite0  tileSpriteID = tileSpriteEmpty
 )
 
 type tileState byte
@@ -76,8 +65,16 @@ type gameStats struct {
 	totalBombs     uint32
 	bombsRemaining uint32
 	bombsExploded  uint32
+	elapsedMS      uint64 // time played, stopped while the scene is paused
+	tilesOpened    int    // by the player, with the first opening
+	wrongFlags     int    // removed by the wrong flag penalty
+	clicks         int    // opening, flagging and chording actions
+	boardValue     int    // 3BV of the grid
 }
 
+// lines of the HUD
+const statsLines = 6
+
 type gameState byte
 
 const (
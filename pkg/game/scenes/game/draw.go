@@ -0,0 +1,274 @@
+package game
+
+import (
+	"log"
+	"math"
+	"sort"
+
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+The board is drawn in view coordinates: the grid's coordinates rotated by the view's rotation.
+Board space is the isometric projection of the view coordinates (the view tile 0;0 is at 0;0)
+*/
+
+// Position of the view tile's sprite in board space
+func (s *GameScene) viewTileRect(vcol, vrow int32) sdl.Rect {
+	pos := cartesianToIso(sdl.Point{X: vcol, Y: vrow}, s.tileSize)
+	return sdl.Rect{X: pos.X, Y: pos.Y, W: s.tileSize.W, H: s.tileSize.H}
+}
+
+// Position of the grid tile's sprite in board space
+func (s *GameScene) tileBoardRect(col, row int32) sdl.Rect {
+	return s.viewTileRect(s.gridToView(col, row))
+}
+
+func (s *GameScene) tileLayerSprite(layer boardLayer, col, row int32) tileSpriteID {
+	if layer == boardLayerContent {
+		return s.boardContentSprite(col, row)
+	}
+	if s.grid.tiles[col][row].has(tileStateBorder) {
+		return tileSpriteBorder
+	}
+	return tileSpriteEmpty
+}
+
+// Id of the sprite in the theme's spritesheet, its hover variant for the player's tile
+func (s *GameScene) spriteIndex(id tileSpriteID, highlight bool) uint32 {
+	if highlight {
+		return s.spritesHover[id]
+	}
+	return s.sprites[id]
+}
+
+func (s *GameScene) drawTileSprite(renderer *rendering.CustomRenderer, layer boardLayer, col, row int32, dest sdl.Rect, highlight bool) {
+	spriteID := s.tileLayerSprite(layer, col, row)
+	if spriteID == tileNoSprite {
+		return
+	}
+	highlight = highlight && s.cursorAnim == nil && spriteID != tileSpriteBorder && col == s.player.pos.col && row == s.player.pos.row
+	s.spreadsheet.SelectSprite(s.spriteIndex(spriteID, highlight))
+	s.spreadsheet.Draw(renderer, dest)
+	if layer == boardLayerContent {
+		s.drawTileGlyph(renderer, spriteID, dest, 255)
+	}
+}
+
+/*
+Draws a layer of the view tiles [vcstart;vcstop[ x [vrstart;vrstop[ transformed from board space to the target.
+If clip isn't nil, only the tiles intersecting it (in board space) are drawn.
+If highlight is true the player's tile uses the highlighted sprite
+*/
+func (s *GameScene) drawTiles(renderer *rendering.CustomRenderer, layer boardLayer, vcstart, vcstop, vrstart, vrstop int32, clip *sdl.Rect, transform boardTransform, highlight bool) {
+	for vr := vrstart; vr < vrstop; vr += 1 {
+		for vc := vcstart; vc < vcstop; vc += 1 {
+			rect := s.viewTileRect(vc, vr)
+			if clip != nil && !rect.HasIntersection(clip) {
+				continue
+			}
+			col, row := s.viewToGrid(vc, vr)
+			s.drawTileSprite(renderer, layer, col, row, transform.rect(rect), highlight)
+		}
+	}
+}
+
+// Center of the tile's sprite in board space
+func (s *GameScene) tileBoardCenter(col, row int32) (float64, float64) {
+	rect := s.tileBoardRect(col, row)
+	return float64(rect.X) + float64(rect.W)/2, float64(rect.Y) + float64(rect.H)/2
+}
+
+// Grid tile under the screen point, ok is false if there is no tile
+func (s *GameScene) pickTile(p sdl.Point) (col, row int32, ok bool) {
+	w, h := s.renderer.SDLwindow.GetSize()
+	x, y := s.camera.screenToBoard(p, w, h)
+	pos := isoToCartesian(x, y, s.tileSize)
+	viewColumns, viewRows := s.viewSize()
+	if pos.X < 0 || pos.Y < 0 || pos.X >= viewColumns || pos.Y >= viewRows {
+		return 0, 0, false
+	}
+	col, row = s.viewToGrid(pos.X, pos.Y)
+	return col, row, true
+}
+
+// The camera eases toward the player's tile (again if it was dragged away)
+func (s *GameScene) cameraFollowPlayer() {
+	s.camera.follow(s.tileBoardCenter(s.player.pos.col, s.player.pos.row))
+	s.camera.recenter()
+}
+
+// Redraws the changed tiles (or whole chunks) in the chunks' textures
+func (s *GameScene) updateBoardCache(renderer *rendering.CustomRenderer) {
+	if s.board.grid != s.grid || s.board.rotation != s.rotation || s.board.tileSize.W != s.tileSize.W || s.board.tileSize.H != s.tileSize.H {
+		s.board.grid = s.grid
+		s.board.rotation = s.rotation
+		viewColumns, viewRows := s.viewSize()
+		if err := s.board.rebuild(renderer.SDLrenderer, viewColumns, viewRows, s.tileSize); err != nil {
+			log.Printf("%s (fallback to direct drawing)\n", err)
+		}
+	}
+	// the scene may be drawn in a texture (during a transition)
+	target := renderer.SDLrenderer.GetRenderTarget()
+	for _, chunk := range s.board.chunks {
+		if !chunk.fullRedraw && len(chunk.dirtyTiles) == 0 {
+			continue
+		}
+		transform := boardTransform{offsetX: float64(-chunk.bounds.X), offsetY: float64(-chunk.bounds.Y), zoom: 1}
+		for layer, texture := range chunk.textures {
+			renderer.SDLrenderer.SetRenderTarget(texture)
+			renderer.SDLrenderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+			renderer.SDLrenderer.SetDrawColor(0, 0, 0, 0)
+			if chunk.fullRedraw {
+				renderer.SDLrenderer.Clear()
+				s.drawTiles(renderer, boardLayer(layer), chunk.colStart, chunk.colStop, chunk.rowStart, chunk.rowStop, nil, transform, false)
+				continue
+			}
+			for _, pos := range chunk.dirtyTiles {
+				region := s.viewTileRect(pos.X, pos.Y)
+				local := transform.rect(region)
+				renderer.SDLrenderer.SetClipRect(&local)
+				renderer.SDLrenderer.FillRect(&local)
+				s.drawTiles(
+					renderer, boardLayer(layer),
+					maxInt32(chunk.colStart, pos.X-3), minInt32(chunk.colStop, pos.X+4),
+					maxInt32(chunk.rowStart, pos.Y-3), minInt32(chunk.rowStop, pos.Y+4),
+					&region, transform, false,
+				)
+			}
+			renderer.SDLrenderer.SetClipRect(nil)
+		}
+		chunk.fullRedraw = false
+		chunk.dirtyTiles = chunk.dirtyTiles[:0]
+	}
+	renderer.SDLrenderer.SetRenderTarget(target)
+}
+
+// Marks the grid tile to be redrawn in the board cache and the minimap
+func (s *GameScene) invalidateTile(col, row int32) {
+	s.board.invalidateTile(s.gridToView(col, row))
+	s.minimap.dirty = true
+}
+
+// Marks every tile to be redrawn
+func (s *GameScene) invalidateBoard() {
+	s.board.invalidateAll()
+	s.minimap.dirty = true
+}
+
+// Draws the highlighted player's tile over the cached board, clipped to the tile so the tiles in front of it stay in front
+func (s *GameScene) drawCursor(renderer *rendering.CustomRenderer, transform boardTransform) {
+	vcol, vrow := s.gridToView(s.player.pos.col, s.player.pos.row)
+	viewColumns, viewRows := s.viewSize()
+	cursor := s.viewTileRect(vcol, vrow)
+	clip := transform.rect(cursor)
+	vcstart := maxInt32(0, vcol-3)
+	vcstop := minInt32(viewColumns, vcol+4)
+	vrstart := maxInt32(0, vrow-3)
+	vrstop := minInt32(viewRows, vrow+4)
+	renderer.SDLrenderer.SetClipRect(&clip)
+	s.drawTiles(renderer, boardLayerGround, vcstart, vcstop, vrstart, vrstop, &cursor, transform, true)
+	s.drawTiles(renderer, boardLayerContent, vcstart, vcstop, vrstart, vrstop, &cursor, transform, true)
+	renderer.SDLrenderer.SetClipRect(nil)
+}
+
+// Draws every visible tile without the cache
+func (s *GameScene) drawBoardDirect(renderer *rendering.CustomRenderer, transform boardTransform, w, h int32) {
+	x, y := s.camera.screenToBoard(sdl.Point{X: w / 2, Y: h / 2}, w, h)
+	center := isoToCartesian(x, y, s.tileSize)
+	viewColumns, viewRows := s.viewSize()
+	viewTiles := int32(float64(viewRange) / s.camera.zoom)
+	vrstart := maxInt32(0, center.Y-viewTiles)
+	vrstop := minInt32(viewRows, center.Y+viewTiles)
+	vcstart := maxInt32(0, center.X-viewTiles)
+	vcstop := minInt32(viewColumns, center.X+viewTiles)
+	screen := sdl.Rect{X: 0, Y: 0, W: w, H: h}
+	for layer := boardLayerGround; layer < boardLayerCount; layer += 1 {
+		for vr := vrstart; vr < vrstop; vr += 1 {
+			for vc := vcstart; vc < vcstop; vc += 1 {
+				rect := transform.rect(s.viewTileRect(vc, vr))
+				if rect.HasIntersection(&screen) {
+					col, row := s.viewToGrid(vc, vr)
+					s.drawTileSprite(renderer, layer, col, row, rect, true)
+				}
+			}
+		}
+	}
+}
+
+type rotatingTile struct {
+	col   int32
+	row   int32
+	rect  sdl.Rect
+	depth float64
+}
+
+// Draws the visible tiles while the view turns around the player's tile, sorted back to front
+func (s *GameScene) drawBoardRotating(renderer *rendering.CustomRenderer, transform boardTransform, w, h int32) {
+	angle := s.rotationAnim * math.Pi / 2
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	pvcol, pvrow := s.gridToView(s.player.pos.col, s.player.pos.row)
+	halfW, quarterH := float64(s.tileSize.W/2), float64(s.tileSize.H/4)
+	viewTiles := int32(float64(viewRange) / s.camera.zoom)
+	screen := sdl.Rect{X: 0, Y: 0, W: w, H: h}
+	tiles := make([]rotatingTile, 0, 4*viewTiles*viewTiles)
+	for col := maxInt32(0, s.player.pos.col-viewTiles); col < minInt32(int32(s.grid.columns), s.player.pos.col+viewTiles); col += 1 {
+		for row := maxInt32(0, s.player.pos.row-viewTiles); row < minInt32(int32(s.grid.rows), s.player.pos.row+viewTiles); row += 1 {
+			vcol, vrow := s.gridToView(col, row)
+			dx, dy := float64(vcol-pvcol), float64(vrow-pvrow)
+			vx := float64(pvcol) + dx*cos - dy*sin
+			vy := float64(pvrow) + dx*sin + dy*cos
+			rect := transform.rect(sdl.Rect{
+				X: int32(math.Round((vx - vy) * halfW)),
+				Y: int32(math.Round((vx + vy) * quarterH)),
+				W: s.tileSize.W,
+				H: s.tileSize.H,
+			})
+			if rect.HasIntersection(&screen) {
+				tiles = append(tiles, rotatingTile{col: col, row: row, rect: rect, depth: vx + vy})
+			}
+		}
+	}
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].depth < tiles[j].depth })
+	for layer := boardLayerGround; layer < boardLayerCount; layer += 1 {
+		for _, t := range tiles {
+			s.drawTileSprite(renderer, layer, t.col, t.row, t.rect, true)
+		}
+	}
+}
+
+func (s *GameScene) drawBoard(renderer *rendering.CustomRenderer) {
+	w, h := renderer.SDLwindow.GetSize()
+	transform := s.camera.transform(w, h)
+	if s.rotationAnim != 0 {
+		s.drawBoardRotating(renderer, transform, w, h)
+		return
+	}
+	if s.board.enabled {
+		s.updateBoardCache(renderer)
+	}
+	if s.board.isReady() {
+		screen := sdl.Rect{X: 0, Y: 0, W: w, H: h}
+		for layer := boardLayerGround; layer < boardLayerCount; layer += 1 {
+			for _, chunk := range s.board.chunks {
+				dest := transform.rect(chunk.bounds)
+				if dest.HasIntersection(&screen) {
+					renderer.SDLrenderer.Copy(chunk.textures[layer], nil, &dest)
+				}
+			}
+		}
+		if s.cursorAnim == nil {
+			s.drawCursor(renderer, transform)
+		}
+	} else {
+		s.drawBoardDirect(renderer, transform, w, h)
+	}
+	s.drawTileAnims(renderer, transform)
+	if s.cursorAnim != nil {
+		s.drawCursorAnim(renderer, transform)
+	} else {
+		s.drawTileOutline(renderer, transform.rect(s.tileBoardRect(s.player.pos.col, s.player.pos.row)), transform.zoom)
+	}
+}
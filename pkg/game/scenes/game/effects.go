@@ -0,0 +1,136 @@
+package game
+
+import (
+	"math"
+
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// openings of at least this many tiles sparkle
+const sparkleMinTiles = 20
+
+var (
+	debrisColors = []sdl.Color{
+		{R: 40, G: 40, B: 40, A: sdl.ALPHA_OPAQUE},
+		{R: 90, G: 90, B: 90, A: sdl.ALPHA_OPAQUE},
+		{R: 255, G: 140, B: 0, A: sdl.ALPHA_OPAQUE},
+		{R: 255, G: 60, B: 0, A: sdl.ALPHA_OPAQUE},
+	}
+	confettiColors = []sdl.Color{
+		{R: 255, G: 60, B: 60, A: sdl.ALPHA_OPAQUE},
+		{R: 60, G: 200, B: 60, A: sdl.ALPHA_OPAQUE},
+		{R: 60, G: 120, B: 255, A: sdl.ALPHA_OPAQUE},
+		{R: 255, G: 220, B: 0, A: sdl.ALPHA_OPAQUE},
+		{R: 255, G: 0, B: 255, A: sdl.ALPHA_OPAQUE},
+	}
+	sparkleColors = []sdl.Color{
+		{R: 255, G: 255, B: 255, A: sdl.ALPHA_OPAQUE},
+		{R: 255, G: 255, B: 160, A: sdl.ALPHA_OPAQUE},
+	}
+)
+
+// Center of the tile's top face in board space
+func (s *GameScene) tileTopCenter(col, row int32) (float64, float64) {
+	rect := s.tileBoardRect(col, row)
+	return float64(rect.X) + float64(rect.W)/2, float64(rect.Y) + float64(rect.H)/4
+}
+
+// Debris (colored and pieces of the tile) thrown up from an exploded tile
+func (s *GameScene) emitExplosion(col, row int32) {
+	x, y := s.tileTopCenter(col, row)
+	tileW := float64(s.tileSize.W)
+	s.particles.Burst(x, y, rendering.ParticleConfig{
+		Count:     40,
+		SpreadX:   tileW / 4,
+		SpreadY:   tileW / 8,
+		SpeedMin:  tileW,
+		SpeedMax:  tileW * 4,
+		AngleMin:  -math.Pi,
+		AngleMax:  0,
+		Gravity:   tileW * 8,
+		LifeMinMS: 400,
+		LifeMaxMS: 900,
+		SizeMin:   tileW / 20,
+		SizeMax:   tileW / 8,
+		Colors:    debrisColors,
+	})
+	s.particles.Burst(x, y, rendering.ParticleConfig{
+		Count:     6,
+		SpeedMin:  tileW * 1.5,
+		SpeedMax:  tileW * 3,
+		AngleMin:  -math.Pi * 0.9,
+		AngleMax:  -math.Pi * 0.1,
+		Gravity:   tileW * 8,
+		LifeMinMS: 500,
+		LifeMaxMS: 800,
+		SizeMin:   tileW / 5,
+		SizeMax:   tileW / 3,
+		Sprites:   s.spreadsheet,
+		SpriteIDs: []uint32{s.spriteIndex(tileSpriteHidden, false), s.spriteIndex(tileSpriteBombExploded, false)},
+	})
+}
+
+// Confetti falling over the whole view
+func (s *GameScene) emitConfetti() {
+	w, h := s.renderer.SDLwindow.GetSize()
+	x, _ := s.camera.screenToBoard(sdl.Point{X: w / 2, Y: 0}, w, h)
+	_, y := s.camera.screenToBoard(sdl.Point{X: w / 2, Y: -h / 10}, w, h)
+	tileW := float64(s.tileSize.W)
+	s.particles.Emit(x, y, rendering.ParticleConfig{
+		Count:     120,
+		SpreadX:   float64(w) / s.camera.zoom,
+		SpeedMin:  tileW / 2,
+		SpeedMax:  tileW * 2,
+		AngleMin:  math.Pi * 0.3,
+		AngleMax:  math.Pi * 0.7,
+		Gravity:   tileW * 2,
+		LifeMinMS: 1500,
+		LifeMaxMS: 3000,
+		SizeMin:   tileW / 12,
+		SizeMax:   tileW / 6,
+		Colors:    confettiColors,
+	}, 2000)
+}
+
+func (s *GameScene) emitSparkles(col, row int32) {
+	x, y := s.tileTopCenter(col, row)
+	tileW := float64(s.tileSize.W)
+	s.particles.Burst(x, y, rendering.ParticleConfig{
+		Count:     3,
+		SpreadX:   tileW / 2,
+		SpreadY:   tileW / 4,
+		SpeedMin:  tileW / 4,
+		SpeedMax:  tileW,
+		AngleMin:  -math.Pi * 0.8,
+		AngleMax:  -math.Pi * 0.2,
+		Gravity:   -tileW / 2,
+		LifeMinMS: 300,
+		LifeMaxMS: 600,
+		SizeMin:   tileW / 24,
+		SizeMax:   tileW / 12,
+		Colors:    sparkleColors,
+	})
+}
+
+// Makes the tiles of the reveal cascade that didn't start yet sparkle when they appear
+func (s *GameScene) sparkleReveals() {
+	for pos, anim := range s.tileAnims {
+		if anim.kind != tileAnimReveal || anim.tween.Progress() > 0 || s.grid.tiles[pos.X][pos.Y].has(tileStateBomb) {
+			continue
+		}
+		col, row := pos.X, pos.Y
+		onStart := anim.tween.OnStart
+		anim.tween.OnStart = func() {
+			onStart()
+			s.emitSparkles(col, row)
+		}
+	}
+}
+
+func (s *GameScene) drawParticles(renderer *rendering.CustomRenderer) {
+	w, h := renderer.SDLwindow.GetSize()
+	t := s.camera.transform(w, h)
+	s.particles.Draw(renderer, t.offsetX, t.offsetY, t.zoom)
+}
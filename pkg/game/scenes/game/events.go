@@ -2,53 +2,147 @@ package game
 
 import (
 	"fmt"
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
 	"minesweeper/pkg/game/scenes"
+	"time"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 func (s *GameScene) ProcessEvent(e sdl.Event) scenes.EventState {
 	switch t := e.(type) {
+	case *sdl.RenderEvent:
+		// the chunks' textures content (or the textures themselves) were lost
+		s.board.grid = nil
+		s.needsRedraw = true
+
+	case *sdl.WindowEvent:
+		if t.Event == sdl.WINDOWEVENT_FOCUS_LOST && s.InProgress() && s.sceneManager.GetConfig().Game.PauseOnFocusLoss {
+			s.pause()
+		}
+
 	case *sdl.MouseButtonEvent:
 		if t.State == sdl.PRESSED {
 			if (eventMouseClick(s, sdl.Point{X: t.X, Y: t.Y})) {
 				return scenes.EventProcessed
 			}
-		}
-
-	case *sdl.KeyboardEvent:
-		keyCode := t.Keysym.Sym
-		pressed := t.State == sdl.PRESSED
-		if pressed {
-			if keyCode == sdl.K_ESCAPE {
-				s.Exit()
+			if t.Button == sdl.BUTTON_LEFT && s.minimapMoveCamera(sdl.Point{X: t.X, Y: t.Y}) {
+				s.minimap.dragging = true
 				return scenes.EventProcessed
-			} else if keyCode == s.keyConfig.KeyUp {
-				eventMoveUP(s)
-			} else if keyCode == s.keyConfig.KeyDown {
-				eventMoveDOWN(s)
-			} else if keyCode == s.keyConfig.KeyRight {
-				eventMoveRIGHT(s)
-			} else if keyCode == s.keyConfig.KeyLeft {
-				eventMoveLEFT(s)
-			} else if s.state == gameStatePlaying {
-				if keyCode == s.keyConfig.KeyOpen && s.state == gameStatePlaying {
-					eventOpenTile(s)
-				} else if keyCode == s.keyConfig.KeyFlag && s.state == gameStatePlaying {
-					eventToggleFlag(s)
-				}
-			} else {
-				if keyCode == s.keyConfig.KeyReplay {
-					s.load()
-					s.needsRedraw = true
-				}
+			}
+			if t.Button == sdl.BUTTON_LEFT {
+				s.camera.startDrag(sdl.Point{X: t.X, Y: t.Y})
+			}
+		} else if t.Button == sdl.BUTTON_LEFT && s.minimap.dragging {
+			s.minimap.dragging = false
+		} else if t.Button == sdl.BUTTON_LEFT && s.camera.stopDrag() && s.sceneManager.GetConfig().Game.ClickToMove {
+			if col, row, ok := s.pickTile(sdl.Point{X: t.X, Y: t.Y}); ok {
+				eventMoveTo(s, col, row)
 			}
 		}
+
+	case *sdl.MouseMotionEvent:
+		if s.minimap.dragging {
+			s.minimapMoveCamera(sdl.Point{X: t.X, Y: t.Y})
+		} else if s.camera.drag(sdl.Point{X: t.X, Y: t.Y}, t.XRel, t.YRel) {
+			s.needsRedraw = true
+		}
+	}
+	for _, action := range s.input.Process(e) {
+		if s.processAction(action) == scenes.EventProcessed {
+			return scenes.EventProcessed
+		}
 	}
 	return scenes.EventToProcess
 }
 
+// Handles the pressed actions, the ones bound to the mouse act on the pointed tile
+func (s *GameScene) processAction(e input.Event) scenes.EventState {
+	if !e.Pressed {
+		return scenes.EventToProcess
+	}
+	pointer := sdl.Point{X: e.X, Y: e.Y}
+	if e.Binding.Kind == config.BindMouseButton && (e.Action == input.Open || e.Action == input.Flag || e.Action == input.Chord) {
+		if col, row, ok := s.pickTile(pointer); ok {
+			eventMoveTo(s, col, row)
+		}
+	}
+	switch e.Action {
+	case input.FocusNext, input.FocusPrevious, input.Confirm:
+		used, clicked := s.focus.ProcessAction(e.Action)
+		if !used {
+			return scenes.EventToProcess
+		}
+		if btn, ok := clicked.(*rendering.Button); ok {
+			s.processButtonClick(btn)
+		}
+		s.needsRedraw = true
+	case input.Menu:
+		s.pause()
+	case input.MoveUp:
+		eventMoveUP(s)
+	case input.MoveDown:
+		eventMoveDOWN(s)
+	case input.MoveRight:
+		eventMoveRIGHT(s)
+	case input.MoveLeft:
+		eventMoveLEFT(s)
+	case input.ZoomIn, input.ZoomOut:
+		factor := cameraZoomStep
+		if e.Action == input.ZoomOut {
+			factor = 1 / cameraZoomStep
+		}
+		if e.Binding.Kind == config.BindWheel {
+			// around the pointer
+			w, h := s.renderer.SDLwindow.GetSize()
+			s.camera.zoomAt(factor, pointer, w, h)
+			s.needsRedraw = true
+		} else {
+			eventZoom(s, factor)
+		}
+	case input.RotateLeft:
+		s.rotate(-1)
+	case input.RotateRight:
+		s.rotate(1)
+	case input.Minimap:
+		s.minimap.shown = !s.minimap.shown
+		s.needsRedraw = true
+	case input.Recenter:
+		s.cameraFollowPlayer()
+		s.needsRedraw = true
+	case input.Open:
+		if s.state == gameStatePlaying {
+			s.stats.clicks++
+			s.recordAction(replayOpen)
+			eventOpenTile(s)
+		}
+	case input.Flag:
+		if s.state == gameStatePlaying {
+			s.stats.clicks++
+			s.recordAction(replayFlag)
+			eventToggleFlag(s)
+		}
+	case input.Chord:
+		if s.state == gameStatePlaying {
+			s.stats.clicks++
+			s.recordAction(replayChord)
+			eventChordTile(s)
+		}
+	case input.Replay:
+		if s.state != gameStatePlaying {
+			s.load(s.sceneManager.GetConfig().Game, time.Now().UnixNano())
+			s.needsRedraw = true
+		}
+	default:
+		return scenes.EventToProcess
+	}
+	return scenes.EventProcessed
+}
+
 // returns true if the click was on a button
 func eventMouseClick(s *GameScene, pos sdl.Point) bool {
 	for _, w := range s.widgets {
@@ -62,63 +156,92 @@ func eventMouseClick(s *GameScene, pos sdl.Point) bool {
 	return false
 }
 
-func eventMoveLEFT(s *GameScene) {
-	err := s.moveTo(s.player.pos.col, s.player.pos.row+1)
+func eventMoveTo(s *GameScene, col, row int32) {
+	err := s.moveTo(col, row)
 	if err == nil {
+		s.sceneManager.PlaySound(audio.SoundMove)
 		if s.state == gameStatePlaying {
-			s.updateStateMessage(fmt.Sprintf("Moved to tile @%d;%d", s.player.pos.col, s.player.pos.row))
+			s.updateStateMessage(fmt.Sprintf(lang.Text.Game.MovedTo, s.player.pos.col, s.player.pos.row))
 		}
 		s.needsRedraw = true
 	}
 }
+
+// Moves by a step in view coordinates, so the directions stay the same on screen whatever the rotation
+func eventMoveView(s *GameScene, dvcol, dvrow int32) {
+	vcol, vrow := s.gridToView(s.player.pos.col, s.player.pos.row)
+	col, row := s.viewToGrid(vcol+dvcol, vrow+dvrow)
+	eventMoveTo(s, col, row)
+}
+
+func eventMoveLEFT(s *GameScene) {
+	eventMoveView(s, 0, 1)
+}
 func eventMoveRIGHT(s *GameScene) {
-	err := s.moveTo(s.player.pos.col, s.player.pos.row-1)
-	if err == nil {
-		if s.state == gameStatePlaying {
-			s.updateStateMessage(fmt.Sprintf("Moved to tile @%d;%d", s.player.pos.col, s.player.pos.row))
-		}
-		s.needsRedraw = true
-	}
+	eventMoveView(s, 0, -1)
 }
 func eventMoveUP(s *GameScene) {
-	err := s.moveTo(s.player.pos.col-1, s.player.pos.row)
-	if err == nil {
-		if s.state == gameStatePlaying {
-			s.updateStateMessage(fmt.Sprintf("Moved to tile @%d;%d", s.player.pos.col, s.player.pos.row))
-		}
-		s.needsRedraw = true
-	}
+	eventMoveView(s, -1, 0)
 }
 func eventMoveDOWN(s *GameScene) {
-	err := s.moveTo(s.player.pos.col+1, s.player.pos.row)
-	if err == nil {
-		if s.state == gameStatePlaying {
-			s.updateStateMessage(fmt.Sprintf("Moved to tile @%d;%d", s.player.pos.col, s.player.pos.row))
-		}
-		s.needsRedraw = true
-	}
+	eventMoveView(s, 1, 0)
+}
+
+// Zooms around the center of the screen
+func eventZoom(s *GameScene, factor float64) {
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.camera.zoomAt(factor, sdl.Point{X: w / 2, Y: h / 2}, w, h)
+	s.needsRedraw = true
 }
 
 func eventOpenTile(s *GameScene) {
 	count := 0
 	err := s.openTile(s.player.pos.col, s.player.pos.row, &count)
 	if err == nil {
-		s.stats.tilesHidden -= count
+		exploded := []sdl.Point{}
 		if s.grid.tiles[s.player.pos.col][s.player.pos.row].has(tileStateBomb) {
+			exploded = append(exploded, sdl.Point{X: s.player.pos.col, Y: s.player.pos.row})
+		}
+		eventTilesOpened(s, count, exploded)
+	}
+}
+
+// Opens the tiles around the player's number once all its bombs are flagged
+func eventChordTile(s *GameScene) {
+	exploded, count, err := s.chordTile(s.player.pos.col, s.player.pos.row)
+	if err == nil && count > 0 {
+		eventTilesOpened(s, count, exploded)
+	}
+}
+
+// Updates the stats and the message after count tiles were opened, exploding the bombs at the given positions
+func eventTilesOpened(s *GameScene, count int, exploded []sdl.Point) {
+	s.stats.tilesHidden -= count
+	s.stats.tilesOpened += count - len(exploded)
+	if len(exploded) > 0 {
+		for range exploded {
 			s.stats.bombsRemaining -= 1
 			s.stats.bombsExploded += 1
 			if s.stats.totalLives >= 0 {
 				s.stats.livesRemaining -= 1
 			}
-			s.updateStateMessage(fmt.Sprintf("Bomb exploded @%d:%d", s.player.pos.col, s.player.pos.row))
-		} else if count == 1 {
-			s.updateStateMessage(fmt.Sprintf("opened tile @%d:%d", s.player.pos.col, s.player.pos.row))
-		} else {
-			s.updateStateMessage(fmt.Sprintf("opened %d tiles from @%d:%d", count, s.player.pos.col, s.player.pos.row))
 		}
-		s.checkGameState()
-		s.needsRedraw = true
+		s.updateStateMessage(fmt.Sprintf(lang.Text.Game.BombExploded, exploded[0].X, exploded[0].Y))
+		s.shakeCamera()
+		s.sceneManager.PlaySound(audio.SoundExplosion)
+		s.sceneManager.Rumble(explosionRumbleMS)
+	} else if count == 1 {
+		s.updateStateMessage(fmt.Sprintf(lang.Text.Game.OpenedSingleTile, s.player.pos.col, s.player.pos.row))
+		s.sceneManager.PlaySound(audio.SoundOpen)
+	} else {
+		s.updateStateMessage(fmt.Sprintf(lang.Plural(lang.Text.Game.OpenedMultipleTiles, count), count, s.player.pos.col, s.player.pos.row))
+		s.sceneManager.PlaySound(audio.SoundCascade)
+		if count >= sparkleMinTiles {
+			s.sparkleReveals()
+		}
 	}
+	s.checkGameState()
+	s.needsRedraw = true
 }
 
 func eventToggleFlag(s *GameScene) {
@@ -128,16 +251,22 @@ func eventToggleFlag(s *GameScene) {
 			if s.partyGameConfig.WrongFlagPenalty && !s.grid.tiles[s.player.pos.col][s.player.pos.row].has(tileStateBomb) {
 				s.flagTile(s.player.pos.col, s.player.pos.row)
 				s.openTile(s.player.pos.col, s.player.pos.row, nil)
-				s.stats.livesRemaining -= 1
-				s.updateStateMessage(fmt.Sprintf("Wrong flag set on tile @%d:%d", s.player.pos.col, s.player.pos.row))
+				if s.stats.totalLives >= 0 {
+					s.stats.livesRemaining -= 1
+				}
+				s.stats.wrongFlags++
+				s.updateStateMessage(fmt.Sprintf(lang.Text.Game.FlagSetWrong, s.player.pos.col, s.player.pos.row))
+				s.sceneManager.PlaySound(audio.SoundUnflag)
 
 			} else {
 				s.stats.flagsUsed += 1
-				s.updateStateMessage(fmt.Sprintf("Set flag @%d:%d", s.player.pos.col, s.player.pos.row))
+				s.updateStateMessage(fmt.Sprintf(lang.Text.Game.FlagSet, s.player.pos.col, s.player.pos.row))
+				s.sceneManager.PlaySound(audio.SoundFlag)
 			}
 		} else {
 			s.stats.flagsUsed -= 1
-			s.updateStateMessage(fmt.Sprintf("Unset flag @%d:%d", s.player.pos.col, s.player.pos.row))
+			s.updateStateMessage(fmt.Sprintf(lang.Text.Game.FlagUnset, s.player.pos.col, s.player.pos.row))
+			s.sceneManager.PlaySound(audio.SoundUnflag)
 		}
 		s.checkGameState()
 		s.needsRedraw = true
@@ -2,14 +2,18 @@ package game
 
 import (
 	"errors"
+	"math"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-func isoToCartesian(isometricPos sdl.Point, tileSize sdl.Rect) sdl.Point {
+// Tile whose top face is under the board space point (inverse of cartesianToIso)
+func isoToCartesian(x, y float64, tileSize sdl.Rect) sdl.Point {
+	u := (x - float64(tileSize.W)/2) / float64(tileSize.W/2)
+	v := (y - float64(tileSize.H)/4) / float64(tileSize.H/4)
 	return sdl.Point{
-		X: (isometricPos.Y/(tileSize.H/2) + isometricPos.X/(tileSize.W/2)) / 2,
-		Y: (isometricPos.Y/(tileSize.H/2) - isometricPos.X/(tileSize.W/2)) / 2,
+		X: int32(math.Floor((v+u)/2 + 0.5)),
+		Y: int32(math.Floor((v-u)/2 + 0.5)),
 	}
 }
 
@@ -28,10 +32,13 @@ func (s *GameScene) flagTile(col, row int32) error {
 		s.grid.tiles[col][row].unset(tileStateFlagged)
 	} else {
 		s.grid.tiles[col][row].set(tileStateFlagged)
+		s.animateFlagDrop(col, row)
 	}
+	s.invalidateTile(col, row)
 	return nil
 }
 
+// Opens the tile and flood-fills the empty area around it (breadth-first, so the reveal ripples out from the tile)
 func (s *GameScene) openTile(col, row int32, count *int) error {
 	tile := &s.grid.tiles[col][row]
 	if tile.has(tileStateFlagged) {
@@ -40,29 +47,77 @@ func (s *GameScene) openTile(col, row int32, count *int) error {
 	if tile.has(tileStateShown) {
 		return errors.New("can't open: tile already opened")
 	}
+	type openStep struct {
+		pos   sdl.Point
+		depth int
+	}
 	tile.set(tileStateShown)
-	if tile.has(tileStateBomb) {
-		tile.set(tileStateExploded)
+	queue := []openStep{{pos: sdl.Point{X: col, Y: row}, depth: 0}}
+	for len(queue) > 0 {
+		step := queue[0]
+		queue = queue[1:]
+		tile := &s.grid.tiles[step.pos.X][step.pos.Y]
+		if tile.has(tileStateBomb) {
+			tile.set(tileStateExploded)
+			s.emitExplosion(step.pos.X, step.pos.Y)
+		}
+		s.invalidateTile(step.pos.X, step.pos.Y)
+		s.animateTileReveal(step.pos.X, step.pos.Y, uint64(step.depth)*tileRevealStepMS)
+		if count != nil {
+			*count += 1
+		}
+		if tile.bombAround == 0 && !tile.has(tileStateBomb) {
+			for _, pos := range s.grid.tilesAround(step.pos.X, step.pos.Y) {
+				around := &s.grid.tiles[pos.X][pos.Y]
+				if !around.has(tileStateShown | tileStateFlagged) {
+					around.set(tileStateShown)
+					queue = append(queue, openStep{pos: pos, depth: step.depth + 1})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Chords the opened number: if as many tiles around it are flagged (or exploded) as its number,
+the other hidden tiles around it are opened. Returns the bombs opened by a wrong flag
+*/
+func (s *GameScene) chordTile(col, row int32) ([]sdl.Point, int, error) {
+	tile := &s.grid.tiles[col][row]
+	if !tile.has(tileStateShown) || tile.has(tileStateBomb) || tile.bombAround == 0 {
+		return nil, 0, errors.New("can't chord: not an opened number")
 	}
-	if count != nil {
-		*count += 1
+	around := s.grid.tilesAround(col, row)
+	marked := 0
+	for _, pos := range around {
+		if s.grid.tiles[pos.X][pos.Y].has(tileStateFlagged | tileStateExploded) {
+			marked += 1
+		}
+	}
+	if marked != tile.bombAround {
+		return nil, 0, errors.New("can't chord: flags don't match the number")
 	}
-	if tile.bombAround == 0 && !tile.has(tileStateBomb) {
-		for _, pos := range s.grid.tilesAround(col, row) {
-			s.openTile(pos.X, pos.Y, count)
+	exploded := []sdl.Point{}
+	count := 0
+	for _, pos := range around {
+		if s.openTile(pos.X, pos.Y, &count) == nil && s.grid.tiles[pos.X][pos.Y].has(tileStateBomb) {
+			exploded = append(exploded, pos)
 		}
 	}
-	return nil
+	return exploded, count, nil
 }
 
 func (s *GameScene) moveTo(col, row int32) error {
-	if col < 0 || col > int32(s.grid.columns)-1 || row < 0 && row > int32(s.grid.rows)-1 {
+	if col < 0 || col > int32(s.grid.columns)-1 || row < 0 || row > int32(s.grid.rows)-1 {
 		return errors.New("can't move: invalid position")
 	}
 	if s.grid.tiles[col][row].has(tileStateBorder) {
 		return errors.New("can't move: border reached")
 	}
+	s.animateCursor(s.player.pos.col, s.player.pos.row)
 	s.player.pos.col = col
 	s.player.pos.row = row
+	s.cameraFollowPlayer()
 	return nil
 }
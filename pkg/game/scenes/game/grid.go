@@ -88,7 +88,8 @@ func (g *grid) placeBomb(col, row int32) error {
 	return nil
 }
 
-func newGrid(col, row, bombCount uint32) *grid {
+// Grid of the size with the bombs placed by rng (the same seed gives the same grid)
+func newGrid(col, row, bombCount uint32, rng *rand.Rand) *grid {
 	col += 2
 	row += 2
 	tiles := make([][]tile, col)
@@ -116,8 +117,8 @@ func newGrid(col, row, bombCount uint32) *grid {
 		g.tiles[g.columns-1][i].set(tileStateShown | tileStateBorder)
 	}
 	for bombCount > 0 {
-		col := int32(rand.Intn(int(g.columns) - 1))
-		row := int32(rand.Intn(int(g.rows) - 1))
+		col := int32(rng.Intn(int(g.columns) - 1))
+		row := int32(rng.Intn(int(g.rows) - 1))
 		err := g.placeBomb(col, row)
 		if err == nil {
 			bombCount -= 1
@@ -0,0 +1,171 @@
+package game
+
+import (
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const (
+	minimapMaxSize       int32 = 160 // max width/height (px) with minimapPixelsPerTile, the larger grids get 1 px per tile
+	minimapPixelsPerTile int32 = 2
+	minimapMargin        int32 = 10
+)
+
+var (
+	minimapColorFrame    = sdl.Color{R: 0, G: 0, B: 0, A: sdl.ALPHA_OPAQUE}
+	minimapColorPlayer   = sdl.Color{R: 255, G: 255, B: 255, A: sdl.ALPHA_OPAQUE}
+	minimapColorViewport = sdl.Color{R: 255, G: 255, B: 0, A: sdl.ALPHA_OPAQUE}
+)
+
+// Top-down overview of the whole grid (in view coordinates, so it turns with the view), one texel per tile
+type minimap struct {
+	texture  *sdl.Texture
+	pixels   []uint32
+	columns  int32
+	rows     int32
+	rect     sdl.Rect // on screen
+	shown    bool
+	dirty    bool
+	dragging bool
+}
+
+func (m *minimap) destroy() {
+	if m.texture != nil {
+		m.texture.Destroy()
+		m.texture = nil
+	}
+	m.columns = 0
+	m.rows = 0
+}
+
+// Color of the tile as 0xRRGGBBAA (sdl.PIXELFORMAT_RGBA8888)
+func (p *minimapPalette) tileColor(t tile) uint32 {
+	switch {
+	case t.has(tileStateBorder):
+		return p.border
+	case t.has(tileStateFlagged):
+		return p.flagged
+	case !t.has(tileStateShown):
+		return p.hidden
+	case t.has(tileStateExploded):
+		return p.exploded
+	case t.has(tileStateBomb):
+		return p.bomb
+	case t.bombAround > 0:
+		return p.number
+	}
+	return p.revealed
+}
+
+/*
+Places the minimap in the top left corner, with minimapPixelsPerTile if it fits minimapMaxSize.
+
+The scale is a whole number of pixels per tile and never under 1, so the tiles don't blend
+into their neighbours (a 200x200 grid takes 200x200 px)
+*/
+func (s *GameScene) placeMinimap() {
+	viewColumns, viewRows := s.viewSize()
+	scale := minimapPixelsPerTile
+	if biggest := maxInt32(viewColumns, viewRows); biggest*minimapPixelsPerTile > minimapMaxSize {
+		scale = 1
+	}
+	s.minimap.rect = sdl.Rect{
+		X: minimapMargin,
+		Y: minimapMargin,
+		W: viewColumns * scale,
+		H: viewRows * scale,
+	}
+}
+
+// Refreshes the texture from the grid (recreated if the view's size changed)
+func (s *GameScene) updateMinimap(renderer *rendering.CustomRenderer) error {
+	viewColumns, viewRows := s.viewSize()
+	if s.minimap.texture == nil || s.minimap.columns != viewColumns || s.minimap.rows != viewRows {
+		s.minimap.destroy()
+		texture, err := renderer.SDLrenderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_STREAMING, viewColumns, viewRows)
+		if err != nil {
+			return err
+		}
+		s.minimap.texture = texture
+		s.minimap.columns = viewColumns
+		s.minimap.rows = viewRows
+		s.minimap.pixels = make([]uint32, viewColumns*viewRows)
+		s.minimap.dirty = true
+	}
+	if !s.minimap.dirty {
+		return nil
+	}
+	for vrow := int32(0); vrow < viewRows; vrow += 1 {
+		for vcol := int32(0); vcol < viewColumns; vcol += 1 {
+			col, row := s.viewToGrid(vcol, vrow)
+			s.minimap.pixels[vrow*viewColumns+vcol] = s.displayPalette.minimap.tileColor(s.grid.tiles[col][row])
+		}
+	}
+	s.minimap.dirty = false
+	return s.minimap.texture.UpdateRGBA(nil, s.minimap.pixels, int(viewColumns))
+}
+
+// Board space point to the minimap's screen space
+func (s *GameScene) boardToMinimap(x, y float64) sdl.Point {
+	halfW, quarterH := float64(s.tileSize.W/2), float64(s.tileSize.H/4)
+	u := (x - halfW) / halfW
+	v := (y - quarterH) / quarterH
+	vcol := (u+v)/2 + 0.5
+	vrow := (v-u)/2 + 0.5
+	return sdl.Point{
+		X: s.minimap.rect.X + int32(vcol*float64(s.minimap.rect.W)/float64(s.minimap.columns)),
+		Y: s.minimap.rect.Y + int32(vrow*float64(s.minimap.rect.H)/float64(s.minimap.rows)),
+	}
+}
+
+func (s *GameScene) drawMinimap(renderer *rendering.CustomRenderer) {
+	if !s.minimap.shown {
+		return
+	}
+	if err := s.updateMinimap(renderer); err != nil {
+		return
+	}
+	frame := sdl.Rect{X: s.minimap.rect.X - 2, Y: s.minimap.rect.Y - 2, W: s.minimap.rect.W + 4, H: s.minimap.rect.H + 4}
+	renderer.SetDrawColor(minimapColorFrame)
+	renderer.SDLrenderer.FillRect(&frame)
+	renderer.SDLrenderer.Copy(s.minimap.texture, nil, &s.minimap.rect)
+
+	renderer.SDLrenderer.SetClipRect(&s.minimap.rect)
+	w, h := renderer.SDLwindow.GetSize()
+	corners := [...]sdl.Point{{X: 0, Y: 0}, {X: w, Y: 0}, {X: w, Y: h}, {X: 0, Y: h}, {X: 0, Y: 0}}
+	viewport := make([]sdl.Point, len(corners))
+	for i, corner := range corners {
+		viewport[i] = s.boardToMinimap(s.camera.screenToBoard(corner, w, h))
+	}
+	renderer.SetDrawColor(minimapColorViewport)
+	renderer.SDLrenderer.DrawLines(viewport)
+
+	vcol, vrow := s.gridToView(s.player.pos.col, s.player.pos.row)
+	size := maxInt32(3, s.minimap.rect.W/s.minimap.columns)
+	player := sdl.Rect{
+		X: s.minimap.rect.X + vcol*s.minimap.rect.W/s.minimap.columns - size/2 + s.minimap.rect.W/s.minimap.columns/2,
+		Y: s.minimap.rect.Y + vrow*s.minimap.rect.H/s.minimap.rows - size/2 + s.minimap.rect.H/s.minimap.rows/2,
+		W: size,
+		H: size,
+	}
+	renderer.SetDrawColor(minimapColorPlayer)
+	renderer.SDLrenderer.FillRect(&player)
+	renderer.SDLrenderer.SetClipRect(nil)
+}
+
+// Moves the camera to the tile under the minimap's point, returns false if the point isn't on the minimap
+func (s *GameScene) minimapMoveCamera(p sdl.Point) bool {
+	if !s.minimap.shown || s.minimap.columns == 0 || !p.InRect(&s.minimap.rect) {
+		return false
+	}
+	vcol := (p.X - s.minimap.rect.X) * s.minimap.columns / s.minimap.rect.W
+	vrow := (p.Y - s.minimap.rect.Y) * s.minimap.rows / s.minimap.rect.H
+	rect := s.viewTileRect(vcol, vrow)
+	s.camera.following = false
+	s.camera.x = float64(rect.X) + float64(rect.W)/2
+	s.camera.y = float64(rect.Y) + float64(rect.H)/2
+	s.camera.clamp()
+	s.needsRedraw = true
+	return true
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"minesweeper/pkg/config"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Actions of a replay
+const (
+	replayOpen  = "open"
+	replayFlag  = "flag"
+	replayChord = "chord"
+)
+
+// Action of the player on a tile, at a time of the game (the pauses aren't counted)
+type replayAction struct {
+	TimeMS uint64 `yaml:"time_ms"`
+	Action string `yaml:"action"`
+	Column int32  `yaml:"column"`
+	Row    int32  `yaml:"row"`
+}
+
+/*
+Replay of a finished game: its board is generated again from the seed and the grid settings,
+then the actions are played in order (the first opening is done by the board's generation)
+*/
+type replay struct {
+	Player     string            `yaml:"player"`
+	Game       config.GameConfig `yaml:"game"`
+	Seed       int64             `yaml:"seed"`
+	Won        bool              `yaml:"won"`
+	DurationMS uint64            `yaml:"duration_ms"`
+	Actions    []replayAction    `yaml:"actions"`
+}
+
+// Adds the action on the player's tile to the replay
+func (s *GameScene) recordAction(action string) {
+	s.actions = append(s.actions, replayAction{
+		TimeMS: s.stats.elapsedMS,
+		Action: action,
+		Column: s.player.pos.col,
+		Row:    s.player.pos.row,
+	})
+}
+
+// Writes the replay of the finished game in a file of config.ReplaysPath, returns its path
+func (s *GameScene) SaveReplay() (string, error) {
+	if !s.isLoaded || s.state == gameStatePlaying {
+		return "", errors.New("save replay: no finished game")
+	}
+	r := replay{
+		Player:     s.partyGameConfig.PlayerName,
+		Game:       s.partyGameConfig,
+		Seed:       s.seed,
+		Won:        s.results.Won,
+		DurationMS: s.results.DurationMS,
+		Actions:    s.actions,
+	}
+	if err := os.MkdirAll(config.ReplaysPath, 0755); err != nil {
+		return "", fmt.Errorf("save replay: %s", err)
+	}
+	path := filepath.Join(config.ReplaysPath, fmt.Sprintf("replay-%d-%s.yml", s.seed, time.Now().Format("20060102-150405")))
+	if err := config.SaveConfig(path, r); err != nil {
+		return "", err
+	}
+	return path, nil
+}
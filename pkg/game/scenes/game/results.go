@@ -0,0 +1,199 @@
+package game
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/history"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+3BV of the grid: the least clicks needed to clear it without flags, one per area without bombs
+around (its numbers are opened with it) and one per number not next to such an area
+*/
+func (g *grid) boardValue() int {
+	opened := make([][]bool, g.columns)
+	for col := range opened {
+		opened[col] = make([]bool, g.rows)
+	}
+	safe := func(col, row int32) bool {
+		return !g.tiles[col][row].has(tileStateBomb | tileStateBorder)
+	}
+	value := 0
+	for col := int32(0); col < int32(g.columns); col++ {
+		for row := int32(0); row < int32(g.rows); row++ {
+			if opened[col][row] || !safe(col, row) || g.tiles[col][row].bombAround != 0 {
+				continue
+			}
+			value++
+			opened[col][row] = true
+			queue := []sdl.Point{{X: col, Y: row}}
+			for len(queue) > 0 {
+				pos := queue[0]
+				queue = queue[1:]
+				for _, around := range g.tilesAround(pos.X, pos.Y) {
+					if opened[around.X][around.Y] || !safe(around.X, around.Y) {
+						continue
+					}
+					opened[around.X][around.Y] = true
+					if g.tiles[around.X][around.Y].bombAround == 0 {
+						queue = append(queue, around)
+					}
+				}
+			}
+		}
+	}
+	for col := int32(0); col < int32(g.columns); col++ {
+		for row := int32(0); row < int32(g.rows); row++ {
+			if !opened[col][row] && safe(col, row) {
+				value++
+			}
+		}
+	}
+	return value
+}
+
+// Best times of the won games (in ms), by grid settings
+type personalBests map[string]uint64
+
+func bestsKey(cfg config.GameConfig) string {
+	return fmt.Sprintf("%dx%d-%d%%", cfg.GridColumns, cfg.GridRows, cfg.BombPercent)
+}
+
+// The bests file is missing until a game is won
+func loadBests() personalBests {
+	bests := personalBests{}
+	if _, err := os.Stat(config.BestsFilePath); err == nil {
+		if err := config.LoadConfig(config.BestsFilePath, &bests); err != nil {
+			log.Printf("personal bests: %s\n", err)
+		}
+	}
+	return bests
+}
+
+// Called once when the game is won or lost: keeps its results and queues the results overlay
+func (s *GameScene) finishGame() {
+	flagsRight, flagsWrong := 0, s.stats.wrongFlags
+	for col := range s.grid.tiles {
+		for row := range s.grid.tiles[col] {
+			t := &s.grid.tiles[col][row]
+			if t.has(tileStateFlagged) && t.has(tileStateBomb) {
+				flagsRight++
+			} else if t.has(tileStateFlagged) {
+				flagsWrong++
+			}
+		}
+	}
+	// a life is lost by each exploded bomb and each wrong flag, with or without a limit
+	livesUsed := int(s.stats.bombsExploded) + s.stats.wrongFlags
+	cfg := s.partyGameConfig
+	s.results = scenes.GameResults{
+		Won:         s.state == gameStateWon,
+		DurationMS:  s.stats.elapsedMS,
+		Columns:     cfg.GridColumns,
+		Rows:        cfg.GridRows,
+		BombPercent: cfg.BombPercent,
+		Seed:        s.seed,
+		TilesOpened: s.stats.tilesOpened,
+		SafeTiles:   s.stats.totalTiles - int(s.stats.totalBombs),
+		FlagsRight:  flagsRight,
+		FlagsWrong:  flagsWrong,
+		LivesUsed:   livesUsed,
+		Lives:       s.stats.totalLives,
+		Clicks:      s.stats.clicks,
+		BoardValue:  s.stats.boardValue,
+	}
+	bests := loadBests()
+	key := bestsKey(cfg)
+	s.results.BestMS = bests[key]
+	if s.results.Won && (s.results.BestMS == 0 || s.results.DurationMS < s.results.BestMS) {
+		s.results.NewBest = true
+		bests[key] = s.results.DurationMS
+		if err := config.SaveConfig(config.BestsFilePath, bests); err != nil {
+			log.Printf("personal bests: %s\n", err)
+		}
+	}
+	s.recordGame()
+	removeSave()
+	s.resultsDelayMS = resultsDelayMS
+}
+
+// Appends the finished game to the history file
+func (s *GameScene) recordGame() {
+	cfg := s.partyGameConfig
+	r := s.results
+	record := history.Record{
+		Player:           cfg.PlayerName,
+		FinishedAt:       time.Now().Truncate(time.Second),
+		Columns:          r.Columns,
+		Rows:             r.Rows,
+		BombPercent:      r.BombPercent,
+		Lives:            r.Lives,
+		WrongFlagPenalty: cfg.WrongFlagPenalty,
+		Seed:             r.Seed,
+		Won:              r.Won,
+		DurationMS:       r.DurationMS,
+		TilesOpened:      r.TilesOpened,
+		FlagsRight:       r.FlagsRight,
+		FlagsWrong:       r.FlagsWrong,
+		LivesUsed:        r.LivesUsed,
+		Clicks:           r.Clicks,
+		BoardValue:       r.BoardValue,
+	}
+	if err := history.Append(config.HistoryFilePath, record); err != nil {
+		log.Println(err)
+	}
+}
+
+// Summary of the finished game
+func (s *GameScene) Results() scenes.GameResults {
+	return s.results
+}
+
+func (s *GameScene) showResults() {
+	if err := s.sceneManager.PushOverlay("results"); err != nil {
+		log.Printf("results: %s\n", err)
+	}
+}
+
+/*
+Writes the board in a text file of config.ExportsPath, one line per row of the grid:
+'*' for a bomb, '.' for a tile without bombs around, else the number of bombs around
+*/
+func (s *GameScene) ExportBoard() (string, error) {
+	if s.grid == nil {
+		return "", fmt.Errorf("export board: no board")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# minesweeper board %dx%d, %d bombs, seed %d\n", s.grid.columns-2, s.grid.rows-2, s.stats.totalBombs, s.seed)
+	for row := 1; row < int(s.grid.rows)-1; row++ {
+		for col := 1; col < int(s.grid.columns)-1; col++ {
+			t := s.grid.tiles[col][row]
+			switch {
+			case t.has(tileStateBomb):
+				b.WriteByte('*')
+			case t.bombAround == 0:
+				b.WriteByte('.')
+			default:
+				b.WriteByte(byte('0' + t.bombAround))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	if err := os.MkdirAll(config.ExportsPath, 0755); err != nil {
+		return "", fmt.Errorf("export board: %s", err)
+	}
+	path := filepath.Join(config.ExportsPath, fmt.Sprintf("board-%d.txt", s.seed))
+	if err := ioutil.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("export board: %s", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,64 @@
+package game
+
+import "math"
+
+// duration (ms) for the view to ease to ~63% of a rotation
+const rotationEaseMS = 70.0
+
+// Grid coordinates to view coordinates, the view is turned by s.rotation quarter turns clockwise
+func (s *GameScene) gridToView(col, row int32) (int32, int32) {
+	columns, rows := int32(s.grid.columns), int32(s.grid.rows)
+	switch s.rotation {
+	case 1:
+		return rows - 1 - row, col
+	case 2:
+		return columns - 1 - col, rows - 1 - row
+	case 3:
+		return row, columns - 1 - col
+	}
+	return col, row
+}
+
+// View coordinates to grid coordinates
+func (s *GameScene) viewToGrid(vcol, vrow int32) (int32, int32) {
+	columns, rows := int32(s.grid.columns), int32(s.grid.rows)
+	switch s.rotation {
+	case 1:
+		return vrow, rows - 1 - vcol
+	case 2:
+		return columns - 1 - vcol, rows - 1 - vrow
+	case 3:
+		return columns - 1 - vrow, vcol
+	}
+	return vcol, vrow
+}
+
+// Columns and rows of the grid once rotated
+func (s *GameScene) viewSize() (int32, int32) {
+	if s.rotation%2 == 1 {
+		return int32(s.grid.rows), int32(s.grid.columns)
+	}
+	return int32(s.grid.columns), int32(s.grid.rows)
+}
+
+// Turns the view by quarterTurns (clockwise if positive) around the player's tile
+func (s *GameScene) rotate(quarterTurns int) {
+	// the animated tiles and the particles are placed in the unrotated board space
+	s.animator.FinishAll()
+	s.particles.Clear()
+	s.rotation = ((s.rotation+quarterTurns)%4 + 4) % 4
+	s.rotationAnim -= float64(quarterTurns)
+	viewColumns, viewRows := s.viewSize()
+	s.camera.boardBounds = tileRangeBounds(0, viewColumns, 0, viewRows, s.tileSize)
+	s.placeMinimap()
+	s.minimap.dirty = true
+	s.cameraFollowPlayer()
+	s.camera.snap()
+	s.needsRedraw = true
+}
+
+// Eases the rotation animation, returns true if the view turned
+func (s *GameScene) updateRotation(deltaMS uint64) bool {
+	t := 1 - math.Exp(-float64(deltaMS)/rotationEaseMS)
+	return ease(&s.rotationAnim, 0, t, 0.01)
+}
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"minesweeper/pkg/config"
+	"os"
+	"strings"
+)
+
+/*
+Game saved by the pause menu, the grid is generated again from the seed and the grid settings,
+only the states of its tiles are kept: one line per row, '#' for a hidden tile, '.' for an
+opened one, 'F' for a flag and 'X' for an exploded bomb
+*/
+type savedGame struct {
+	Game   config.GameConfig `yaml:"game"`
+	Seed   int64             `yaml:"seed"`
+	Column int32             `yaml:"column"`
+	Row    int32             `yaml:"row"`
+	Tiles  []string          `yaml:"tiles"`
+	Stats  savedStats        `yaml:"stats"`
+	// the replay goes on after the game is restored
+	Actions []replayAction `yaml:"actions"`
+}
+
+// the gameStats that change while playing, the others come from the grid settings
+type savedStats struct {
+	TilesHidden    int    `yaml:"tiles_hidden"`
+	FlagsUsed      int    `yaml:"flags_used"`
+	LivesRemaining int    `yaml:"lives_remaining"`
+	BombsRemaining uint32 `yaml:"bombs_remaining"`
+	BombsExploded  uint32 `yaml:"bombs_exploded"`
+	ElapsedMS      uint64 `yaml:"elapsed_ms"`
+	TilesOpened    int    `yaml:"tiles_opened"`
+	WrongFlags     int    `yaml:"wrong_flags"`
+	Clicks         int    `yaml:"clicks"`
+}
+
+var savedTileChars = map[tileState]byte{
+	0:                                  '#',
+	tileStateShown:                     '.',
+	tileStateFlagged:                   'F',
+	tileStateShown | tileStateExploded: 'X',
+}
+
+// Writes the game in progress in config.SaveFilePath, it is restored when the program starts
+func (s *GameScene) Save() error {
+	if !s.InProgress() {
+		return errors.New("save game: no game in progress")
+	}
+	save := savedGame{
+		Game:    s.partyGameConfig,
+		Seed:    s.seed,
+		Column:  s.player.pos.col,
+		Row:     s.player.pos.row,
+		Actions: s.actions,
+		Stats: savedStats{
+			TilesHidden:    s.stats.tilesHidden,
+			FlagsUsed:      s.stats.flagsUsed,
+			LivesRemaining: s.stats.livesRemaining,
+			BombsRemaining: s.stats.bombsRemaining,
+			BombsExploded:  s.stats.bombsExploded,
+			ElapsedMS:      s.stats.elapsedMS,
+			TilesOpened:    s.stats.tilesOpened,
+			WrongFlags:     s.stats.wrongFlags,
+			Clicks:         s.stats.clicks,
+		},
+	}
+	for row := 1; row < int(s.grid.rows)-1; row++ {
+		var line strings.Builder
+		for col := 1; col < int(s.grid.columns)-1; col++ {
+			state := s.grid.tiles[col][row].state & (tileStateShown | tileStateFlagged | tileStateExploded)
+			line.WriteByte(savedTileChars[state])
+		}
+		save.Tiles = append(save.Tiles, line.String())
+	}
+	return config.SaveConfig(config.SaveFilePath, save)
+}
+
+// Loads the saved game if there is one, the file stays until the game is over or replaced
+func (s *GameScene) restoreSave() error {
+	if _, err := os.Stat(config.SaveFilePath); os.IsNotExist(err) {
+		return nil
+	}
+	var save savedGame
+	if err := config.LoadConfig(config.SaveFilePath, &save); err != nil {
+		return fmt.Errorf("restore game: %s", err)
+	}
+	// the grid is generated the same way it was for the saved game, the settings page's limits still apply
+	if save.Game.GridColumns == 0 || save.Game.GridRows == 0 || save.Game.BombPercent < 1 || save.Game.BombPercent > 99 {
+		return fmt.Errorf("restore game: invalid grid %dx%d with %d%% bombs", save.Game.GridColumns, save.Game.GridRows, save.Game.BombPercent)
+	}
+	if len(save.Tiles) != int(save.Game.GridRows) {
+		return fmt.Errorf("restore game: %d rows of tiles, expected %d", len(save.Tiles), save.Game.GridRows)
+	}
+	states := map[byte]tileState{}
+	for state, char := range savedTileChars {
+		states[char] = state
+	}
+	for row, line := range save.Tiles {
+		if len(line) != int(save.Game.GridColumns) {
+			return fmt.Errorf("restore game: row %d has %d tiles, expected %d", row+1, len(line), save.Game.GridColumns)
+		}
+		for col := range line {
+			if _, ok := states[line[col]]; !ok {
+				return fmt.Errorf("restore game: unknown tile %q at %d,%d", line[col], col+1, row+1)
+			}
+		}
+	}
+	if err := s.load(save.Game, save.Seed); err != nil {
+		return fmt.Errorf("restore game: %s", err)
+	}
+	for row, line := range save.Tiles {
+		for col := range line {
+			t := &s.grid.tiles[col+1][row+1]
+			t.unset(tileStateShown | tileStateFlagged | tileStateExploded)
+			t.set(states[line[col]])
+		}
+	}
+	s.stats.tilesHidden = save.Stats.TilesHidden
+	s.stats.flagsUsed = save.Stats.FlagsUsed
+	s.stats.livesRemaining = save.Stats.LivesRemaining
+	s.stats.bombsRemaining = save.Stats.BombsRemaining
+	s.stats.bombsExploded = save.Stats.BombsExploded
+	s.stats.elapsedMS = save.Stats.ElapsedMS
+	s.stats.tilesOpened = save.Stats.TilesOpened
+	s.stats.wrongFlags = save.Stats.WrongFlags
+	s.stats.clicks = save.Stats.Clicks
+	s.actions = save.Actions
+	if save.Column > 0 && save.Column < int32(s.grid.columns)-1 && save.Row > 0 && save.Row < int32(s.grid.rows)-1 {
+		s.player.pos = playerPos{col: save.Column, row: save.Row}
+	}
+	// the first opening of load was replaced by the saved tiles
+	s.clearAnimations()
+	s.invalidateBoard()
+	s.updateStateMessage("")
+	s.cameraFollowPlayer()
+	s.camera.snap()
+	return nil
+}
+
+// The saved game can't be continued once it is over or another one is started
+func removeSave() {
+	if err := os.Remove(config.SaveFilePath); err != nil && !os.IsNotExist(err) {
+		log.Printf("remove saved game: %s\n", err)
+	}
+}
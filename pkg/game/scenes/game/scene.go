@@ -2,26 +2,38 @@ package game
 
 import (
 	"fmt"
+	"log"
 	"math/rand"
 	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
 	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/game/theme"
+	"reflect"
+	"time"
 
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/veandco/go-sdl2/ttf"
 )
 
 type GameScene struct {
-	widgets         [2]rendering.Widget
-	statsMessage    [5]*rendering.Textbox
-	bigMessage      *rendering.Textbox
-	bigMessageRect  sdl.Rect
-	statsRect       sdl.Rect
-	stats           gameStats
-	renderer        *rendering.CustomRenderer
-	sceneManager    *scenes.SceneManager
-	font            *ttf.Font
-	spreadsheet     *rendering.Spritesheet
+	widgets        [2]rendering.Widget
+	widgetsLayout  [2]*scenes.Layout // the buttons in the bottom corners
+	statsMessage   [statsLines]*rendering.Textbox
+	bigMessage     *rendering.Textbox
+	bigMessageRect sdl.Rect
+	statsRect      sdl.Rect
+	stats          gameStats
+	renderer       *rendering.CustomRenderer
+	sceneManager   *scenes.SceneManager
+	font           *rendering.Font
+	spreadsheet    *rendering.Spritesheet
+	theme          *theme.Theme // theme the widgets were created with
+	language       *lang.Language
+	// id in the spritesheet of each sprite role, and of its version highlighted on the player's tile
+	sprites         [tileSpriteCount]uint32
+	spritesHover    [tileSpriteCount]uint32
 	grid            *grid
 	tileSize        sdl.Rect
 	player          player
@@ -29,90 +41,186 @@ type GameScene struct {
 	needsRedraw     bool
 	state           gameState
 	partyGameConfig config.GameConfig
-	keyConfig       config.ControlCodes
+	seed            int64          // of the board, to restart it
+	actions         []replayAction // of the player since the board was loaded, for its replay
+	controls        config.GameControls
+	input           *input.Context
+	focus           *scenes.FocusManager // of the buttons, the arrows stay on the board
+	board           boardCache
+	camera          camera
+	rotation        int     // quarter turns of the view, clockwise
+	rotationAnim    float64 // remaining quarter turns of the rotation animation
+	minimap         minimap
+	animator        rendering.Animator
+	tileAnims       map[sdl.Point]*tileAnim // animated tiles, by grid position
+	cursorAnim      *cursorAnim
+	particles       *rendering.ParticleSystem
+	display         config.AccessibilityConfig
+	displayPalette  displayPalette
+	glyphs          *numberGlyphs // numbers drawn with the theme's font
+	results         scenes.GameResults
+	resultsDelayMS  uint64 // until the results overlay is shown, 0 if it isn't queued
 }
 
-func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer, font *ttf.Font) (*GameScene, error) {
+func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer) (*GameScene, error) {
+	cfg := sceneManager.GetConfig()
+	s := &GameScene{
+		statsRect:       sdl.Rect{X: 0, Y: 0, W: 0, H: 0},
+		renderer:        renderer,
+		sceneManager:    sceneManager,
+		grid:            nil,
+		tileSize:        sdl.Rect{X: 0, Y: 0, W: 0, H: 0},
+		player:          player{pos: playerPos{col: 0, row: 0}},
+		isLoaded:        false,
+		needsRedraw:     true,
+		stats:           gameStats{},
+		state:           gameStatePlaying,
+		partyGameConfig: cfg.Game,
+		board:           boardCache{enabled: !cfg.Window.DisableBoardCache},
+		camera:          newCamera(),
+		minimap:         minimap{shown: true},
+		focus:           scenes.NewFocusManager(),
+		tileAnims:       map[sdl.Point]*tileAnim{},
+		particles:       rendering.NewParticleSystem(time.Now().UnixNano()),
+		displayPalette:  displayPalettes["normal"],
+	}
+	if err := s.rebuildWidgets(); err != nil {
+		return nil, err
+	}
+	s.setControls(cfg.Controls)
+	s.setDisplay(cfg.Accessibility)
+	// the game saved by the pause menu is continued with the main menu's Continue button
+	if err := s.restoreSave(); err != nil {
+		log.Println(err)
+	}
+	return s, nil
+}
+
+// (Re)creates the widgets and the messages with the scene manager's theme and language
+func (s *GameScene) rebuildWidgets() error {
 	var err error
 	var widgets [2]rendering.Widget
-	cfg := sceneManager.GetConfig()
+	currentTheme := s.sceneManager.GetTheme()
+	font := currentTheme.Font
 
 	widgets[0] = rendering.NewButton(
 		sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
 		true,
 		true,
-		false,
-		textButtonExit,
+		true,
+		lang.Text.Game.MainMenuBtn,
 		actionExit,
-		colorWhite,
-		&colorBack,
-		&colorWhite,
+		theme.Palette.Text,
+		&theme.Palette.ButtonBackground,
+		&theme.Palette.ButtonHover,
 	)
 
-	if err := widgets[0].(*rendering.Button).UpdateTexture(renderer.SDLrenderer, font); err != nil {
-		return nil, err
+	if err := widgets[0].(*rendering.Button).UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+		return err
 	}
 	widgets[1] = rendering.NewButton(
 		sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
 		true,
 		true,
-		false,
-		textButtonSettings,
+		true,
+		lang.Text.Game.SettingsBtn,
 		actionOpenSettingsMenu,
-		colorWhite,
-		&colorBack,
-		&colorWhite,
+		theme.Palette.Text,
+		&theme.Palette.ButtonBackground,
+		&theme.Palette.ButtonHover,
 	)
 
-	if err := widgets[1].(*rendering.Button).UpdateTexture(renderer.SDLrenderer, font); err != nil {
-		return nil, err
+	if err := widgets[1].(*rendering.Button).UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+		return err
 	}
-
-	spritesheet, err := rendering.NewSpritesheet(renderer, cfg.Window.ResourcesPath+spritesheetFile, spritesheetColumns, spritesheetRows)
-	if err != nil {
-		return nil, err
+	for i, anchor := range [2]scenes.Anchor{scenes.AnchorBottomLeft, scenes.AnchorBottomRight} {
+		s.widgetsLayout[i] = scenes.NewWidgetLayout(widgets[i]).
+			WithPadding(scenes.UniformInsets(5)).
+			WithMargin(scenes.UniformInsets(10)).
+			WithAnchor(anchor)
 	}
-	var statsMessage [5]*rendering.Textbox
+
+	var statsMessage [statsLines]*rendering.Textbox
 	for i := range statsMessage {
-		statsMessage[i], err = rendering.NewTextbox(sdl.Rect{X: 0, Y: 0, W: 0, H: 0}, true, true, "x", renderer.SDLrenderer, font, rendering.ColorWhite)
+		text := "x"
+		if s.statsMessage[i] != nil {
+			text = s.statsMessage[i].Text
+		}
+		statsMessage[i], err = rendering.NewTextbox(sdl.Rect{X: 0, Y: 0, W: 0, H: 0}, true, true, text, s.renderer.SDLrenderer, font, theme.Palette.Text)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-	bigMessage, err := rendering.NewTextbox(sdl.Rect{X: 0, Y: 0, W: 0, H: 0}, true, true, "", renderer.SDLrenderer, font, rendering.ColorWhite)
+	text := ""
+	if s.bigMessage != nil {
+		text = s.bigMessage.Text
+	}
+	bigMessage, err := rendering.NewTextbox(sdl.Rect{X: 0, Y: 0, W: 0, H: 0}, true, true, text, s.renderer.SDLrenderer, font, theme.Palette.Text)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	s := &GameScene{
-		widgets:         widgets,
-		statsMessage:    statsMessage,
-		statsRect:       sdl.Rect{X: 0, Y: 0, W: 0, H: 0},
-		font:            font,
-		renderer:        renderer,
-		sceneManager:    sceneManager,
-		spreadsheet:     spritesheet,
-		grid:            nil,
-		tileSize:        sdl.Rect{X: 0, Y: 0, W: 0, H: 0},
-		player:          player{pos: playerPos{col: 0, row: 0}},
-		isLoaded:        false,
-		needsRedraw:     true,
-		stats:           gameStats{},
-		bigMessage:      bigMessage,
-		state:           gameStatePlaying,
-		partyGameConfig: cfg.Game,
-		keyConfig:       cfg.Controls.Codes,
+	glyphsFont, err := currentTheme.Fonts.Font(currentTheme.Config.Font.Size * glyphFontScale)
+	if err != nil {
+		return err
+	}
+	glyphs, err := newNumberGlyphs(s.renderer.SDLrenderer, glyphsFont)
+	if err != nil {
+		return err
+	}
+	s.destroyWidgets()
+	s.widgets = widgets
+	s.focus.SetWidgets(s.widgets[:])
+	s.glyphs = glyphs
+	s.statsMessage = statsMessage
+	s.bigMessage = bigMessage
+	s.font = font
+	s.theme = currentTheme
+	s.language = s.sceneManager.GetLanguage()
+
+	s.spreadsheet = currentTheme.Spritesheet
+	// the names were checked when the theme was loaded
+	for i, name := range currentTheme.Config.Spritesheet.Sprites.Names() {
+		id, _ := s.spreadsheet.SpriteId(name)
+		s.sprites[i] = id
+		s.spritesHover[i] = s.spreadsheet.VariantId(id, rendering.VariantHover)
+	}
+	// the particles may use the previous spritesheet
+	s.particles.Clear()
+	s.board.grid = nil
+	s.needsRedraw = true
+	return nil
+}
+
+func (s *GameScene) destroyWidgets() {
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		}
+	}
+	for _, tbox := range s.statsMessage {
+		if tbox != nil {
+			tbox.Destroy()
+		}
+	}
+	if s.bigMessage != nil {
+		s.bigMessage.Destroy()
+	}
+	if s.glyphs != nil {
+		s.glyphs.destroy()
 	}
-	return s, nil
 }
 
 func (s *GameScene) processButtonClick(b *rendering.Button) {
+	if b.ActionId != actionNone {
+		s.sceneManager.PlaySound(audio.SoundClick)
+	}
 	switch b.ActionId {
 	case actionNone:
 		return
 	case actionOpenSettingsMenu:
-		s.sceneManager.SetScene("settings", false)
+		s.sceneManager.Push("settings")
 	case actionExit:
-		s.Exit()
+		s.confirmExit()
 	}
 
 }
@@ -149,7 +257,7 @@ func (s *GameScene) replaceStateMessage() {
 }
 
 func (s *GameScene) updateBigMessage(msg string) {
-	s.bigMessage.SetText(msg, s.renderer.SDLrenderer, s.font, rendering.ColorWhite)
+	s.bigMessage.SetText(msg, s.renderer.SDLrenderer, s.font, theme.Palette.Text)
 	s.replaceBigMessage()
 }
 func (s *GameScene) replaceBigMessage() {
@@ -159,72 +267,112 @@ func (s *GameScene) replaceBigMessage() {
 }
 
 func (s *GameScene) updateStateMessage(msg string) {
-	var msgs [5]string
+	var msgs [statsLines]string
 	if s.state == gameStatePlaying {
+		text := &lang.Text.Game
 		var livesMsg string
 		if s.stats.totalLives < 0 {
-			livesMsg = "lives left: ∞"
+			livesMsg = text.LivesInfinite
 		} else {
-			livesMsg = fmt.Sprintf("lives left: %d/%d", s.stats.livesRemaining, s.stats.totalLives)
+			livesMsg = fmt.Sprintf(text.LivesLeft, s.stats.livesRemaining, s.stats.totalLives)
 		}
 		msgs = [...]string{
 			msg,
-			fmt.Sprintf("Tiles hidden: %d/%d", s.stats.tilesHidden, s.stats.totalTiles),
-			fmt.Sprintf("flags used: %d", s.stats.flagsUsed),
-			fmt.Sprintf("Bombs remaining: %d", s.stats.bombsRemaining),
+			fmt.Sprintf(text.TilesHidden, s.stats.tilesHidden, s.stats.totalTiles),
+			fmt.Sprintf(text.FlagsUsed, s.stats.flagsUsed),
+			fmt.Sprintf(text.BombsRemaining, s.stats.bombsRemaining),
 			livesMsg,
+			fmt.Sprintf(text.Time, scenes.FormatDuration(s.stats.elapsedMS)),
 		}
 	} else {
+		text := &lang.Text.Game
 		var livesMsg string
 		if s.stats.totalLives < 0 {
-			livesMsg = "unlimited lives"
+			livesMsg = text.TotalLivesNoLimit
 		} else if s.stats.livesRemaining == 0 {
-			livesMsg = fmt.Sprintf("%d/%d lives left", s.stats.livesRemaining, s.stats.totalLives)
+			livesMsg = fmt.Sprintf(lang.Plural(text.TotalLivesLeft, s.stats.livesRemaining), s.stats.livesRemaining, s.stats.totalLives)
 		}
 		msgs = [...]string{
 			msg,
 			"",
-			fmt.Sprintf("%d tiles", s.stats.totalTiles),
-			fmt.Sprintf("%d/%d bombs exploded", s.stats.bombsExploded, s.stats.totalBombs),
+			fmt.Sprintf(lang.Plural(text.TotalTiles, s.stats.totalTiles), s.stats.totalTiles),
+			fmt.Sprintf(lang.Plural(text.TotalBombsExploded, int(s.stats.bombsExploded)), s.stats.bombsExploded, s.stats.totalBombs),
 			livesMsg,
+			fmt.Sprintf(text.Time, scenes.FormatDuration(s.stats.elapsedMS)),
 		}
 	}
 	for i := range msgs {
-		s.statsMessage[i].SetText(msgs[i], s.renderer.SDLrenderer, s.font, rendering.ColorWhite)
+		s.statsMessage[i].SetText(msgs[i], s.renderer.SDLrenderer, s.font, theme.Palette.Text)
 	}
 	s.replaceStateMessage()
 }
 
-func (s *GameScene) discoverGrid() {
+// Shows every hidden tile, the reveal ripples out from the player's tile
+func (s *GameScene) revealHiddenTiles() {
+	maxDistance := maxInt32(
+		maxInt32(s.player.pos.col, int32(s.grid.columns)-1-s.player.pos.col),
+		maxInt32(s.player.pos.row, int32(s.grid.rows)-1-s.player.pos.row),
+	)
+	step := uint64(tileRevealStepMS)
+	if maxDistance > 0 && uint64(maxDistance)*step > endRevealMaxMS {
+		step = endRevealMaxMS / uint64(maxDistance)
+	}
 	for col := range s.grid.tiles {
 		for row := range s.grid.tiles[col] {
 			if !s.grid.tiles[col][row].has(tileStateShown) {
 				s.grid.tiles[col][row].set(tileStateShown)
+				distance := maxInt32(absInt32(int32(col)-s.player.pos.col), absInt32(int32(row)-s.player.pos.row))
+				s.animateTileReveal(int32(col), int32(row), uint64(distance)*step)
 			}
 		}
 	}
 	s.stats.tilesHidden = 0
+	s.invalidateBoard()
+}
+
+func (s *GameScene) discoverGrid() {
+	s.revealHiddenTiles()
 	s.stats.bombsRemaining = 0
 }
 
+// Uses the controls' bindings, returns true if they changed
+func (s *GameScene) setControls(controls config.GameControls) bool {
+	if s.input != nil && reflect.DeepEqual(s.controls.Names, controls.Names) {
+		return false
+	}
+	s.controls = controls
+	s.input = input.NewContext(input.GameBindings(controls))
+	return true
+}
+
+// Message shown when the game ended in the state, empty while playing
+func (s *GameScene) gameOverMessage(state gameState) string {
+	replayKey := ""
+	if len(s.controls.Bindings.KeyReplay) > 0 {
+		replayKey = s.controls.Bindings.KeyReplay[0].String()
+	}
+	switch state {
+	case gameStateWon:
+		return fmt.Sprintf(lang.Text.Game.GameWon, replayKey)
+	case gameStateLost:
+		return fmt.Sprintf(lang.Text.Game.GameLost, replayKey)
+	}
+	return ""
+}
+
 func (s *GameScene) checkGameState() {
 	if s.stats.totalLives >= 0 && s.stats.livesRemaining <= 0 {
-		s.updateBigMessage("Game lost (no lives left) press [R] to replay")
+		s.updateBigMessage(s.gameOverMessage(gameStateLost))
 		s.updateStateMessage("")
-		for col := range s.grid.tiles {
-			for row := range s.grid.tiles[col] {
-				if !s.grid.tiles[col][row].has(tileStateShown) {
-					s.grid.tiles[col][row].set(tileStateShown)
-				}
-			}
-		}
-		s.stats.tilesHidden = 0
+		s.revealHiddenTiles()
+		s.sceneManager.PlaySound(audio.SoundLoss)
 		s.state = gameStateLost
 		s.updateStateMessage("")
+		s.finishGame()
 		return
 	}
 	if uint32(s.stats.flagsUsed) > s.stats.bombsRemaining {
-		s.updateBigMessage("At least one flag isn't right")
+		s.updateBigMessage(lang.Text.Game.IncorrectFlags)
 		return
 	}
 	if uint32(s.stats.flagsUsed) == s.stats.bombsRemaining {
@@ -239,23 +387,27 @@ func (s *GameScene) checkGameState() {
 		}
 		if won {
 			s.discoverGrid()
+			s.emitConfetti()
+			s.sceneManager.PlaySound(audio.SoundWin)
 			s.state = gameStateWon
-			s.updateBigMessage("Game won, press [R] to replay")
-			s.updateStateMessage("")
-
+			s.updateBigMessage(s.gameOverMessage(gameStateWon))
 			s.updateStateMessage("")
+			s.finishGame()
 		} else {
-			s.updateBigMessage("At least one flag isn't right")
+			s.updateBigMessage(lang.Text.Game.IncorrectFlags)
 		}
 		return
 	}
 	if uint32(s.stats.tilesHidden) == s.stats.bombsRemaining {
 		s.discoverGrid()
+		s.emitConfetti()
+		s.sceneManager.PlaySound(audio.SoundWin)
 		s.stats.tilesHidden = 0
 		s.stats.bombsRemaining = 0
 		s.state = gameStateWon
-		s.updateBigMessage("Game won, press [R] to replay")
+		s.updateBigMessage(s.gameOverMessage(gameStateWon))
 		s.updateStateMessage("")
+		s.finishGame()
 		return
 	}
 	s.updateBigMessage("")
@@ -264,14 +416,10 @@ func (s *GameScene) checkGameState() {
 func (s *GameScene) ProcessResize(w, h int32) {
 	s.replaceStateMessage()
 	s.replaceBigMessage()
-	if btn, ok := s.widgets[0].(*rendering.Button); ok {
-		btn.SetBackgroundSize(btn.TextureRect.W+10, btn.TextureRect.H+10)
-		btn.SetTopLeft(10, h-btn.Rect.H-10)
-	}
-	if btn, ok := s.widgets[1].(*rendering.Button); ok {
-		btn.SetBackgroundSize(btn.TextureRect.W+10, btn.TextureRect.H+10)
-		btn.SetTopLeft(w-btn.Rect.W-10, h-btn.Rect.H-10)
+	for _, layout := range s.widgetsLayout {
+		layout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h})
 	}
+	oldTileSize := s.tileSize
 	minTileSize := sdl.Rect{X: 0, Y: 0, W: w / 11, H: h / 11}
 	if minTileSize.W < minTileSize.H {
 		minTileSize.H = minTileSize.W
@@ -292,10 +440,53 @@ func (s *GameScene) ProcessResize(w, h int32) {
 	p := cartesianToIso(sdl.Point{X: int32(s.grid.columns), Y: int32(s.grid.rows)}, s.tileSize)
 	s.tileSize.X = (w - p.X) / 2
 	s.tileSize.Y = (h - p.Y) / 2
+	if oldTileSize.W > 0 && !s.camera.following {
+		ratio := float64(s.tileSize.W) / float64(oldTileSize.W)
+		s.camera.x *= ratio
+		s.camera.y *= ratio
+	}
+	viewColumns, viewRows := s.viewSize()
+	s.camera.boardBounds = tileRangeBounds(0, viewColumns, 0, viewRows, s.tileSize)
+	s.placeMinimap()
+	s.camera.follow(s.tileBoardCenter(s.player.pos.col, s.player.pos.row))
+	if s.camera.following {
+		s.camera.x, s.camera.y = s.camera.targetX, s.camera.targetY
+	}
 	s.needsRedraw = true
 }
 
 func (s *GameScene) Update(deltaMS uint64) {
+	if s.state == gameStatePlaying {
+		second := s.stats.elapsedMS / 1000
+		s.stats.elapsedMS += deltaMS
+		if s.stats.elapsedMS/1000 != second {
+			s.updateStateMessage(s.statsMessage[0].Text)
+			s.needsRedraw = true
+		}
+	}
+	if s.resultsDelayMS > 0 {
+		if s.resultsDelayMS <= deltaMS {
+			s.resultsDelayMS = 0
+			s.showResults()
+			return
+		}
+		s.resultsDelayMS -= deltaMS
+	}
+	for _, e := range s.input.Update(deltaMS) {
+		s.processAction(e)
+	}
+	if s.animator.Update(deltaMS) {
+		s.needsRedraw = true
+	}
+	if s.particles.Update(deltaMS) {
+		s.needsRedraw = true
+	}
+	if s.camera.update(deltaMS) {
+		s.needsRedraw = true
+	}
+	if s.updateRotation(deltaMS) {
+		s.needsRedraw = true
+	}
 	mousePos := sdl.Point{}
 	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
 	var oldState bool
@@ -350,79 +541,12 @@ func (s *GameScene) tileValueToId(t tile) tileSpriteID {
 	return tileSprite0 + tileSpriteID(t.bombAround)
 }
 
-func (s *GameScene) drawTileGround(renderer rendering.CustomRenderer, cstart, cstop, rstart, rstop int32) {
-	w, h := renderer.SDLwindow.GetSize()
-	rect := s.tileSize
-	dp := cartesianToIso(sdl.Point{X: s.player.pos.col, Y: s.player.pos.row}, s.tileSize)
-	s.spreadsheet.SelectSprite(uint32(tileSpriteEmpty))
-	var pos sdl.Point
-	for r := rstart; r < rstop; r += 1 {
-		for c := cstart; c < cstop; c += 1 {
-			pos = cartesianToIso(sdl.Point{X: c, Y: r}, rect)
-			rect.X = pos.X - dp.X + w/2
-			rect.Y = pos.Y - dp.Y + h/2
-			var spriteID uint32
-			if s.grid.tiles[c][r].has(tileStateBorder) {
-				spriteID = uint32(tileSpriteBorder)
-			} else {
-				spriteID = uint32(tileSpriteEmpty)
-				if c == s.player.pos.col && r == s.player.pos.row {
-					spriteID += uint32(spritesheetColumns)
-				}
-			}
-			s.spreadsheet.SelectSprite(spriteID)
-			s.spreadsheet.Draw(&renderer, rect)
-		}
-	}
-}
-
-func (s *GameScene) drawTileContent(renderer rendering.CustomRenderer, cstart, cstop, rstart, rstop int32) {
-	w, h := renderer.SDLwindow.GetSize()
-	rect := s.tileSize
-	dp := cartesianToIso(sdl.Point{X: s.player.pos.col, Y: s.player.pos.row}, s.tileSize)
-	var pos sdl.Point
-	for r := rstart; r < rstop; r += 1 {
-		for c := cstart; c < cstop; c += 1 {
-			spriteID := s.tileValueToId(s.grid.tiles[c][r])
-			if spriteID != tileNoSprite {
-				id := uint32(spriteID)
-				if c == s.player.pos.col && r == s.player.pos.row {
-					id += uint32(spritesheetColumns)
-				}
-				s.spreadsheet.SelectSprite(id)
-				pos = cartesianToIso(sdl.Point{X: c, Y: r}, rect)
-				rect.X = pos.X - dp.X + w/2
-				rect.Y = pos.Y - dp.Y + h/2
-				s.spreadsheet.Draw(&renderer, rect)
-			}
-		}
-	}
-}
-
+// Draws the whole scene, the scene manager only calls it when needed (or under a dialog)
 func (s *GameScene) Draw(renderer rendering.CustomRenderer) {
-	if !s.needsRedraw {
-		return
-	}
-	rstart := s.player.pos.row - viewRange
-	rstop := s.player.pos.row + viewRange
-	if rstart < 0 {
-		rstart = 0
-	}
-	if rstop > int32(s.grid.rows) {
-		rstop = int32(s.grid.rows)
-	}
-
-	cstart := s.player.pos.col - viewRange
-	cstop := s.player.pos.col + viewRange
-	if cstart < 0 {
-		cstart = 0
-	}
-	if cstop > int32(s.grid.columns) {
-		cstop = int32(s.grid.columns)
-	}
-	s.drawTileGround(renderer, cstart, cstop, rstart, rstop)
-	s.drawTileContent(renderer, cstart, cstop, rstart, rstop)
-	renderer.SDLrenderer.SetDrawColor(0, 0, 0, sdl.ALPHA_OPAQUE)
+	s.drawBoard(&renderer)
+	s.drawParticles(&renderer)
+	s.drawMinimap(&renderer)
+	renderer.SetDrawColor(theme.Palette.HudBackground)
 	renderer.SDLrenderer.FillRect(&s.statsRect)
 	if s.bigMessage.Text != "" {
 		renderer.SDLrenderer.FillRect(&s.bigMessageRect)
@@ -438,66 +562,156 @@ func (s *GameScene) Draw(renderer rendering.CustomRenderer) {
 	s.needsRedraw = false
 }
 
+// True while the board is played, the game isn't over
+func (s *GameScene) InProgress() bool {
+	return s.isLoaded && s.state == gameStatePlaying
+}
+
+// Opens the pause menu over the game in progress, leaves the game once it is over
+func (s *GameScene) pause() {
+	if !s.InProgress() {
+		s.Exit()
+		return
+	}
+	if err := s.sceneManager.PushOverlay("pause"); err != nil {
+		log.Printf("pause: %s\n", err)
+	}
+}
+
+// Leaves the scene, after a confirmation if the game is in progress
+func (s *GameScene) confirmExit() {
+	if !s.InProgress() {
+		s.Exit()
+		return
+	}
+	dialog := scenes.NewConfirmDialog(lang.Text.Dialog.LeaveGameTitle, lang.Text.Dialog.LeaveGame, func(confirmed bool) {
+		if confirmed {
+			s.Exit()
+		}
+	})
+	if err := s.sceneManager.ShowDialog(dialog); err != nil {
+		log.Printf("leave game: %s\n", err)
+		s.Exit()
+	}
+}
+
+// Goes back to the main menu, the game can be continued from it
 func (s *GameScene) Exit() {
-	// s.grid = nil
-	s.sceneManager.SetSceneDefault(false)
+	s.sceneManager.Pop()
 }
 
-func (s *GameScene) Enter(reload bool) error {
-	if reload {
-		var cfg config.Config
-		if config.LoadConfig(config.ConfigFilePath, &cfg) == nil {
-			s.sceneManager.SetConfig(cfg)
+// Starts a new game with the config file's grid
+func (s *GameScene) NewGame() error {
+	cfg := config.DefaultConfig
+	if config.LoadConfig(config.ConfigFilePath, &cfg) == nil && cfg.Check() == nil {
+		s.sceneManager.SetConfig(cfg)
+	}
+	s.setControls(s.sceneManager.GetConfig().Controls)
+	removeSave()
+	return s.load(s.sceneManager.GetConfig().Game, time.Now().UnixNano())
+}
+
+// Starts the same board over, with the same grid settings
+func (s *GameScene) Restart() error {
+	if !s.isLoaded {
+		return s.NewGame()
+	}
+	removeSave()
+	return s.load(s.partyGameConfig, s.seed)
+}
+
+// Continues the game, a new one is started if there is none
+func (s *GameScene) Enter() error {
+	if !s.isLoaded {
+		if err := s.NewGame(); err != nil {
+			return err
 		}
-		err := s.load()
-		if err != nil {
+	}
+	return s.Resume()
+}
+
+func (s *GameScene) Pause() {
+	// the releases go to the scene on top
+	s.input.Reset()
+	s.minimap.dragging = false
+	s.camera.stopDrag()
+}
+
+// Applies the settings changed while the scene was paused
+func (s *GameScene) Resume() error {
+	languageChanged := s.language != s.sceneManager.GetLanguage()
+	if s.theme != s.sceneManager.GetTheme() || languageChanged {
+		if err := s.rebuildWidgets(); err != nil {
 			return err
 		}
 	}
+	// the keys may have been changed in the controls page
+	keysChanged := s.setControls(s.sceneManager.GetConfig().Controls)
+	// the bindings held when leaving the scene were released elsewhere
+	s.input.Reset()
+	if languageChanged || keysChanged {
+		// the messages kept the previous language's texts or replay key
+		s.updateBigMessage(s.gameOverMessage(s.state))
+		s.updateStateMessage("")
+	}
+	s.setDisplay(s.sceneManager.GetConfig().Accessibility)
 
 	w, h := s.renderer.SDLwindow.GetSize()
 	s.ProcessResize(w, h)
 	return nil
 }
 
-func (s *GameScene) load() error {
-	cfg := s.sceneManager.GetConfig()
-	bombCount := cfg.Game.GridColumns * cfg.Game.GridRows * uint32(cfg.Game.BombPercent) / 100
-	s.grid = newGrid(cfg.Game.GridColumns, cfg.Game.GridRows, bombCount)
-	s.partyGameConfig = cfg.Game
-	tileCount := int(cfg.Game.GridColumns * cfg.Game.GridRows)
+// Starts a game of the grid settings, the bombs and the first tile are placed from the seed
+func (s *GameScene) load(gameCfg config.GameConfig, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	bombCount := gameCfg.GridColumns * gameCfg.GridRows * uint32(gameCfg.BombPercent) / 100
+	s.clearAnimations()
+	s.grid = newGrid(gameCfg.GridColumns, gameCfg.GridRows, bombCount, rng)
+	s.minimap.dirty = true
+	s.partyGameConfig = gameCfg
+	s.seed = seed
+	tileCount := int(gameCfg.GridColumns * gameCfg.GridRows)
 	s.stats = gameStats{
 		tilesHidden:    tileCount,
 		totalTiles:     tileCount,
 		flagsUsed:      0,
-		livesRemaining: cfg.Game.Lives,
-		totalLives:     cfg.Game.Lives,
+		livesRemaining: gameCfg.Lives,
+		totalLives:     gameCfg.Lives,
 		totalBombs:     bombCount,
 		bombsRemaining: bombCount,
 		bombsExploded:  0,
+		boardValue:     s.grid.boardValue(),
 	}
+	s.resultsDelayMS = 0
+	s.actions = nil
 	playerPlaced := false
 	for !playerPlaced {
-		col := int32(rand.Intn(int(s.grid.columns)))
-		row := int32(rand.Intn(int(s.grid.rows)))
+		col := int32(rng.Intn(int(s.grid.columns)))
+		row := int32(rng.Intn(int(s.grid.rows)))
 		var firstOpenCount int
 		if !s.grid.tiles[col][row].has(tileStateBomb|tileStateBorder) && s.grid.tiles[col][row].bombAround == 0 && s.openTile(col, row, &firstOpenCount) == nil {
 			s.player.pos.col = col
 			s.player.pos.row = row
 			s.stats.tilesHidden -= firstOpenCount
+			s.stats.tilesOpened = firstOpenCount
 			playerPlaced = true
 		}
 	}
-	fmt.Printf("(load) New game config: %+v\n", cfg.Game)
+	fmt.Printf("(load) New game config: %+v (seed %d)\n", gameCfg, seed)
+	s.state = gameStatePlaying
 	s.updateStateMessage("")
 	s.updateBigMessage("")
+	s.cameraFollowPlayer()
+	s.camera.snap()
 	s.isLoaded = true
 	s.needsRedraw = true
-	s.state = gameStatePlaying
 	return nil
 }
 
 func (s *GameScene) Unload() {
+	s.board.destroy()
+	s.board.grid = nil
+	s.minimap.destroy()
 	// for _, b := range s.buttons {
 	// 	b.Destroy()
 	// }
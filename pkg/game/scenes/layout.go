@@ -0,0 +1,351 @@
+package scenes
+
+import (
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Placement of a node in the space its parent gives it
+type Align int
+
+const (
+	AlignCenter Align = iota
+	AlignStart
+	AlignEnd
+	AlignStretch // takes the whole space, the background of the buttons and controls grows with it
+)
+
+// Side of the screen a root node is placed on
+type Anchor int
+
+const (
+	AnchorCenter Anchor = iota
+	AnchorTop
+	AnchorBottom
+	AnchorLeft
+	AnchorRight
+	AnchorTopLeft
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+)
+
+type Insets struct {
+	Top, Right, Bottom, Left int32
+}
+
+func UniformInsets(value int32) Insets {
+	return Insets{Top: value, Right: value, Bottom: value, Left: value}
+}
+
+func (i Insets) horizontal() int32 {
+	return i.Left + i.Right
+}
+
+func (i Insets) vertical() int32 {
+	return i.Top + i.Bottom
+}
+
+// area inside the insets
+func (i Insets) shrink(r sdl.Rect) sdl.Rect {
+	return sdl.Rect{X: r.X + i.Left, Y: r.Y + i.Top, W: r.W - i.horizontal(), H: r.H - i.vertical()}
+}
+
+type layoutKind int
+
+const (
+	layoutWidget layoutKind = iota
+	layoutVBox
+	layoutHBox
+	layoutGrid
+)
+
+/*
+Node of a layout tree: a widget, or a box placing its children in a column (VBox),
+a row (HBox) or a grid (filled row by row).
+
+A node takes its preferred size (its content and padding, bounded by its min/max size) and is
+aligned in the space its parent gives it, the margin being outside of it. The root is placed on
+the screen by Resize, on the side given by its anchor.
+
+The scenes declare their tree when they create their widgets, then only call Resize
+*/
+type Layout struct {
+	kind     layoutKind
+	widget   rendering.Widget
+	children []*Layout
+	Columns  int       // of a grid
+	Spacing  int32     // between the children of a box
+	Padding  Insets    // inside the node: around the children of a box, the text of a button
+	Margin   Insets    // outside the node
+	AlignX   Align     // in the parent's space
+	AlignY   Align     // in the parent's space
+	Anchor   Anchor    // of the root on the screen
+	MinSize  sdl.Point // 0 for no minimum
+	MaxSize  sdl.Point // 0 for no maximum
+	Rect     sdl.Rect  // set by Resize, without the margin
+}
+
+func NewWidgetLayout(w rendering.Widget) *Layout {
+	return &Layout{kind: layoutWidget, widget: w}
+}
+
+func NewVBox(children ...*Layout) *Layout {
+	return &Layout{kind: layoutVBox, children: children}
+}
+
+func NewHBox(children ...*Layout) *Layout {
+	return &Layout{kind: layoutHBox, children: children}
+}
+
+func NewGrid(columns int, children ...*Layout) *Layout {
+	return &Layout{kind: layoutGrid, Columns: columns, children: children}
+}
+
+func (l *Layout) Add(children ...*Layout) *Layout {
+	l.children = append(l.children, children...)
+	return l
+}
+
+func (l *Layout) Insert(i int, child *Layout) *Layout {
+	l.children = append(l.children, nil)
+	copy(l.children[i+1:], l.children[i:])
+	l.children[i] = child
+	return l
+}
+
+func (l *Layout) WithSpacing(spacing int32) *Layout {
+	l.Spacing = spacing
+	return l
+}
+
+func (l *Layout) WithPadding(padding Insets) *Layout {
+	l.Padding = padding
+	return l
+}
+
+func (l *Layout) WithMargin(margin Insets) *Layout {
+	l.Margin = margin
+	return l
+}
+
+func (l *Layout) WithAlign(x, y Align) *Layout {
+	l.AlignX = x
+	l.AlignY = y
+	return l
+}
+
+func (l *Layout) WithAnchor(anchor Anchor) *Layout {
+	l.Anchor = anchor
+	return l
+}
+
+func (l *Layout) WithMinSize(w, h int32) *Layout {
+	l.MinSize = sdl.Point{X: w, Y: h}
+	return l
+}
+
+func (l *Layout) WithMaxSize(w, h int32) *Layout {
+	l.MaxSize = sdl.Point{X: w, Y: h}
+	return l
+}
+
+// Widget drawn on a background around its content (the buttons and the controls), which the layout sizes
+type backgroundWidget interface {
+	rendering.Widget
+	ContentSize() (int32, int32)
+	SetBackgroundSize(w, h int32)
+}
+
+// Size of the widget's content: the text of a button (not its background)
+func contentSize(w rendering.Widget) (int32, int32) {
+	if widget, ok := w.(backgroundWidget); ok {
+		return widget.ContentSize()
+	}
+	return w.Width(), w.Height()
+}
+
+func (l *Layout) clamp(w, h int32) (int32, int32) {
+	if l.MinSize.X > 0 && w < l.MinSize.X {
+		w = l.MinSize.X
+	}
+	if l.MinSize.Y > 0 && h < l.MinSize.Y {
+		h = l.MinSize.Y
+	}
+	if l.MaxSize.X > 0 && w > l.MaxSize.X {
+		w = l.MaxSize.X
+	}
+	if l.MaxSize.Y > 0 && h > l.MaxSize.Y {
+		h = l.MaxSize.Y
+	}
+	return w, h
+}
+
+// widths of the columns and heights of the rows of a grid, with the children's margins
+func (l *Layout) gridCells() ([]int32, []int32) {
+	columns := l.Columns
+	if columns < 1 {
+		columns = 1
+	}
+	widths := make([]int32, columns)
+	heights := make([]int32, (len(l.children)+columns-1)/columns)
+	for i, child := range l.children {
+		w, h := child.Size()
+		widths[i%columns] = maxInt32(widths[i%columns], w)
+		heights[i/columns] = maxInt32(heights[i/columns], h)
+	}
+	return widths, heights
+}
+
+// total of the sizes with the spacing between them
+func spaced(sizes []int32, spacing int32) int32 {
+	var total int32
+	for _, size := range sizes {
+		total += size
+	}
+	if len(sizes) > 1 {
+		total += spacing * int32(len(sizes)-1)
+	}
+	return total
+}
+
+// Preferred size of the node with its margin
+func (l *Layout) Size() (int32, int32) {
+	var w, h int32
+	switch l.kind {
+	case layoutWidget:
+		w, h = contentSize(l.widget)
+	case layoutVBox, layoutHBox:
+		sizes := make([]int32, len(l.children))
+		for i, child := range l.children {
+			cw, ch := child.Size()
+			if l.kind == layoutVBox {
+				w = maxInt32(w, cw)
+				sizes[i] = ch
+			} else {
+				h = maxInt32(h, ch)
+				sizes[i] = cw
+			}
+		}
+		if l.kind == layoutVBox {
+			h = spaced(sizes, l.Spacing)
+		} else {
+			w = spaced(sizes, l.Spacing)
+		}
+	case layoutGrid:
+		widths, heights := l.gridCells()
+		w, h = spaced(widths, l.Spacing), spaced(heights, l.Spacing)
+	}
+	w, h = l.clamp(w+l.Padding.horizontal(), h+l.Padding.vertical())
+	return w + l.Margin.horizontal(), h + l.Margin.vertical()
+}
+
+// position and size of a node of the given size aligned in the space
+func align(a Align, start, space, size int32) (int32, int32) {
+	switch a {
+	case AlignStart:
+		return start, size
+	case AlignEnd:
+		return start + space - size, size
+	case AlignStretch:
+		return start, space
+	}
+	return start + (space-size)/2, size
+}
+
+// Places the node (with its margin) in the area
+func (l *Layout) place(area sdl.Rect) {
+	w, h := l.Size()
+	w, h = w-l.Margin.horizontal(), h-l.Margin.vertical()
+	inner := l.Margin.shrink(area)
+	l.Rect.X, l.Rect.W = align(l.AlignX, inner.X, inner.W, w)
+	l.Rect.Y, l.Rect.H = align(l.AlignY, inner.Y, inner.H, h)
+	// a stretched node stays in its max size
+	l.Rect.W, l.Rect.H = l.clamp(l.Rect.W, l.Rect.H)
+	content := l.Padding.shrink(l.Rect)
+
+	switch l.kind {
+	case layoutWidget:
+		if widget, ok := l.widget.(backgroundWidget); ok {
+			widget.SetBackgroundSize(l.Rect.W, l.Rect.H)
+			widget.SetTopLeft(l.Rect.X, l.Rect.Y)
+		} else {
+			l.widget.SetCenter(l.Rect.X+l.Rect.W/2, l.Rect.Y+l.Rect.H/2)
+		}
+	case layoutVBox:
+		y := content.Y
+		for _, child := range l.children {
+			_, ch := child.Size()
+			child.place(sdl.Rect{X: content.X, Y: y, W: content.W, H: ch})
+			y += ch + l.Spacing
+		}
+	case layoutHBox:
+		x := content.X
+		for _, child := range l.children {
+			cw, _ := child.Size()
+			child.place(sdl.Rect{X: x, Y: content.Y, W: cw, H: content.H})
+			x += cw + l.Spacing
+		}
+	case layoutGrid:
+		widths, heights := l.gridCells()
+		y := content.Y
+		for row, rowHeight := range heights {
+			x := content.X
+			for column, columnWidth := range widths {
+				i := row*len(widths) + column
+				if i >= len(l.children) {
+					break
+				}
+				l.children[i].place(sdl.Rect{X: x, Y: y, W: columnWidth, H: rowHeight})
+				x += columnWidth + l.Spacing
+			}
+			y += rowHeight + l.Spacing
+		}
+	}
+}
+
+// Places the tree on the screen, on the side of the root's anchor
+func (l *Layout) Resize(screen sdl.Rect) {
+	w, h := l.Size()
+	w, h = minInt32(w, screen.W), minInt32(h, screen.H)
+	x, y := screen.X+(screen.W-w)/2, screen.Y+(screen.H-h)/2
+	switch l.Anchor {
+	case AnchorLeft, AnchorTopLeft, AnchorBottomLeft:
+		x = screen.X
+	case AnchorRight, AnchorTopRight, AnchorBottomRight:
+		x = screen.X + screen.W - w
+	}
+	switch l.Anchor {
+	case AnchorTop, AnchorTopLeft, AnchorTopRight:
+		y = screen.Y
+	case AnchorBottom, AnchorBottomLeft, AnchorBottomRight:
+		y = screen.Y + screen.H - h
+	}
+	l.place(sdl.Rect{X: x, Y: y, W: w, H: h})
+}
+
+// Draws the widgets of the tree
+func (l *Layout) Draw(r *rendering.CustomRenderer) {
+	if l.kind == layoutWidget {
+		l.widget.Draw(r)
+		return
+	}
+	for _, child := range l.children {
+		child.Draw(r)
+	}
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,32 @@
+package menuControls
+
+import (
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+)
+
+const (
+	actionNone rendering.ButtonActionId = iota
+	actionExit
+	actionResetDefaults
+	// followed by the index of the key in config.GameControls.Keys
+	actionRebind
+)
+
+// names of the actions, by their id in the config file
+var actionNames = map[string]*string{
+	"up":           &lang.Text.Controls.Up,
+	"down":         &lang.Text.Controls.Down,
+	"left":         &lang.Text.Controls.Left,
+	"right":        &lang.Text.Controls.Right,
+	"open":         &lang.Text.Controls.Open,
+	"flag":         &lang.Text.Controls.Flag,
+	"chord":        &lang.Text.Controls.Chord,
+	"replay":       &lang.Text.Controls.Replay,
+	"zoom-in":      &lang.Text.Controls.ZoomIn,
+	"zoom-out":     &lang.Text.Controls.ZoomOut,
+	"recenter":     &lang.Text.Controls.Recenter,
+	"rotate-left":  &lang.Text.Controls.RotateLeft,
+	"rotate-right": &lang.Text.Controls.RotateRight,
+	"minimap":      &lang.Text.Controls.Minimap,
+}
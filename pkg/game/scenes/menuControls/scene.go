@@ -0,0 +1,513 @@
+package menuControls
+
+import (
+	"fmt"
+	"log"
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/game/theme"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Controls page: lists the game's actions with their bindings, clicking an action's bindings
+waits for the next key press (or mouse button, wheel, game controller button) to replace
+the first one.
+
+A binding already used by another action is swapped with it, the keys handled by the
+scenes themselves are refused. The bindings shared by several actions in the config file
+are marked with a warning. The config is saved when leaving the page
+*/
+type ControlsScene struct {
+	widgets      []rendering.Widget // all the widgets, for the drawing and the hover
+	title        *rendering.Textbox
+	labels       []*rendering.Textbox // names of the actions
+	keys         []*rendering.Button  // in the order of config.GameControls.Keys
+	message      *rendering.Textbox
+	warning      *rendering.Textbox // bindings shared by several actions, conflicts with the keys of the other scenes
+	reset        *rendering.Button
+	back         *rendering.Button
+	renderer     *rendering.CustomRenderer
+	sceneManager *scenes.SceneManager
+	font         *rendering.Font
+	theme        *theme.Theme // theme the widgets were created with
+	language     *lang.Language
+	input        *input.Context
+	focus        *scenes.FocusManager
+	layout       *scenes.Layout
+	actions      *scenes.Layout // grid of the labels and keys
+	backLayout   *scenes.Layout
+	waiting      int // index of the key waiting for a key press, -1 if none
+}
+
+func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer) (*ControlsScene, error) {
+	s := &ControlsScene{
+		renderer:     renderer,
+		sceneManager: sceneManager,
+		input:        input.NewContext(input.MenuBindings()),
+		focus:        scenes.NewFocusManager(),
+		waiting:      -1,
+	}
+	if err := s.rebuildWidgets(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newButton(text string, action rendering.ButtonActionId, renderer *sdl.Renderer, font *rendering.Font) (*rendering.Button, error) {
+	btn := rendering.NewButton(
+		sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
+		true,
+		true,
+		true,
+		text,
+		action,
+		theme.Palette.Text,
+		&theme.Palette.ButtonBackground,
+		&theme.Palette.ButtonHover,
+	)
+	if err := btn.UpdateTexture(renderer, font); err != nil {
+		return nil, err
+	}
+	return btn, nil
+}
+
+func newTextbox(text string, renderer *sdl.Renderer, font *rendering.Font) (*rendering.Textbox, error) {
+	return rendering.NewTextbox(sdl.Rect{X: 0, Y: 0, W: 10, H: 10}, true, true, text, renderer, font, theme.Palette.Text)
+}
+
+// (Re)creates the widgets with the scene manager's theme and language
+func (s *ControlsScene) rebuildWidgets() error {
+	var err error
+	currentTheme := s.sceneManager.GetTheme()
+	font := currentTheme.Font
+	renderer := s.renderer.SDLrenderer
+	cfg := s.sceneManager.GetConfig()
+	keys := cfg.Controls.Keys()
+
+	previous := s.widgets
+	s.widgets = make([]rendering.Widget, 0, 2*len(keys)+5)
+	s.labels = make([]*rendering.Textbox, len(keys))
+	s.keys = make([]*rendering.Button, len(keys))
+	if s.title, err = newTextbox(lang.Text.Controls.Title, renderer, font); err != nil {
+		return err
+	}
+	s.widgets = append(s.widgets, s.title)
+	for i, key := range keys {
+		if s.labels[i], err = newTextbox(actionName(key), renderer, font); err != nil {
+			return err
+		}
+		if s.keys[i], err = newButton(bindingsName(*key.Bindings, nil), actionRebind+rendering.ButtonActionId(i), renderer, font); err != nil {
+			return err
+		}
+		s.widgets = append(s.widgets, s.labels[i], s.keys[i])
+	}
+	if s.message, err = newTextbox("", renderer, font); err != nil {
+		return err
+	}
+	if s.warning, err = newTextbox("", renderer, font); err != nil {
+		return err
+	}
+	if s.reset, err = newButton(lang.Text.Controls.ResetDefaults, actionResetDefaults, renderer, font); err != nil {
+		return err
+	}
+	if s.back, err = newButton(lang.Text.Settings.GoBack, actionExit, renderer, font); err != nil {
+		return err
+	}
+	s.widgets = append(s.widgets, s.message, s.warning, s.reset, s.back)
+	s.focus.SetWidgets(s.widgets)
+	s.buildLayout(font)
+
+	destroyWidgets(previous)
+	s.font = font
+	s.theme = currentTheme
+	s.language = s.sceneManager.GetLanguage()
+	s.updateText()
+	return nil
+}
+
+func destroyWidgets(widgets []rendering.Widget) {
+	for _, widget := range widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		} else if tbox, ok := widget.(*rendering.Textbox); ok {
+			tbox.Destroy()
+		}
+	}
+}
+
+// Translated name of the action
+func actionName(key config.ControlKey) string {
+	if name, found := actionNames[key.Id]; found {
+		return *name
+	}
+	return key.Id
+}
+
+// Names of the bindings as written in the config ("Space / W", "Shift+Tab", "mouse:right"...), the conflicting ones are marked
+func bindingsName(bindings []config.Binding, conflicts map[config.Binding][]string) string {
+	names := make([]string, len(bindings))
+	for i, binding := range bindings {
+		names[i] = binding.String()
+		if _, found := conflicts[binding]; found {
+			names[i] = fmt.Sprintf(lang.Text.Controls.ConflictMark, names[i])
+		}
+	}
+	return strings.Join(names, " / ")
+}
+
+func firstConflict(keys []config.ControlKey, conflicts map[config.Binding][]string) (config.Binding, []string, bool) {
+	for _, key := range keys {
+		for _, binding := range *key.Bindings {
+			if ids, found := conflicts[binding]; found {
+				return binding, ids, true
+			}
+		}
+	}
+	return config.Binding{}, nil, false
+}
+
+// Translated name of the action with the id
+func actionNameOf(keys []config.ControlKey, id string) string {
+	for _, key := range keys {
+		if key.Id == id {
+			return actionName(key)
+		}
+	}
+	return id
+}
+
+func isModifierKey(code sdl.Keycode) bool {
+	switch code {
+	case sdl.K_LSHIFT, sdl.K_RSHIFT, sdl.K_LCTRL, sdl.K_RCTRL, sdl.K_LALT, sdl.K_RALT, sdl.K_LGUI, sdl.K_RGUI:
+		return true
+	}
+	return false
+}
+
+// Sets the keys' names and the warning about the shared bindings, or else about the keys used by the other scenes
+func (s *ControlsScene) updateText() {
+	cfg := s.sceneManager.GetConfig()
+	warning := ""
+	menuFullscreen := config.Binding{Kind: config.BindKey, Key: config.KeyMenuFullscreen}
+	keys := cfg.Controls.Keys()
+	conflicts := cfg.Controls.Conflicts()
+	for i, key := range keys {
+		text := bindingsName(*key.Bindings, conflicts)
+		if i == s.waiting {
+			text = lang.Text.Controls.PressKey
+		}
+		s.keys[i].SetText(text, s.renderer.SDLrenderer, s.font, theme.Palette.Text)
+		for _, binding := range *key.Bindings {
+			if binding == menuFullscreen {
+				warning = fmt.Sprintf(lang.Text.Controls.MenuFullscreen, menuFullscreen.String())
+			}
+		}
+	}
+	// the first shared binding, in the order of the actions, is more important
+	if binding, ids, found := firstConflict(keys, conflicts); found {
+		names := make([]string, len(ids))
+		for j, id := range ids {
+			names[j] = fmt.Sprintf("%q", actionNameOf(keys, id))
+		}
+		warning = fmt.Sprintf(lang.Text.Controls.KeyConflict, binding.String(), strings.Join(names, ", "))
+	}
+	s.warning.SetText(warning, s.renderer.SDLrenderer, s.font, theme.Palette.TextDisabled)
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+}
+
+func (s *ControlsScene) setMessage(text string) {
+	s.message.SetText(text, s.renderer.SDLrenderer, s.font, theme.Palette.Text)
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+}
+
+func (s *ControlsScene) startRebind(i int) {
+	cfg := s.sceneManager.GetConfig()
+	keys := cfg.Controls.Keys()
+	s.waiting = i
+	s.setMessage(fmt.Sprintf(lang.Text.Controls.WaitingKey, actionName(keys[i])))
+	s.updateText()
+}
+
+func (s *ControlsScene) cancelRebind() {
+	s.waiting = -1
+	s.setMessage("")
+	s.updateText()
+}
+
+// Replaces the i-th action's first binding, the action previously using the binding gets the replaced one (or loses it if there is none)
+func (s *ControlsScene) rebind(i int, binding config.Binding) {
+	s.waiting = -1
+	cfg := s.sceneManager.GetConfig()
+	keys := cfg.Controls.Keys()
+	name := binding.String()
+	switch parsed, err := config.ParseBinding(name); {
+	case err != nil || parsed != binding:
+		s.setMessage(lang.Text.Controls.KeyNoName)
+	case binding.IsReserved():
+		s.setMessage(fmt.Sprintf(lang.Text.Controls.KeyReserved, name))
+	default:
+		// an action whose default bindings were all used by the other actions has none, its new binding is taken from them
+		hasBinding := len(*keys[i].Names) > 0
+		previous := ""
+		if hasBinding {
+			previous = (*keys[i].Names)[0]
+		}
+		message := fmt.Sprintf(lang.Text.Controls.KeyChanged, actionName(keys[i]), name)
+		for j, other := range keys {
+			// the lists may be shared with the scene manager's config
+			names := config.KeyList{}
+			for k, b := range *other.Bindings {
+				if b != binding {
+					names = append(names, (*other.Names)[k])
+				} else if hasBinding {
+					names = append(names, previous)
+					if j != i {
+						message = fmt.Sprintf(lang.Text.Controls.KeySwapped, name, actionName(other), previous)
+					}
+				}
+			}
+			*other.Names = names
+		}
+		if hasBinding {
+			(*keys[i].Names)[0] = name
+		} else {
+			*keys[i].Names = config.KeyList{name}
+		}
+		if err := cfg.Check(); err != nil {
+			log.Printf("rebind: %s\n", err)
+			s.setMessage(err.Error())
+			break
+		}
+		s.sceneManager.SetConfig(cfg)
+		s.setMessage(message)
+	}
+	s.updateText()
+}
+
+func (s *ControlsScene) resetDefaults() {
+	s.waiting = -1
+	cfg := s.sceneManager.GetConfig()
+	cfg.Controls.Names = config.DefaultConfig.Controls.Names
+	if err := cfg.Check(); err != nil {
+		log.Printf("resetDefaults: %s\n", err)
+		return
+	}
+	s.sceneManager.SetConfig(cfg)
+	s.setMessage(lang.Text.Controls.DefaultsReset)
+	s.updateText()
+}
+
+func (s *ControlsScene) processButtonClick(b *rendering.Button) {
+	if b.ActionId != actionNone {
+		s.sceneManager.PlaySound(audio.SoundClick)
+	}
+	switch b.ActionId {
+	case actionNone:
+		return
+	case actionExit:
+		s.Exit()
+	case actionResetDefaults:
+		s.resetDefaults()
+	default:
+		if i := int(b.ActionId - actionRebind); i >= 0 && i < len(s.keys) {
+			s.startRebind(i)
+		}
+	}
+}
+
+// While waiting for a binding, every input is the new binding (Escape and the left click cancel)
+func (s *ControlsScene) processWaitingEvent(e sdl.Event) {
+	switch t := e.(type) {
+	case *sdl.KeyboardEvent:
+		// the modifiers are part of the next key's binding
+		if t.State != sdl.PRESSED || t.Repeat != 0 || isModifierKey(t.Keysym.Sym) {
+			return
+		}
+		if t.Keysym.Sym == config.KeyBack {
+			s.cancelRebind()
+			return
+		}
+		s.rebind(s.waiting, config.Binding{Kind: config.BindKey, Key: t.Keysym.Sym, Mod: config.BindingMod(t.Keysym.Mod)})
+	case *sdl.MouseButtonEvent:
+		if t.State != sdl.PRESSED {
+			return
+		}
+		if t.Button == sdl.BUTTON_LEFT {
+			s.cancelRebind()
+			return
+		}
+		s.rebind(s.waiting, config.Binding{Kind: config.BindMouseButton, Button: t.Button})
+	case *sdl.MouseWheelEvent:
+		if t.Y != 0 {
+			wheel := int32(1)
+			if (t.Y < 0) != (t.Direction == sdl.MOUSEWHEEL_FLIPPED) {
+				wheel = -1
+			}
+			s.rebind(s.waiting, config.Binding{Kind: config.BindWheel, Direction: wheel})
+		}
+	case *sdl.ControllerButtonEvent:
+		if t.State == sdl.PRESSED {
+			s.rebind(s.waiting, config.Binding{Kind: config.BindGamepadButton, Button: t.Button})
+		}
+	case *sdl.ControllerAxisEvent:
+		if direction := input.AxisDirection(t.Value); direction != 0 {
+			s.rebind(s.waiting, config.Binding{Kind: config.BindGamepadAxis, Button: t.Axis, Direction: direction})
+		}
+	}
+}
+
+func (s *ControlsScene) processAction(action input.Action) bool {
+	if used, clicked := s.focus.ProcessAction(action); used {
+		if btn, ok := clicked.(*rendering.Button); ok {
+			s.processButtonClick(btn)
+		}
+		return true
+	}
+	if action == input.Menu {
+		s.Exit()
+		return true
+	}
+	return false
+}
+
+func (s *ControlsScene) ProcessEvent(e sdl.Event) scenes.EventState {
+	if s.waiting != -1 {
+		switch e.(type) {
+		case *sdl.KeyboardEvent, *sdl.MouseButtonEvent, *sdl.MouseWheelEvent, *sdl.ControllerButtonEvent, *sdl.ControllerAxisEvent:
+			s.processWaitingEvent(e)
+			return scenes.EventProcessed
+		}
+	}
+	if t, ok := e.(*sdl.MouseButtonEvent); ok && t.State == sdl.PRESSED {
+		mousePos := sdl.Point{
+			X: t.X,
+			Y: t.Y,
+		}
+		for _, w := range s.widgets {
+			if btn, ok := w.(*rendering.Button); ok {
+				if btn.OnButton(mousePos) {
+					s.processButtonClick(btn)
+					return scenes.EventProcessed
+				}
+			}
+		}
+	}
+	for _, action := range s.input.Process(e) {
+		if action.Pressed && s.processAction(action.Action) {
+			return scenes.EventProcessed
+		}
+	}
+	return scenes.EventToProcess
+}
+
+/*
+Layout of the page: the title, the actions in a grid of label and key columns, the messages
+and the reset button. The "Go back" button is in the bottom-left corner
+*/
+func (s *ControlsScene) buildLayout(font *rendering.Font) {
+	lineHeight := int32(font.Height())
+	var margin int32 = 5
+	padding := scenes.UniformInsets(lineHeight / 2)
+	s.actions = scenes.NewGrid(2).WithSpacing(margin)
+	for i := range s.keys {
+		// the labels are aligned on the keys, the keys are as large as the largest one of their column
+		s.actions.Add(
+			scenes.NewWidgetLayout(s.labels[i]).WithAlign(scenes.AlignEnd, scenes.AlignCenter),
+			scenes.NewWidgetLayout(s.keys[i]).WithPadding(padding).WithAlign(scenes.AlignStretch, scenes.AlignCenter),
+		)
+	}
+	s.layout = scenes.NewVBox(
+		scenes.NewWidgetLayout(s.title).WithMinSize(0, 2*lineHeight),
+		s.actions,
+		scenes.NewWidgetLayout(s.message).WithMinSize(0, 2*lineHeight),
+		scenes.NewWidgetLayout(s.warning).WithMinSize(0, 2*lineHeight),
+		scenes.NewWidgetLayout(s.reset).WithPadding(padding),
+	).WithSpacing(margin)
+	s.backLayout = scenes.NewWidgetLayout(s.back).
+		WithPadding(padding).
+		WithMargin(scenes.UniformInsets(margin)).
+		WithAnchor(scenes.AnchorBottomLeft)
+}
+
+func (s *ControlsScene) ProcessResize(w, h int32) {
+	_, backHeight := s.backLayout.Size()
+	// the actions are split in more columns when they don't fit in the window's height
+	for s.actions.Columns = 2; s.actions.Columns < 2*len(s.keys); s.actions.Columns += 2 {
+		if _, height := s.layout.Size(); height <= h-2*backHeight {
+			break
+		}
+	}
+	s.layout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h - backHeight})
+	s.backLayout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h})
+}
+
+func (s *ControlsScene) Update(deltaMS uint64) {
+	for _, action := range s.input.Update(deltaMS) {
+		if s.waiting == -1 {
+			s.processAction(action.Action)
+		}
+	}
+	mousePos := sdl.Point{}
+	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.SetHovered(mousePos.InRect(&btn.Rect))
+		}
+	}
+}
+
+func (s *ControlsScene) Draw(renderer rendering.CustomRenderer) {
+	for _, widget := range s.widgets {
+		widget.Draw(&renderer)
+	}
+}
+
+// Saves the keys and goes back to the settings
+func (s *ControlsScene) Exit() {
+	s.waiting = -1
+	if err := config.SaveConfig(config.ConfigFilePath, s.sceneManager.GetConfig()); err != nil {
+		log.Printf("controls save error: %s\n", err)
+	}
+	s.sceneManager.Pop()
+}
+
+func (s *ControlsScene) Enter() error {
+	if s.theme != s.sceneManager.GetTheme() || s.language != s.sceneManager.GetLanguage() {
+		if err := s.rebuildWidgets(); err != nil {
+			return err
+		}
+	}
+	s.waiting = -1
+	s.input.Reset()
+	s.setMessage("")
+	s.updateText()
+	return nil
+}
+
+func (s *ControlsScene) Pause() {
+	s.waiting = -1
+}
+
+func (s *ControlsScene) Resume() error {
+	return s.Enter()
+}
+
+func (s *ControlsScene) Unload() {
+}
+
+func (s *ControlsScene) IsLoaded() bool {
+	return true
+}
+
+func (s *ControlsScene) NeedsRedraw() bool {
+	return true
+}
@@ -1,32 +1,23 @@
 package menuMain
 
 import (
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/theme"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-var (
-	backgroundColor = sdl.Color{R: 0, G: 0, B: 0, A: sdl.ALPHA_OPAQUE}
-	hoverColor      = sdl.Color{R: 255, G: 255, B: 0, A: sdl.ALPHA_OPAQUE}
-	secondaryColor  = sdl.Color{R: 255, G: 255, B: 255, A: sdl.ALPHA_OPAQUE}
-	// primaryColor    = sdl.Color{R: 255, G: 50, B: 255, A: sdl.ALPHA_OPAQUE}
-	tertiaryColor = sdl.Color{R: 120, G: 120, B: 120, A: sdl.ALPHA_OPAQUE}
-	// wipColor        = sdl.Color{R: 255, G: 0, B: 0, A: 100}
-)
-
 const (
 	actionNone rendering.ButtonActionId = iota
 	actionOpenSettingsMenu
 	actionOpenNewGame
 	actionOpenLastGame
+	actionOpenStatistics
 	actionOpenBrowserGithub
 	actionOpenBrowserInstagram
 	actionExit
 
-	githubLogoFile    = "github.png"
-	instagramLogoFile = "instagram.png"
-
 	githubURL   = "https://github.com/nhoualet"
 	intagramURL = "https://www.instagram.com/13noodles_"
 )
@@ -40,30 +31,30 @@ const (
 
 type widgetLoadingData struct {
 	wType           widgetType
-	text            string
+	text            *string // in lang.Text for the translated texts
 	action          rendering.ButtonActionId
 	textColor       *sdl.Color
 	backgroundColor *sdl.Color
 	hoverColor      *sdl.Color
 }
 
-const (
-	textTitle              = "Isometric minesweeper"
-	textButtonNewGame      = "New Game"
-	textButtonContinueGame = "Continue"
-	textButtonExit         = "Exit"
-	textButtonSettings     = "Settings"
+// not translated
+var (
+	textEmpty         = ""
+	githubLogoFile    = "github.png"
+	instagramLogoFile = "instagram.png"
 )
 
 var widgetsData = [...]widgetLoadingData{
-	{textboxWidget, textTitle, actionNone, &secondaryColor, nil, nil},
-	{textboxWidget, "", actionNone, &secondaryColor, nil, nil},
-	{buttonWidget, textButtonNewGame, actionOpenNewGame, &secondaryColor, &backgroundColor, &tertiaryColor},
-	{buttonWidget, textButtonContinueGame, actionOpenLastGame, &secondaryColor, &backgroundColor, &tertiaryColor},
-	{buttonWidget, textButtonSettings, actionOpenSettingsMenu, &secondaryColor, &backgroundColor, &hoverColor},
-	{buttonWidget, textButtonExit, actionExit, &secondaryColor, &backgroundColor, &hoverColor},
+	{textboxWidget, &lang.Text.MainMenu.Title, actionNone, &theme.Palette.Text, nil, nil},
+	{textboxWidget, &textEmpty, actionNone, &theme.Palette.Text, nil, nil},
+	{buttonWidget, &lang.Text.MainMenu.NewGame, actionOpenNewGame, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHoverMenu},
+	{buttonWidget, &lang.Text.MainMenu.ResumeGame, actionOpenLastGame, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHoverMenu},
+	{buttonWidget, &lang.Text.MainMenu.Statistics, actionOpenStatistics, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHoverMenu},
+	{buttonWidget, &lang.Text.MainMenu.Settings, actionOpenSettingsMenu, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHoverAccent},
+	{buttonWidget, &lang.Text.MainMenu.Exit, actionExit, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHoverAccent},
 }
 var socialsWidgetData = [...]widgetLoadingData{
-	{buttonWidget, githubLogoFile, actionOpenBrowserGithub, &secondaryColor, nil, nil},
-	{buttonWidget, instagramLogoFile, actionOpenBrowserInstagram, &secondaryColor, nil, nil},
+	{buttonWidget, &githubLogoFile, actionOpenBrowserGithub, &theme.Palette.Text, nil, nil},
+	{buttonWidget, &instagramLogoFile, actionOpenBrowserInstagram, &theme.Palette.Text, nil, nil},
 }
@@ -2,36 +2,50 @@ package menuMain
 
 import (
 	"errors"
-	"minesweeper/pkg/config"
+	"log"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
 	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/game/theme"
 
 	"github.com/pkg/browser"
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/veandco/go-sdl2/ttf"
 )
 
 type MainScene struct {
-	widgets          []rendering.Widget
-	renderer         *rendering.CustomRenderer
-	sceneManager     *scenes.SceneManager
-	font             *ttf.Font
-	lang             config.MainMenuLang
-	selectedWidgetID int
+	widgets      []rendering.Widget
+	renderer     *rendering.CustomRenderer
+	sceneManager *scenes.SceneManager
+	font         *rendering.Font
+	focus        *scenes.FocusManager
+	layout       *scenes.Layout // the title and the buttons
+	socials      *scenes.Layout
+	theme        *theme.Theme // theme the widgets were created with
+	language     *lang.Language
+	input        *input.Context
 }
 
-func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer, font *ttf.Font, lang config.MainMenuLang) (*MainScene, error) {
-	var err error
-	btnCount := 0
-	textboxCount := 0
-	cfg := sceneManager.GetConfig()
-	for _, widget := range widgetsData {
-		if widget.wType == buttonWidget {
-			btnCount += 1
-		} else {
-			textboxCount += 1
-		}
+func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer) (*MainScene, error) {
+	s := &MainScene{
+		renderer:     renderer,
+		sceneManager: sceneManager,
+		focus:        scenes.NewFocusManager(),
+		input:        input.NewContext(input.MainMenuBindings()),
 	}
+	if err := s.rebuildWidgets(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// (Re)creates the widgets with the scene manager's theme and language
+func (s *MainScene) rebuildWidgets() error {
+	var err error
+	currentTheme := s.sceneManager.GetTheme()
+	font := currentTheme.Font
+	cfg := s.sceneManager.GetConfig()
 	widgets := make([]rendering.Widget, len(widgetsData)+len(socialsWidgetData))
 	for i, widget := range widgetsData {
 		if widget.wType == buttonWidget {
@@ -41,14 +55,14 @@ func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRen
 				true,
 				true,
 				selectable,
-				widget.text,
+				*widget.text,
 				widget.action,
 				*widget.textColor,
 				widget.backgroundColor,
 				widget.hoverColor,
 			)
-			if err := btn.UpdateTexture(renderer.SDLrenderer, font); err != nil {
-				return nil, err
+			if err := btn.UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+				return err
 			}
 			widgets[i] = btn
 		} else {
@@ -56,14 +70,14 @@ func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRen
 				sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
 				true,
 				true,
-				widget.text,
-				renderer.SDLrenderer,
+				*widget.text,
+				s.renderer.SDLrenderer,
 				font,
 				*widget.textColor,
 			)
 
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
@@ -81,42 +95,57 @@ func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRen
 				nil,
 				widget.hoverColor,
 			)
-			if err := btn.UpdateTexture(renderer.SDLrenderer, font); err != nil {
-				return nil, err
+			if err := btn.UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+				return err
 			}
 			widgets[len(widgetsData)+i] = btn
 			if w, ok := widgets[len(widgetsData)+i].(*rendering.Button); ok {
-				texture, err := renderer.LoadTexture(cfg.Window.ResourcesPath + widget.text)
+				texture, err := s.renderer.LoadTexture(cfg.Window.ResourcesPath + *widget.text)
 				if err != nil {
-					return nil, err
+					return err
 				}
 				w.SetTexture(texture)
 			}
 		} else {
-			return nil, errors.New("got socialsWidgetData with wtype != buttonWidget")
+			return errors.New("got socialsWidgetData with wtype != buttonWidget")
 		}
 	}
-	s := &MainScene{
-		widgets:          widgets,
-		font:             font,
-		renderer:         renderer,
-		sceneManager:     sceneManager,
-		selectedWidgetID: -1,
-		lang:             lang,
+	s.destroyWidgets()
+	s.widgets = widgets
+	s.buildLayout(font)
+	s.font = font
+	s.theme = currentTheme
+	s.language = s.sceneManager.GetLanguage()
+	s.focus.SetWidgets(widgets)
+	return nil
+}
+
+func (s *MainScene) destroyWidgets() {
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		} else if tbox, ok := widget.(*rendering.Textbox); ok {
+			tbox.Destroy()
+		}
 	}
-	return s, nil
+	s.widgets = nil
 }
 
 func (s *MainScene) processButtonClick(b *rendering.Button) {
+	if b.ActionId != actionNone {
+		s.sceneManager.PlaySound(audio.SoundClick)
+	}
 	switch b.ActionId {
 	case actionNone:
 		return
 	case actionOpenSettingsMenu:
-		s.sceneManager.SetScene("settings", false)
+		s.sceneManager.Push("settings")
 	case actionOpenNewGame:
-		s.sceneManager.SetScene("game", true)
+		s.newGame()
 	case actionOpenLastGame:
-		s.sceneManager.SetScene("game", false)
+		s.sceneManager.Push("game")
+	case actionOpenStatistics:
+		s.sceneManager.Push("statistics")
 	case actionOpenBrowserGithub:
 		browser.OpenURL(githubURL)
 	case actionOpenBrowserInstagram:
@@ -127,6 +156,43 @@ func (s *MainScene) processButtonClick(b *rendering.Button) {
 
 }
 
+// Starts a new game, asking first if one is in progress
+func (s *MainScene) newGame() {
+	gameScene, err := s.sceneManager.GetScene("game")
+	if err != nil {
+		return
+	}
+	game, ok := gameScene.(scenes.GameSession)
+	if !ok {
+		return
+	}
+	if !game.InProgress() {
+		s.startGame(game)
+		return
+	}
+	text := &lang.Text.Dialog
+	options := []string{text.StartNewGame, text.ContinueGame, text.Cancel}
+	dialog := scenes.NewChoiceDialog(text.NewGameTitle, text.NewGame, options, func(choice int) {
+		switch choice {
+		case 0:
+			s.startGame(game)
+		case 1:
+			s.sceneManager.Push("game")
+		}
+	})
+	if err := s.sceneManager.ShowDialog(dialog); err != nil {
+		log.Printf("new game: %s\n", err)
+	}
+}
+
+func (s *MainScene) startGame(game scenes.GameSession) {
+	if err := game.NewGame(); err != nil {
+		log.Printf("new game: %s\n", err)
+		return
+	}
+	s.sceneManager.Push("game")
+}
+
 func (s *MainScene) ProcessEvent(e sdl.Event) scenes.EventState {
 	switch t := e.(type) {
 	case *sdl.MouseButtonEvent:
@@ -144,139 +210,67 @@ func (s *MainScene) ProcessEvent(e sdl.Event) scenes.EventState {
 				}
 			}
 		}
-
-	case *sdl.KeyboardEvent:
-		keyCode := t.Keysym.Sym
-		pressed := (t.State == sdl.PRESSED)
-		if pressed {
-			if keyCode == sdl.K_ESCAPE {
-				s.sceneManager.Quit()
-				return scenes.EventProcessed
-			} else if keyCode == sdl.K_f {
-				s.renderer.ToggleFullscreen()
-			} else if keyCode == sdl.K_TAB {
-				if (sdl.GetModState() & sdl.KMOD_SHIFT) != 0 {
-					s.selectPreviousWidget()
-
-				} else {
-					s.selectNextWidget()
-				}
-			} else if keyCode == sdl.K_RETURN && s.selectedWidgetID != -1 {
-				if btn, ok := s.widgets[s.selectedWidgetID].(*rendering.Button); ok {
-					s.processButtonClick(btn)
-				}
-			}
+	}
+	for _, action := range s.input.Process(e) {
+		if action.Pressed && s.processAction(action.Action) {
+			return scenes.EventProcessed
 		}
 	}
 	return scenes.EventToProcess
 }
 
-func (s *MainScene) toggleSelect(w interface{ rendering.Selectable }) {
-	w.Selected(!w.IsSelected())
-}
-
-func (s *MainScene) selectPreviousWidget() {
-	if s.selectedWidgetID == -1 {
-		var widgetID int
-		for widgetID = len(s.widgets) - 1; widgetID > -1; {
-			if newSelected, ok := s.widgets[widgetID].(rendering.Selectable); ok && newSelected.IsSelectable() {
-				s.toggleSelect(newSelected)
-				s.selectedWidgetID = widgetID
-				break
-			}
-			widgetID += 1
-		}
-
-	} else {
-		var widgetID int
-		for widgetID = s.selectedWidgetID - 1; widgetID != s.selectedWidgetID; {
-			if widgetID == -1 {
-				widgetID = len(s.widgets) - 1
-			}
-			if newSelected, ok := s.widgets[widgetID].(rendering.Selectable); ok && newSelected.IsSelectable() {
-				if oldSelected, ok := s.widgets[s.selectedWidgetID].(rendering.Selectable); ok {
-					s.toggleSelect(oldSelected)
-				}
-				s.toggleSelect(newSelected)
-				s.selectedWidgetID = widgetID
-				break
-			}
-			widgetID -= 1
+func (s *MainScene) processAction(action input.Action) bool {
+	if used, clicked := s.focus.ProcessAction(action); used {
+		if btn, ok := clicked.(*rendering.Button); ok {
+			s.processButtonClick(btn)
 		}
+		return true
 	}
-}
-
-func (s *MainScene) selectNextWidget() {
-	if s.selectedWidgetID == -1 {
-		var widgetID int
-		for widgetID = 0; widgetID < len(s.widgets)-1; {
-			if newSelected, ok := s.widgets[widgetID].(rendering.Selectable); ok && newSelected.IsSelectable() {
-				s.toggleSelect(newSelected)
-				s.selectedWidgetID = widgetID
-				break
-			}
-			widgetID += 1
-		}
-
-	} else {
-		var widgetID int
-		for widgetID = s.selectedWidgetID + 1; widgetID != s.selectedWidgetID; {
-			if widgetID == len(s.widgets) {
-				widgetID = 0
-			}
-			if newSelected, ok := s.widgets[widgetID].(rendering.Selectable); ok && newSelected.IsSelectable() {
-				if oldSelected, ok := s.widgets[s.selectedWidgetID].(rendering.Selectable); ok {
-					s.toggleSelect(oldSelected)
-				}
-				s.toggleSelect(newSelected)
-				s.selectedWidgetID = widgetID
-				break
-			}
-			widgetID += 1
-		}
+	switch action {
+	case input.Menu:
+		s.sceneManager.Quit()
+	case input.Fullscreen:
+		s.renderer.ToggleFullscreen()
+	default:
+		return false
 	}
+	return true
 }
 
-func (s *MainScene) ProcessResize(w, h int32) {
-	var maxWidth int32 = 0
-	var maxHeight int32 = 0
-
-	for _, w := range s.widgets[:len(s.widgets)-len(socialsWidgetData)] {
-		if btn, ok := w.(*rendering.Button); ok {
-			if btn.TextureRect.W > maxWidth {
-				maxWidth = btn.TextureRect.W
-			}
-			if btn.TextureRect.H > maxHeight {
-				maxHeight = btn.TextureRect.H
-			}
+// Layouts of the widgets, the buttons are as large as the largest one
+func (s *MainScene) buildLayout(font *rendering.Font) {
+	lineHeight := int32(font.Height())
+	var margin int32 = 5
+	s.layout = scenes.NewVBox().WithSpacing(margin)
+	for _, widget := range s.widgets[:len(widgetsData)] {
+		node := scenes.NewWidgetLayout(widget).WithAlign(scenes.AlignStretch, scenes.AlignCenter)
+		if _, ok := widget.(*rendering.Button); ok {
+			node.WithPadding(scenes.UniformInsets(lineHeight / 2))
+		} else {
+			node.WithMinSize(0, lineHeight)
 		}
+		s.layout.Add(node)
 	}
-
-	padding := maxHeight
-	maxHeight += padding
-	maxWidth += padding
-	var margin int32 = 5
-	top := (h - maxHeight*int32(len(s.widgets))) / 2
-	for i, widget := range s.widgets[:len(s.widgets)-len(socialsWidgetData)] {
-		widget.SetCenter(w/2, top+int32(i)*(maxHeight+margin))
+	s.socials = scenes.NewHBox().WithSpacing(margin).WithMargin(scenes.UniformInsets(margin)).WithAnchor(scenes.AnchorBottom)
+	for _, widget := range s.widgets[len(widgetsData):] {
 		if btn, ok := widget.(*rendering.Button); ok {
-			btn.SetBackgroundSize(maxWidth, maxHeight)
+			// the logos are as high as the buttons
+			btn.SetTextureSize(2*lineHeight, 2*lineHeight)
 		}
+		s.socials.Add(scenes.NewWidgetLayout(widget))
 	}
+}
 
-	linksLeft := (w-(maxHeight+margin)*int32(len(socialsWidgetData)))/2 + maxHeight/2
-
-	for i := len(s.widgets) - len(socialsWidgetData); i < len(s.widgets); i++ {
-		if btn, ok := s.widgets[i].(*rendering.Button); ok {
-			btn.SetCenter(linksLeft, h-margin-maxHeight/2)
-			btn.SetBackgroundSize(maxHeight, maxHeight)
-			btn.SetTextureSize(maxHeight, maxHeight)
-			linksLeft += maxHeight + margin
-		}
-	}
+func (s *MainScene) ProcessResize(w, h int32) {
+	screen := sdl.Rect{X: 0, Y: 0, W: w, H: h}
+	s.layout.Resize(screen)
+	s.socials.Resize(screen)
 }
 
 func (s *MainScene) Update(deltaMS uint64) {
+	for _, action := range s.input.Update(deltaMS) {
+		s.processAction(action.Action)
+	}
 	mousePos := sdl.Point{}
 	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
 	for _, widget := range s.widgets {
@@ -295,10 +289,10 @@ func (s *MainScene) Exit() {
 	// sm.Quit()
 }
 
-func (s *MainScene) Enter(reload bool) error {
-	if reload {
-		err := s.load()
-		if err != nil {
+func (s *MainScene) Enter() error {
+	s.input.Reset()
+	if s.theme != s.sceneManager.GetTheme() || s.language != s.sceneManager.GetLanguage() {
+		if err := s.rebuildWidgets(); err != nil {
 			return err
 		}
 	}
@@ -306,12 +300,12 @@ func (s *MainScene) Enter(reload bool) error {
 		continueWidget := s.widgets[3]
 		if continueBtn, ok := continueWidget.(*rendering.Button); ok {
 			if gameScene.IsLoaded() {
-				continueBtn.SetText(continueBtn.Text, s.renderer.SDLrenderer, s.font, rendering.ColorWhite)
-				continueBtn.SetHoverColor(&rendering.ColorWhite)
+				continueBtn.SetText(continueBtn.Text, s.renderer.SDLrenderer, s.font, theme.Palette.Text)
+				continueBtn.SetHoverColor(&theme.Palette.ButtonHover)
 				continueBtn.SetSelectable(true)
 				continueBtn.SetAction(actionOpenLastGame)
 			} else {
-				continueBtn.SetText(continueBtn.Text, s.renderer.SDLrenderer, s.font, rendering.ColorGrey)
+				continueBtn.SetText(continueBtn.Text, s.renderer.SDLrenderer, s.font, theme.Palette.TextDisabled)
 				continueBtn.SetSelectable(false)
 				continueBtn.SetHoverColor(nil)
 				continueBtn.SetAction(actionNone)
@@ -324,8 +318,12 @@ func (s *MainScene) Enter(reload bool) error {
 	return nil
 }
 
-func (s *MainScene) load() error {
-	return nil
+func (s *MainScene) Pause() {
+}
+
+// The game may have been started or the theme and language changed
+func (s *MainScene) Resume() error {
+	return s.Enter()
 }
 
 func (s *MainScene) Unload() {
@@ -0,0 +1,30 @@
+package menuPause
+
+import (
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+)
+
+const (
+	actionNone rendering.ButtonActionId = iota
+	actionResume
+	actionRestart
+	actionNewGame
+	actionOpenSettingsMenu
+	actionQuitToMenu
+	actionQuitToDesktop
+)
+
+type buttonLoadingData struct {
+	text   *string // in lang.Text
+	action rendering.ButtonActionId
+}
+
+var buttonsData = [...]buttonLoadingData{
+	{&lang.Text.Pause.Resume, actionResume},
+	{&lang.Text.Pause.Restart, actionRestart},
+	{&lang.Text.Pause.NewGame, actionNewGame},
+	{&lang.Text.Pause.Settings, actionOpenSettingsMenu},
+	{&lang.Text.Pause.QuitToMenu, actionQuitToMenu},
+	{&lang.Text.Pause.QuitToDesktop, actionQuitToDesktop},
+}
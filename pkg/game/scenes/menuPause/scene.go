@@ -0,0 +1,268 @@
+package menuPause
+
+import (
+	"log"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/game/theme"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Pause menu, pushed as an overlay over the game: the board stays drawn under it and the game
+(with its timer) is paused until it is popped.
+
+"Save and quit to menu" writes the game in config.SaveFilePath and keeps it in memory, it is
+continued with the main menu's Continue button, also after the program is restarted
+*/
+type PauseScene struct {
+	widgets      []rendering.Widget // the title then the buttons
+	renderer     *rendering.CustomRenderer
+	sceneManager *scenes.SceneManager
+	focus        *scenes.FocusManager
+	layout       *scenes.Layout
+	theme        *theme.Theme // theme the widgets were created with
+	language     *lang.Language
+	input        *input.Context
+}
+
+func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer) (*PauseScene, error) {
+	s := &PauseScene{
+		renderer:     renderer,
+		sceneManager: sceneManager,
+		focus:        scenes.NewFocusManager(),
+		input:        input.NewContext(input.PauseBindings()),
+	}
+	if err := s.rebuildWidgets(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// (Re)creates the widgets with the scene manager's theme and language
+func (s *PauseScene) rebuildWidgets() error {
+	currentTheme := s.sceneManager.GetTheme()
+	font := currentTheme.Font
+	lineHeight := int32(font.Height())
+	title, err := rendering.NewTextbox(sdl.Rect{}, true, true, lang.Text.Pause.Title, s.renderer.SDLrenderer, font, theme.Palette.Text)
+	if err != nil {
+		return err
+	}
+	widgets := []rendering.Widget{title}
+	layout := scenes.NewVBox().WithSpacing(lineHeight / 4).WithPadding(scenes.UniformInsets(lineHeight))
+	layout.Add(scenes.NewWidgetLayout(title).WithMinSize(0, 2*lineHeight))
+	for _, data := range buttonsData {
+		btn := rendering.NewButton(
+			sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
+			true,
+			true,
+			true,
+			*data.text,
+			data.action,
+			theme.Palette.Text,
+			&theme.Palette.ButtonBackground,
+			&theme.Palette.ButtonHoverMenu,
+		)
+		if err := btn.UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+			return err
+		}
+		widgets = append(widgets, btn)
+		layout.Add(scenes.NewWidgetLayout(btn).
+			WithPadding(scenes.UniformInsets(lineHeight/2)).
+			WithAlign(scenes.AlignStretch, scenes.AlignCenter))
+	}
+	s.destroyWidgets()
+	s.widgets = widgets
+	s.layout = layout
+	s.theme = currentTheme
+	s.language = s.sceneManager.GetLanguage()
+	s.focus.SetWidgets(widgets)
+	return nil
+}
+
+func (s *PauseScene) destroyWidgets() {
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		} else if tbox, ok := widget.(*rendering.Textbox); ok {
+			tbox.Destroy()
+		}
+	}
+	s.widgets = nil
+}
+
+// the paused game, nil if there is none
+func (s *PauseScene) game() scenes.GameSession {
+	gameScene, err := s.sceneManager.GetScene("game")
+	if err != nil {
+		return nil
+	}
+	game, _ := gameScene.(scenes.GameSession)
+	return game
+}
+
+// Runs the action after a confirmation if the game is in progress
+func (s *PauseScene) confirm(title, message string, action func()) {
+	if game := s.game(); game == nil || !game.InProgress() {
+		action()
+		return
+	}
+	dialog := scenes.NewConfirmDialog(title, message, func(confirmed bool) {
+		if confirmed {
+			action()
+		}
+	})
+	if err := s.sceneManager.ShowDialog(dialog); err != nil {
+		log.Printf("pause menu: %s\n", err)
+	}
+}
+
+// Saves the game before going back to the main menu, a failed save is shown before
+func (s *PauseScene) saveAndQuit() {
+	game := s.game()
+	if game == nil || !game.InProgress() {
+		s.sceneManager.PopToDefault()
+		return
+	}
+	err := game.Save()
+	if err == nil {
+		s.sceneManager.PopToDefault()
+		return
+	}
+	log.Println(err)
+	dialog := scenes.NewAlertDialog(lang.Text.Dialog.SaveFailed, err.Error(), func() { s.sceneManager.PopToDefault() })
+	if err := s.sceneManager.ShowDialog(dialog); err != nil {
+		log.Printf("pause menu: %s\n", err)
+	}
+}
+
+func (s *PauseScene) processButtonClick(b *rendering.Button) {
+	if b.ActionId != actionNone {
+		s.sceneManager.PlaySound(audio.SoundClick)
+	}
+	text := &lang.Text.Dialog
+	switch b.ActionId {
+	case actionNone:
+		return
+	case actionResume:
+		s.Exit()
+	case actionRestart:
+		s.confirm(text.RestartTitle, text.Restart, func() {
+			s.restart(scenes.GameSession.Restart)
+		})
+	case actionNewGame:
+		s.confirm(text.NewGameTitle, text.NewGame, func() {
+			s.restart(scenes.GameSession.NewGame)
+		})
+	case actionOpenSettingsMenu:
+		s.sceneManager.Push("settings")
+	case actionQuitToMenu:
+		s.saveAndQuit()
+	case actionQuitToDesktop:
+		s.confirm(text.QuitTitle, text.Quit, s.sceneManager.Quit)
+	}
+}
+
+// starts the game over (the same board or a new one) and resumes it
+func (s *PauseScene) restart(start func(scenes.GameSession) error) {
+	if game := s.game(); game != nil {
+		if err := start(game); err != nil {
+			log.Printf("pause menu: %s\n", err)
+		}
+	}
+	s.Exit()
+}
+
+func (s *PauseScene) ProcessEvent(e sdl.Event) scenes.EventState {
+	if t, ok := e.(*sdl.MouseButtonEvent); ok && t.State == sdl.PRESSED {
+		for _, w := range s.widgets {
+			if btn, ok := w.(*rendering.Button); ok && btn.OnButton(sdl.Point{X: t.X, Y: t.Y}) {
+				s.processButtonClick(btn)
+				return scenes.EventProcessed
+			}
+		}
+	}
+	for _, action := range s.input.Process(e) {
+		if action.Pressed && s.processAction(action.Action) {
+			return scenes.EventProcessed
+		}
+	}
+	// the game under the overlay doesn't get the events
+	return scenes.EventProcessed
+}
+
+func (s *PauseScene) processAction(action input.Action) bool {
+	if action == input.Menu {
+		s.Exit()
+		return true
+	}
+	used, clicked := s.focus.ProcessAction(action)
+	if btn, ok := clicked.(*rendering.Button); ok {
+		s.processButtonClick(btn)
+	}
+	return used
+}
+
+func (s *PauseScene) ProcessResize(w, h int32) {
+	s.layout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h})
+}
+
+func (s *PauseScene) Update(deltaMS uint64) {
+	for _, action := range s.input.Update(deltaMS) {
+		s.processAction(action.Action)
+	}
+	mousePos := sdl.Point{}
+	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.SetHovered(mousePos.InRect(&btn.Rect))
+		}
+	}
+}
+
+func (s *PauseScene) Draw(renderer rendering.CustomRenderer) {
+	scenes.DrawOverlayPanel(&renderer, s.layout.Rect)
+	s.layout.Draw(&renderer)
+}
+
+// Resumes the game
+func (s *PauseScene) Exit() {
+	s.sceneManager.Pop()
+}
+
+// The first button (Resume) is focused, for the keyboard and the game controllers
+func (s *PauseScene) Enter() error {
+	s.input.Reset()
+	if s.theme != s.sceneManager.GetTheme() || s.language != s.sceneManager.GetLanguage() {
+		if err := s.rebuildWidgets(); err != nil {
+			return err
+		}
+	}
+	s.focus.Focus(1)
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+	return nil
+}
+
+func (s *PauseScene) Pause() {
+}
+
+// The settings may have changed the theme or the language
+func (s *PauseScene) Resume() error {
+	return s.Enter()
+}
+
+func (s *PauseScene) Unload() {
+}
+
+func (s *PauseScene) IsLoaded() bool {
+	return true
+}
+
+func (s *PauseScene) NeedsRedraw() bool {
+	return true
+}
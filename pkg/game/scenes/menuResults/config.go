@@ -0,0 +1,28 @@
+package menuResults
+
+import (
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+)
+
+const (
+	actionNone rendering.ButtonActionId = iota
+	actionReplay
+	actionNewBoard
+	actionSaveReplay
+	actionExportBoard
+	actionMainMenu
+)
+
+type buttonLoadingData struct {
+	text   *string // in lang.Text
+	action rendering.ButtonActionId
+}
+
+var buttonsData = [...]buttonLoadingData{
+	{&lang.Text.Results.Replay, actionReplay},
+	{&lang.Text.Results.NewBoard, actionNewBoard},
+	{&lang.Text.Results.SaveReplay, actionSaveReplay},
+	{&lang.Text.Results.ExportBoard, actionExportBoard},
+	{&lang.Text.Results.MainMenu, actionMainMenu},
+}
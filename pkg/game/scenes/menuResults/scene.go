@@ -0,0 +1,297 @@
+package menuResults
+
+import (
+	"fmt"
+	"log"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/game/theme"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Results of the finished game, pushed by the game as an overlay once the board is revealed.
+
+Closing it (Escape or B) shows the finished board, the game's Replay key still starts a new one
+*/
+type ResultsScene struct {
+	widgets      []rendering.Widget // the title, the lines of the results then the buttons
+	renderer     *rendering.CustomRenderer
+	sceneManager *scenes.SceneManager
+	focus        *scenes.FocusManager
+	layout       *scenes.Layout
+	input        *input.Context
+}
+
+func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer) (*ResultsScene, error) {
+	s := &ResultsScene{
+		renderer:     renderer,
+		sceneManager: sceneManager,
+		focus:        scenes.NewFocusManager(),
+		input:        input.NewContext(input.PauseBindings()),
+	}
+	return s, nil
+}
+
+// the finished game, nil if there is none
+func (s *ResultsScene) game() scenes.GameSession {
+	gameScene, err := s.sceneManager.GetScene("game")
+	if err != nil {
+		return nil
+	}
+	game, _ := gameScene.(scenes.GameSession)
+	return game
+}
+
+// label and value of each line of the results
+func resultLines(r scenes.GameResults) [][2]string {
+	text := &lang.Text.Results
+	lives := fmt.Sprintf("%d/%d", r.LivesUsed, r.Lives)
+	if r.Lives < 0 {
+		lives = fmt.Sprintf(text.NoLivesLimit, r.LivesUsed)
+	}
+	// the first opening is free
+	efficiency := text.None
+	if r.Won && r.Clicks > 0 {
+		efficiency = fmt.Sprintf(text.EfficiencyValue, (r.BoardValue-1)*100/r.Clicks, r.BoardValue)
+	}
+	best := text.NoBest
+	if r.NewBest {
+		best = text.NewBest
+	} else if r.BestMS > 0 {
+		best = scenes.FormatDuration(r.BestMS)
+		if r.Won {
+			best += fmt.Sprintf(" (+%s)", scenes.FormatDuration(r.DurationMS-r.BestMS))
+		}
+	}
+	return [][2]string{
+		{text.Time, scenes.FormatDuration(r.DurationMS)},
+		{text.TilesOpened, fmt.Sprintf("%d/%d", r.TilesOpened, r.SafeTiles)},
+		{text.Flags, fmt.Sprintf(text.FlagsValue, r.FlagsRight, r.FlagsWrong)},
+		{text.LivesUsed, lives},
+		{text.HintsUndos, fmt.Sprintf(text.HintsUndosValue, r.Hints, r.Undos)},
+		{text.Clicks, fmt.Sprint(r.Clicks)},
+		{text.Efficiency, efficiency},
+		{text.Seed, fmt.Sprintf("%d (%dx%d, %d%%)", r.Seed, r.Columns, r.Rows, r.BombPercent)},
+		{text.PersonalBest, best},
+	}
+}
+
+// (Re)creates the widgets with the game's results, the theme and the language
+func (s *ResultsScene) rebuildWidgets(results scenes.GameResults) error {
+	font := s.sceneManager.GetTheme().Font
+	lineHeight := int32(font.Height())
+	text := &lang.Text.Results
+	var widgets []rendering.Widget
+	newTextbox := func(text string) (*rendering.Textbox, error) {
+		tbox, err := rendering.NewTextbox(sdl.Rect{}, true, true, text, s.renderer.SDLrenderer, font, theme.Palette.Text)
+		if err == nil {
+			widgets = append(widgets, tbox)
+		}
+		return tbox, err
+	}
+
+	titleText := text.Lost
+	if results.Won {
+		titleText = text.Won
+	}
+	title, err := newTextbox(titleText)
+	if err != nil {
+		return err
+	}
+	layout := scenes.NewVBox().WithSpacing(lineHeight / 4).WithPadding(scenes.UniformInsets(lineHeight))
+	layout.Add(scenes.NewWidgetLayout(title).WithMinSize(0, 2*lineHeight))
+	lines := scenes.NewGrid(2).WithSpacing(lineHeight / 4)
+	for _, line := range resultLines(results) {
+		for i, align := range [2]scenes.Align{scenes.AlignEnd, scenes.AlignStart} {
+			tbox, err := newTextbox(line[i])
+			if err != nil {
+				return err
+			}
+			lines.Add(scenes.NewWidgetLayout(tbox).WithAlign(align, scenes.AlignCenter).WithMinSize(0, lineHeight))
+		}
+	}
+	layout.Add(lines.WithAlign(scenes.AlignCenter, scenes.AlignCenter))
+
+	buttons := scenes.NewGrid(3).WithSpacing(lineHeight / 2)
+	for _, data := range buttonsData {
+		btn := rendering.NewButton(
+			sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
+			true,
+			true,
+			true,
+			*data.text,
+			data.action,
+			theme.Palette.Text,
+			&theme.Palette.ButtonBackground,
+			&theme.Palette.ButtonHoverMenu,
+		)
+		if err := btn.UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+			return err
+		}
+		widgets = append(widgets, btn)
+		buttons.Add(scenes.NewWidgetLayout(btn).
+			WithPadding(scenes.UniformInsets(lineHeight/2)).
+			WithAlign(scenes.AlignStretch, scenes.AlignStretch))
+	}
+	layout.Add(buttons.WithMargin(scenes.Insets{Top: lineHeight / 2}))
+	s.destroyWidgets()
+	s.widgets = widgets
+	s.layout = layout
+	s.focus.SetWidgets(widgets)
+	return nil
+}
+
+func (s *ResultsScene) destroyWidgets() {
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		} else if tbox, ok := widget.(*rendering.Textbox); ok {
+			tbox.Destroy()
+		}
+	}
+	s.widgets = nil
+}
+
+func (s *ResultsScene) processButtonClick(b *rendering.Button) {
+	if b.ActionId == actionNone {
+		return
+	}
+	s.sceneManager.PlaySound(audio.SoundClick)
+	game := s.game()
+	switch b.ActionId {
+	case actionReplay:
+		s.restart(game, scenes.GameSession.Restart)
+	case actionNewBoard:
+		s.restart(game, scenes.GameSession.NewGame)
+	case actionSaveReplay:
+		s.saveFile(game, scenes.GameSession.SaveReplay, lang.Text.Results.ReplaySaved, lang.Text.Results.ReplayFailed)
+	case actionExportBoard:
+		s.saveFile(game, scenes.GameSession.ExportBoard, lang.Text.Results.Exported, lang.Text.Results.ExportFailed)
+	case actionMainMenu:
+		s.sceneManager.PopToDefault()
+	}
+}
+
+// starts the game over (the same board or a new one) and closes the results
+func (s *ResultsScene) restart(game scenes.GameSession, start func(scenes.GameSession) error) {
+	if game != nil {
+		if err := start(game); err != nil {
+			log.Printf("results: %s\n", err)
+		}
+	}
+	s.Exit()
+}
+
+// Writes the board or the replay in a file and tells where
+func (s *ResultsScene) saveFile(game scenes.GameSession, save func(scenes.GameSession) (string, error), savedTitle, failedTitle string) {
+	if game == nil {
+		return
+	}
+	var dialog *scenes.Dialog
+	if path, err := save(game); err != nil {
+		log.Printf("results: %s\n", err)
+		dialog = scenes.NewAlertDialog(failedTitle, err.Error(), nil)
+	} else {
+		dialog = scenes.NewAlertDialog(savedTitle, path, nil)
+	}
+	if err := s.sceneManager.ShowDialog(dialog); err != nil {
+		log.Printf("results: %s\n", err)
+	}
+}
+
+func (s *ResultsScene) ProcessEvent(e sdl.Event) scenes.EventState {
+	if t, ok := e.(*sdl.MouseButtonEvent); ok && t.State == sdl.PRESSED {
+		for _, w := range s.widgets {
+			if btn, ok := w.(*rendering.Button); ok && btn.OnButton(sdl.Point{X: t.X, Y: t.Y}) {
+				s.processButtonClick(btn)
+				return scenes.EventProcessed
+			}
+		}
+	}
+	for _, action := range s.input.Process(e) {
+		if action.Pressed && s.processAction(action.Action) {
+			return scenes.EventProcessed
+		}
+	}
+	// the game under the overlay doesn't get the events
+	return scenes.EventProcessed
+}
+
+func (s *ResultsScene) processAction(action input.Action) bool {
+	if action == input.Menu {
+		s.Exit()
+		return true
+	}
+	used, clicked := s.focus.ProcessAction(action)
+	if btn, ok := clicked.(*rendering.Button); ok {
+		s.processButtonClick(btn)
+	}
+	return used
+}
+
+func (s *ResultsScene) ProcessResize(w, h int32) {
+	s.layout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h})
+}
+
+func (s *ResultsScene) Update(deltaMS uint64) {
+	for _, action := range s.input.Update(deltaMS) {
+		s.processAction(action.Action)
+	}
+	mousePos := sdl.Point{}
+	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.SetHovered(mousePos.InRect(&btn.Rect))
+		}
+	}
+}
+
+func (s *ResultsScene) Draw(renderer rendering.CustomRenderer) {
+	scenes.DrawOverlayPanel(&renderer, s.layout.Rect)
+	s.layout.Draw(&renderer)
+}
+
+// Shows the finished board
+func (s *ResultsScene) Exit() {
+	s.sceneManager.Pop()
+}
+
+// The widgets are created with the results of the game, the first button (Replay) is focused
+func (s *ResultsScene) Enter() error {
+	s.input.Reset()
+	var results scenes.GameResults
+	if game := s.game(); game != nil {
+		results = game.Results()
+	}
+	if err := s.rebuildWidgets(results); err != nil {
+		return err
+	}
+	s.focus.Focus(len(s.widgets) - len(buttonsData))
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+	return nil
+}
+
+func (s *ResultsScene) Pause() {
+}
+
+func (s *ResultsScene) Resume() error {
+	return s.Enter()
+}
+
+func (s *ResultsScene) Unload() {
+	s.destroyWidgets()
+}
+
+func (s *ResultsScene) IsLoaded() bool {
+	return true
+}
+
+func (s *ResultsScene) NeedsRedraw() bool {
+	return true
+}
@@ -1,7 +1,9 @@
 package menuSettings
 
 import (
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/theme"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -10,31 +12,52 @@ const (
 	actionNone rendering.ButtonActionId = iota
 	actionToggleFullscreen
 	actionToggleBorders
+	actionSettingTheme
+	actionSettingLanguage
 	actionExit
-	actionSettingIncreaseColumn
-	actionSettingDecreaseColumn
-	actionSettingIncreaseRow
-	actionSettingDecreaseRow
+	actionSettingColumns
+	actionSettingRows
+	actionSettingBombPercent
+	actionSettingLives
+	actionSettingPlayerName
+	actionSettingTogglePauseOnFocusLoss
 
-	actionSettingIncreaseBombPercent
-	actionSettingDecreaseBombPercent
+	actionSettingMasterVolume
+	actionSettingSfxVolume
+	actionSettingMusicVolume
+	actionSettingToggleMute
 
-	actionSettingIncreaseLives
-	actionSettingDecreaseLives
-	actionSettingToggleInfiniteLives
+	actionSettingColorMode
+	actionSettingToggleHighContrast
+	actionSettingToggleNumberGlyphs
+	actionOpenControls
 )
 
+// change of a volume for each arrow press on its slider, in percents
+const volumeStep = 5
+
+// largest number of columns or rows of the grid
+const maxGridSize = 200
+
+// largest number of lives (0 is no limit)
+const maxLives = 99
+
 type widgetType int
 
 const (
 	buttonWidget = iota
 	textboxWidget
+	checkboxWidget
+	sliderWidget
+	stepperWidget
+	dropdownWidget
+	textFieldWidget
 )
 
 type widgetLoadingData struct {
 	wType           widgetType
-	text            string
-	action          rendering.ButtonActionId
+	text            *string                  // in lang.Text for the translated texts, nil for the controls (see controlText)
+	action          rendering.ButtonActionId // the setting of a control
 	textColor       *sdl.Color
 	backgroundColor *sdl.Color
 	hoverColor      *sdl.Color
@@ -67,25 +90,36 @@ type widgetID int
 // )
 
 var widgetsData = [...]widgetLoadingData{
-	{textboxWidget, "Window settings", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "Toggle fullscreen", actionToggleFullscreen, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "Toggle borders", actionToggleBorders, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{textboxWidget, "", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{textboxWidget, "---", actionNone, &rendering.ColorDarkGrey, &rendering.ColorBlack, &rendering.ColorWhite},
-	{textboxWidget, "Grid settings (changes for the next game)", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{textboxWidget, "Grid columns : {X}", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "+", actionSettingIncreaseColumn, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "-", actionSettingDecreaseColumn, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{textboxWidget, "Grid rows : {X}", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "+", actionSettingIncreaseRow, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "-", actionSettingDecreaseRow, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{textboxWidget, "% of Bomb : {X}%", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "+", actionSettingIncreaseBombPercent, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "-", actionSettingDecreaseBombPercent, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{textboxWidget, "lives : {X}", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "+", actionSettingIncreaseLives, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "-", actionSettingDecreaseLives, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-	{buttonWidget, "∞", actionNone, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
-
-	{buttonWidget, "Go back", actionExit, &rendering.ColorWhite, &rendering.ColorBlack, &rendering.ColorWhite},
+	{textboxWidget, &lang.Text.Settings.WindowSettings, actionNone, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{checkboxWidget, nil, actionToggleFullscreen, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{checkboxWidget, nil, actionToggleBorders, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{dropdownWidget, nil, actionSettingTheme, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{dropdownWidget, nil, actionSettingLanguage, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{textboxWidget, &lang.Text.Settings.GridSettings, actionNone, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{stepperWidget, nil, actionSettingColumns, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{stepperWidget, nil, actionSettingRows, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{sliderWidget, nil, actionSettingBombPercent, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{stepperWidget, nil, actionSettingLives, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{textFieldWidget, nil, actionSettingPlayerName, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{checkboxWidget, nil, actionSettingTogglePauseOnFocusLoss, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{textboxWidget, &lang.Text.Settings.AudioSettings, actionNone, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{sliderWidget, nil, actionSettingMasterVolume, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{sliderWidget, nil, actionSettingSfxVolume, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{sliderWidget, nil, actionSettingMusicVolume, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{checkboxWidget, nil, actionSettingToggleMute, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{textboxWidget, &lang.Text.Settings.Accessibility, actionNone, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{dropdownWidget, nil, actionSettingColorMode, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{checkboxWidget, nil, actionSettingToggleHighContrast, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{checkboxWidget, nil, actionSettingToggleNumberGlyphs, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{buttonWidget, &lang.Text.Settings.Controls, actionOpenControls, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+	{buttonWidget, &lang.Text.Settings.GoBack, actionExit, &theme.Palette.Text, &theme.Palette.ButtonBackground, &theme.Palette.ButtonHover},
+}
+
+// sections of the settings, each a title and its widgets (by index in widgetsData), the "Go back" button is apart
+var sectionsData = [...][]int{
+	{0, 1, 2, 3, 4},
+	{5, 6, 7, 8, 9, 10, 11},
+	{12, 13, 14, 15, 16},
+	{17, 18, 19, 20},
+	{21},
 }
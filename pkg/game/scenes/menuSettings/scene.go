@@ -1,51 +1,75 @@
 package menuSettings
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
 	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/game/theme"
+	"strings"
 
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/veandco/go-sdl2/ttf"
 )
 
 type SettingsScene struct {
 	widgets      []rendering.Widget
 	renderer     *rendering.CustomRenderer
 	sceneManager *scenes.SceneManager
-	font         *ttf.Font
+	font         *rendering.Font
+	theme        *theme.Theme // theme the widgets were created with
+	language     *lang.Language
+	input        *input.Context
+	focus        *scenes.FocusManager
+	layout       *scenes.Layout // grid of the sections
+	backLayout   *scenes.Layout
+	themeIds     []string // options of the theme dropdown
+	locales      []string // options of the language dropdown
 }
 
-func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer, font *ttf.Font) (*SettingsScene, error) {
-	var err error
-	btnCount := 0
-	textboxCount := 0
-	for _, widget := range widgetsData {
-		if widget.wType == buttonWidget {
-			btnCount += 1
-		} else {
-			textboxCount += 1
-		}
+func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer) (*SettingsScene, error) {
+	s := &SettingsScene{
+		renderer:     renderer,
+		sceneManager: sceneManager,
+		input:        input.NewContext(input.MenuBindings()),
+		focus:        scenes.NewFocusManager(),
 	}
+	if err := s.rebuildWidgets(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// (Re)creates the widgets with the scene manager's theme and language
+func (s *SettingsScene) rebuildWidgets() error {
+	var err error
+	currentTheme := s.sceneManager.GetTheme()
+	font := currentTheme.Font
 	widgets := make([]rendering.Widget, len(widgetsData))
 	for i, widget := range widgetsData {
 		selectable := widget.action != actionNone
-		if widget.wType == buttonWidget {
+		if widget.wType != buttonWidget && widget.wType != textboxWidget {
+			if widgets[i], err = s.newControl(widget, font); err != nil {
+				return err
+			}
+		} else if widget.wType == buttonWidget {
 			btn := rendering.NewButton(
 				sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
 				true,
 				true,
 				selectable,
-				widget.text,
+				*widget.text,
 				widget.action,
 				*widget.textColor,
 				widget.backgroundColor,
 				widget.hoverColor,
 			)
-			if btn.UpdateTexture(renderer.SDLrenderer, font) != nil {
-				return nil, err
+			if err := btn.UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+				return err
 			}
 			widgets[i] = btn
 		} else {
@@ -53,93 +77,300 @@ func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRen
 				sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
 				true,
 				true,
-				widget.text,
-				renderer.SDLrenderer,
+				*widget.text,
+				s.renderer.SDLrenderer,
 				font,
 				*widget.textColor,
 			)
 
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
-	s := &SettingsScene{widgets: widgets, font: font, renderer: renderer, sceneManager: sceneManager}
-	s.updateText()
-	return s, nil
+	s.destroyWidgets()
+	s.widgets = widgets
+	s.focus.SetWidgets(widgets)
+	s.buildLayout(font)
+	s.font = font
+	s.theme = currentTheme
+	s.language = s.sceneManager.GetLanguage()
+	return nil
 }
 
-func (s *SettingsScene) processButtonClick(b *rendering.Button) {
-	switch b.ActionId {
-	case actionNone:
-		return
+// Creates the control of the widget's setting, with the config's value and the callback changing it
+func (s *SettingsScene) newControl(widget widgetLoadingData, font *rendering.Font) (rendering.Control, error) {
+	cfg := s.sceneManager.GetConfig()
+	text := &lang.Text.Settings
+	colors := []sdl.Color{*widget.textColor, *widget.backgroundColor, *widget.hoverColor}
+	newCheckbox := func(checked bool, textOf func(bool) string, onChange func(bool)) rendering.Control {
+		checkbox := rendering.NewCheckbox(s.renderer, font, checked, textOf, colors[0], colors[1], colors[2])
+		checkbox.OnChange = func(checked bool) {
+			s.sceneManager.PlaySound(audio.SoundClick)
+			onChange(checked)
+		}
+		return checkbox
+	}
+	newSlider := func(min, max, step, value int, textOf func(int) string, onChange func(int)) rendering.Control {
+		slider := rendering.NewSlider(s.renderer, font, min, max, step, value, textOf, colors[0], colors[1], colors[2])
+		slider.OnChange = onChange
+		return slider
+	}
+	newStepper := func(min, max, value int, textOf func(int) string, onChange func(int)) rendering.Control {
+		stepper := rendering.NewStepper(s.renderer, font, min, max, 1, value, textOf, colors[0], colors[1], colors[2])
+		stepper.OnChange = func(value int) {
+			s.sceneManager.PlaySound(audio.SoundClick)
+			onChange(value)
+		}
+		return stepper
+	}
+	newDropdown := func(options []string, index int, format string, onChange func(int)) rendering.Control {
+		textOf := func(option string) string { return fmt.Sprintf(format, option) }
+		dropdown := rendering.NewDropdown(s.renderer, font, options, index, textOf, colors[0], colors[1], colors[2])
+		dropdown.OnChange = func(index int) {
+			s.sceneManager.PlaySound(audio.SoundClick)
+			onChange(index)
+		}
+		return dropdown
+	}
+	label := func(text string) func(bool) string {
+		return func(bool) string { return text }
+	}
+	var control rendering.Control
+	switch widget.action {
 	case actionToggleFullscreen:
-		s.renderer.ToggleFullscreen()
+		control = newCheckbox(s.renderer.IsFullscreen(), label(text.ToggleFullscreen), func(checked bool) {
+			if checked != s.renderer.IsFullscreen() {
+				s.renderer.ToggleFullscreen()
+			}
+		})
 	case actionToggleBorders:
-		s.renderer.ToggleBorders()
-	case actionSettingIncreaseColumn:
-		cfg := s.sceneManager.GetConfig()
-		cfg.Game.GridColumns += 1
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
-
-	case actionSettingDecreaseColumn:
-		cfg := s.sceneManager.GetConfig()
-		cfg.Game.GridColumns -= 1
-		if cfg.Game.GridColumns < 1 {
-			cfg.Game.GridColumns = 1
+		control = newCheckbox(s.renderer.IsBordered(), label(text.ToggleBorders), func(checked bool) {
+			if checked != s.renderer.IsBordered() {
+				s.renderer.ToggleBorders()
+			}
+		})
+	case actionSettingTheme:
+		s.themeIds = theme.List(cfg.Window.ThemesPath)
+		names := make([]string, len(s.themeIds))
+		for i, id := range s.themeIds {
+			names[i] = theme.Name(cfg.Window.ThemesPath, id)
 		}
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
-	case actionSettingIncreaseRow:
-		cfg := s.sceneManager.GetConfig()
-		cfg.Game.GridRows += 1
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
-
-	case actionSettingDecreaseRow:
-		cfg := s.sceneManager.GetConfig()
-		cfg.Game.GridRows -= 1
-		if cfg.Game.GridRows < 1 {
-			cfg.Game.GridRows = 1
+		control = newDropdown(names, indexOf(s.themeIds, s.sceneManager.GetTheme().Id), text.Theme, func(i int) {
+			s.setTheme(s.themeIds[i])
+		})
+	case actionSettingLanguage:
+		s.locales = lang.List(cfg.Window.LangPath)
+		names := make([]string, len(s.locales))
+		for i, locale := range s.locales {
+			names[i] = lang.Name(cfg.Window.LangPath, locale)
 		}
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
-
-	case actionSettingIncreaseBombPercent:
-		cfg := s.sceneManager.GetConfig()
-		cfg.Game.BombPercent += 1
-		if cfg.Game.BombPercent > 99 {
-			cfg.Game.BombPercent = 99
+		control = newDropdown(names, indexOf(s.locales, s.sceneManager.GetLanguage().Locale), text.Language, func(i int) {
+			s.setLanguage(s.locales[i])
+		})
+	case actionSettingColumns:
+		control = newStepper(1, maxGridSize, int(cfg.Game.GridColumns), func(value int) string {
+			return fmt.Sprintf(text.Columns, value)
+		}, func(value int) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Game.GridColumns = uint32(value) })
+		})
+	case actionSettingRows:
+		control = newStepper(1, maxGridSize, int(cfg.Game.GridRows), func(value int) string {
+			return fmt.Sprintf(text.Rows, value)
+		}, func(value int) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Game.GridRows = uint32(value) })
+		})
+	case actionSettingBombPercent:
+		control = newSlider(1, 99, 1, cfg.Game.BombPercent, func(value int) string {
+			game := s.sceneManager.GetConfig().Game
+			bombCount := int(game.GridColumns*game.GridRows) * value / 100
+			return fmt.Sprintf(lang.Plural(text.Bombs, bombCount), bombCount, value)
+		}, func(value int) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Game.BombPercent = value })
+		})
+	case actionSettingLives:
+		// 0 is no limit, saved as -1
+		control = newStepper(0, maxLives, livesValue(cfg.Game.Lives), func(value int) string {
+			if value == 0 {
+				return text.LivesNoLimit
+			}
+			return fmt.Sprintf(text.Lives, value)
+		}, func(value int) {
+			s.changeConfig(func(cfg *config.Config) {
+				cfg.Game.Lives = value
+				if value == 0 {
+					cfg.Game.Lives = -1
+				}
+			})
+		})
+	case actionSettingPlayerName:
+		field := rendering.NewTextField(s.renderer, font, text.PlayerName, cfg.Game.PlayerName, config.MaxPlayerNameLength, colors[0], colors[1], colors[2])
+		field.Validate = func(name string) error {
+			if strings.TrimSpace(name) == "" {
+				return errors.New(text.NameEmpty)
+			}
+			return nil
 		}
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
-
-	case actionSettingDecreaseBombPercent:
-		cfg := s.sceneManager.GetConfig()
-		cfg.Game.BombPercent -= 1
-		if cfg.Game.BombPercent < 1 {
-			cfg.Game.BombPercent = 1
+		// the config keeps the last valid name
+		field.OnChange = func(name string) {
+			if field.Error() == nil {
+				s.changeConfig(func(cfg *config.Config) { cfg.Game.PlayerName = strings.TrimSpace(name) })
+			}
 		}
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
-	case actionSettingIncreaseLives:
-		cfg := s.sceneManager.GetConfig()
-		if cfg.Game.Lives < 0 {
-			cfg.Game.Lives = 1
-		} else {
-			cfg.Game.Lives += 1
+		field.SetValue(cfg.Game.PlayerName)
+		control = field
+	case actionSettingTogglePauseOnFocusLoss:
+		control = newCheckbox(cfg.Game.PauseOnFocusLoss, func(checked bool) string {
+			return fmt.Sprintf(text.PauseOnFocusLoss, onOff(checked))
+		}, func(checked bool) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Game.PauseOnFocusLoss = checked })
+		})
+	case actionSettingMasterVolume, actionSettingSfxVolume, actionSettingMusicVolume:
+		volume, format := s.volume(widget.action, &cfg)
+		control = newSlider(0, 100, volumeStep, *volume, func(value int) string {
+			return fmt.Sprintf(format, value)
+		}, func(value int) {
+			s.changeConfig(func(cfg *config.Config) {
+				volume, _ := s.volume(widget.action, cfg)
+				*volume = value
+			})
+		})
+	case actionSettingToggleMute:
+		control = newCheckbox(cfg.Audio.Mute, func(checked bool) string {
+			if !checked && !s.sceneManager.IsAudioEnabled() {
+				return text.NoAudioDevice
+			}
+			return fmt.Sprintf(text.Mute, onOff(checked))
+		}, func(checked bool) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Audio.Mute = checked })
+		})
+	case actionSettingColorMode:
+		names := make([]string, len(config.ColorModes))
+		for i, mode := range config.ColorModes {
+			names[i] = colorModeName(mode)
 		}
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
-	case actionSettingDecreaseLives:
-		cfg := s.sceneManager.GetConfig()
-		cfg.Game.Lives -= 1
-		if cfg.Game.Lives < 1 {
-			cfg.Game.Lives = -1
+		control = newDropdown(names, indexOf(config.ColorModes, cfg.Accessibility.ColorMode), text.ColorMode, func(i int) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Accessibility.ColorMode = config.ColorModes[i] })
+		})
+	case actionSettingToggleHighContrast:
+		control = newCheckbox(cfg.Accessibility.HighContrast, func(checked bool) string {
+			return fmt.Sprintf(text.HighContrast, onOff(checked))
+		}, func(checked bool) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Accessibility.HighContrast = checked })
+		})
+	case actionSettingToggleNumberGlyphs:
+		// the high contrast always draws the numbers with the font
+		control = newCheckbox(cfg.Accessibility.NumberGlyphs || cfg.Accessibility.HighContrast, func(checked bool) string {
+			return fmt.Sprintf(text.NumberGlyphs, onOff(checked))
+		}, func(checked bool) {
+			s.changeConfig(func(cfg *config.Config) { cfg.Accessibility.NumberGlyphs = checked })
+		})
+	}
+	if control == nil {
+		return nil, fmt.Errorf("newControl: no control for the setting %d", widget.action)
+	}
+	return control, nil
+}
+
+// Volume of the config changed by the slider, and the format of its text
+func (s *SettingsScene) volume(action rendering.ButtonActionId, cfg *config.Config) (*int, string) {
+	switch action {
+	case actionSettingSfxVolume:
+		return &cfg.Audio.SfxVolume, lang.Text.Settings.SfxVolume
+	case actionSettingMusicVolume:
+		return &cfg.Audio.MusicVolume, lang.Text.Settings.MusicVolume
+	}
+	return &cfg.Audio.MasterVolume, lang.Text.Settings.MasterVolume
+}
+
+// Changes the shared config, then shows the new values (a change can change other texts, like the bombs count)
+func (s *SettingsScene) changeConfig(change func(cfg *config.Config)) {
+	cfg := s.sceneManager.GetConfig()
+	change(&cfg)
+	s.sceneManager.SetConfig(cfg)
+	s.updateControls()
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+}
+
+// value of the lives stepper, 0 for no limit
+func livesValue(lives int) int {
+	if lives < 1 {
+		return 0
+	}
+	return lives
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
 		}
-		s.sceneManager.SetConfig(cfg)
-		s.updateText()
+	}
+	return 0
+}
+
+func (s *SettingsScene) destroyWidgets() {
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		} else if tbox, ok := widget.(*rendering.Textbox); ok {
+			tbox.Destroy()
+		} else if control, ok := widget.(rendering.Control); ok {
+			control.Destroy()
+		}
+	}
+	s.widgets = nil
+}
+
+// Switches to the theme of the themes directory, the dropdown goes back to the current one if it can't be loaded
+func (s *SettingsScene) setTheme(id string) {
+	cfg := s.sceneManager.GetConfig()
+	newTheme, err := theme.Load(s.renderer, cfg.Window.ThemesPath, id, cfg.Window.ResourcesPath)
+	if err != nil {
+		log.Printf("setTheme: %s\n", err)
+		s.updateControls()
+		return
+	}
+	s.sceneManager.SetTheme(newTheme)
+	cfg.Window.Theme = id
+	s.sceneManager.SetConfig(cfg)
+	if err := s.rebuildWidgets(); err != nil {
+		log.Printf("setTheme: %s\n", err)
+	}
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+}
+
+// Switches to the language of the languages directory, the widgets are rebuilt with its texts
+func (s *SettingsScene) setLanguage(locale string) {
+	cfg := s.sceneManager.GetConfig()
+	language, err := lang.Load(cfg.Window.LangPath, locale)
+	if err != nil {
+		log.Printf("setLanguage: %s\n", err)
+		s.updateControls()
+		return
+	}
+	s.sceneManager.SetLanguage(language)
+	cfg.Window.Language = locale
+	s.sceneManager.SetConfig(cfg)
+	if err := s.rebuildWidgets(); err != nil {
+		log.Printf("setLanguage: %s\n", err)
+	}
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+}
+
+func (s *SettingsScene) processButtonClick(b *rendering.Button) {
+	if b.ActionId != actionNone {
+		s.sceneManager.PlaySound(audio.SoundClick)
+	}
+	switch b.ActionId {
+	case actionNone:
+		return
+	case actionOpenControls:
+		// the controls page saves the whole config when leaving it
+		s.sceneManager.Push("controls")
 
 	case actionExit:
 		s.Exit()
@@ -147,6 +378,9 @@ func (s *SettingsScene) processButtonClick(b *rendering.Button) {
 }
 
 func (s *SettingsScene) ProcessEvent(e sdl.Event) scenes.EventState {
+	if s.focus.ProcessMouse(e) || s.focus.ProcessKey(e) {
+		return scenes.EventProcessed
+	}
 	switch t := e.(type) {
 	case *sdl.MouseButtonEvent:
 		if t.State == sdl.PRESSED {
@@ -163,51 +397,71 @@ func (s *SettingsScene) ProcessEvent(e sdl.Event) scenes.EventState {
 				}
 			}
 		}
-
-	case *sdl.KeyboardEvent:
-		keyCode := t.Keysym.Sym
-		pressed := (t.State == sdl.PRESSED)
-		if pressed {
-			if keyCode == sdl.K_ESCAPE {
-				s.Exit()
-				return scenes.EventProcessed
-			}
+	}
+	for _, action := range s.input.Process(e) {
+		if action.Pressed && s.processAction(action.Action) {
+			return scenes.EventProcessed
 		}
 	}
 	return scenes.EventToProcess
 }
 
-func (s *SettingsScene) ProcessResize(w, h int32) {
-	var maxHeight int32 = 0
-
-	for _, widget := range s.widgets {
-		if btn, ok := widget.(*rendering.Button); ok {
-			if btn.TextureRect.H > maxHeight {
-				maxHeight = btn.TextureRect.H
-			}
+func (s *SettingsScene) processAction(action input.Action) bool {
+	if used, clicked := s.focus.ProcessAction(action); used {
+		if btn, ok := clicked.(*rendering.Button); ok {
+			s.processButtonClick(btn)
 		}
+		return true
 	}
+	if action == input.Menu {
+		s.Exit()
+		return true
+	}
+	return false
+}
 
-	padding := maxHeight
-	maxHeight += padding
+// Layout of the sections (one per column of a grid) and of the "Go back" button, in the bottom-left corner
+func (s *SettingsScene) buildLayout(font *rendering.Font) {
+	lineHeight := int32(font.Height())
 	var margin int32 = 5
-	y := (h - (maxHeight+margin)*int32(len(s.widgets)-1)) / 2
-	for _, widget := range s.widgets[:len(s.widgets)-1] {
-		widget.SetCenter(w/2, y)
-		if btn, ok := widget.(*rendering.Button); ok {
-			btn.SetBackgroundSize(btn.TextureRect.W+padding, maxHeight)
-		} else if tbox, ok := widget.(*rendering.Textbox); ok {
-			tbox.SetBackgroundSize(tbox.Rect.W+padding, maxHeight)
+	padding := scenes.UniformInsets(lineHeight / 2)
+	s.layout = scenes.NewGrid(len(sectionsData)).WithSpacing(lineHeight)
+	for _, section := range sectionsData {
+		box := scenes.NewVBox().WithSpacing(margin).WithAlign(scenes.AlignCenter, scenes.AlignStart)
+		for _, i := range section {
+			node := scenes.NewWidgetLayout(s.widgets[i]).WithPadding(padding)
+			if _, ok := s.widgets[i].(rendering.Control); ok {
+				// the controls of a section are as large as the largest one
+				node.WithAlign(scenes.AlignStretch, scenes.AlignCenter)
+			}
+			box.Add(node)
 		}
-		y += maxHeight + margin
+		s.layout.Add(box)
 	}
-	if btn, ok := s.widgets[len(s.widgets)-1].(*rendering.Button); ok {
-		btn.SetBackgroundSize(btn.TextureRect.W+padding, btn.TextureRect.H+padding)
-		btn.SetTopLeft(margin, h-margin-maxHeight)
+	back := s.widgets[len(s.widgets)-1]
+	s.backLayout = scenes.NewWidgetLayout(back).
+		WithPadding(padding).
+		WithMargin(scenes.UniformInsets(margin)).
+		WithAnchor(scenes.AnchorBottomLeft)
+}
+
+func (s *SettingsScene) ProcessResize(w, h int32) {
+	_, backHeight := s.backLayout.Size()
+	// as many sections side by side as fit in the window's width, the "Go back" button keeps its row
+	for s.layout.Columns = len(sectionsData); s.layout.Columns > 1; s.layout.Columns-- {
+		if width, _ := s.layout.Size(); width <= w {
+			break
+		}
 	}
+	s.layout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h - backHeight})
+	s.backLayout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h})
 }
 
 func (s *SettingsScene) Update(deltaMS uint64) {
+	for _, action := range s.input.Update(deltaMS) {
+		s.processAction(action.Action)
+	}
+	s.focus.Update(deltaMS)
 	mousePos := sdl.Point{}
 	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
 	for _, widget := range s.widgets {
@@ -220,37 +474,46 @@ func (s *SettingsScene) Draw(renderer rendering.CustomRenderer) {
 	for _, widget := range s.widgets {
 		widget.Draw(&renderer)
 	}
+	s.focus.DrawCapturing(&renderer)
 }
 
 func (s *SettingsScene) Exit() {
 	s.SaveSettings()
-	s.sceneManager.SetLastScene(false)
+	s.sceneManager.Pop()
 	fmt.Printf("%+v\n", s.sceneManager.GetConfig())
 }
 
-func (s *SettingsScene) Enter(reload bool) error {
-	var cfg config.Config
-	err := config.LoadConfig(config.ConfigFilePath, &cfg)
-	if err == nil {
-		s.sceneManager.SetConfig(cfg)
-	}
-	if reload {
-		err := s.load()
-		if err != nil {
+func (s *SettingsScene) Enter() error {
+	s.input.Reset()
+	if s.theme != s.sceneManager.GetTheme() || s.language != s.sceneManager.GetLanguage() {
+		if err := s.rebuildWidgets(); err != nil {
 			return err
 		}
 	}
+	cfg := config.DefaultConfig
+	err := config.LoadConfig(config.ConfigFilePath, &cfg)
+	if err == nil && cfg.Check() == nil {
+		s.sceneManager.SetConfig(cfg)
+	}
+	s.updateControls()
 	w, h := s.renderer.SDLwindow.GetSize()
 	s.ProcessResize(w, h)
-	s.updateText()
 	return nil
 }
 
-func (s *SettingsScene) load() error {
-	return nil
+func (s *SettingsScene) Pause() {
+	// a focused text field stops the text input
+	s.focus.Clear()
+}
+
+// The controls page saved the config
+func (s *SettingsScene) Resume() error {
+	return s.Enter()
 }
 
 func (s *SettingsScene) Unload() {
+	// a focused text field stops the text input
+	s.focus.Clear()
 	// for _, b := range s.buttons {
 	// 	b.Destroy()
 	// }
@@ -270,30 +533,69 @@ func (s *SettingsScene) SaveSettings() {
 	}
 }
 
-func (s *SettingsScene) updateText() {
+// Shows the config's values in the controls (without calling their callbacks)
+func (s *SettingsScene) updateControls() {
 	cfg := s.sceneManager.GetConfig()
-	if tbox, ok := s.widgets[6].(*rendering.Textbox); ok {
-		text := fmt.Sprintf("Grid columns: %d", cfg.Game.GridColumns)
-		tbox.SetText(text, s.renderer.SDLrenderer, s.font, rendering.ColorWhite)
-	}
-	if tbox, ok := s.widgets[9].(*rendering.Textbox); ok {
-		text := fmt.Sprintf("Grid rows: %d", cfg.Game.GridRows)
-		tbox.SetText(text, s.renderer.SDLrenderer, s.font, rendering.ColorWhite)
+	for i, widget := range widgetsData {
+		switch control := s.widgets[i].(type) {
+		case *rendering.Checkbox:
+			switch widget.action {
+			case actionToggleFullscreen:
+				control.SetChecked(s.renderer.IsFullscreen())
+			case actionToggleBorders:
+				control.SetChecked(s.renderer.IsBordered())
+			case actionSettingToggleMute:
+				control.SetChecked(cfg.Audio.Mute)
+			case actionSettingToggleHighContrast:
+				control.SetChecked(cfg.Accessibility.HighContrast)
+			case actionSettingToggleNumberGlyphs:
+				control.SetChecked(cfg.Accessibility.NumberGlyphs || cfg.Accessibility.HighContrast)
+			}
+		case *rendering.Slider:
+			if widget.action == actionSettingBombPercent {
+				control.SetValue(cfg.Game.BombPercent)
+			} else {
+				volume, _ := s.volume(widget.action, &cfg)
+				control.SetValue(*volume)
+			}
+		case *rendering.Stepper:
+			switch widget.action {
+			case actionSettingColumns:
+				control.SetValue(int(cfg.Game.GridColumns))
+			case actionSettingRows:
+				control.SetValue(int(cfg.Game.GridRows))
+			case actionSettingLives:
+				control.SetValue(livesValue(cfg.Game.Lives))
+			}
+		case *rendering.Dropdown:
+			switch widget.action {
+			case actionSettingTheme:
+				control.SetIndex(indexOf(s.themeIds, s.sceneManager.GetTheme().Id))
+			case actionSettingLanguage:
+				control.SetIndex(indexOf(s.locales, s.sceneManager.GetLanguage().Locale))
+			case actionSettingColorMode:
+				control.SetIndex(indexOf(config.ColorModes, cfg.Accessibility.ColorMode))
+			}
+		}
 	}
-	if tbox, ok := s.widgets[12].(*rendering.Textbox); ok {
-		text := fmt.Sprintf("bombs: %d (%d%% of the tiles)", cfg.Game.GridColumns*cfg.Game.GridRows*uint32(cfg.Game.BombPercent)/100, cfg.Game.BombPercent)
-		tbox.SetText(text, s.renderer.SDLrenderer, s.font, rendering.ColorWhite)
+}
+
+func onOff(value bool) string {
+	if value {
+		return lang.Text.Settings.On
 	}
-	if tbox, ok := s.widgets[15].(*rendering.Textbox); ok {
-		var text string
-		if cfg.Game.Lives < 1 {
-			text = "lives : no limit"
+	return lang.Text.Settings.Off
+}
 
-		} else {
-			text = fmt.Sprintf("lives : %d", cfg.Game.Lives)
-		}
-		tbox.SetText(text, s.renderer.SDLrenderer, s.font, rendering.ColorWhite)
+// Translated name of the config.ColorModes
+func colorModeName(mode string) string {
+	switch mode {
+	case "red-green":
+		return lang.Text.Settings.ColorRedGreen
+	case "blue-yellow":
+		return lang.Text.Settings.ColorBlueYellow
 	}
+	return lang.Text.Settings.ColorNormal
 }
 
 func (s *SettingsScene) IsLoaded() bool {
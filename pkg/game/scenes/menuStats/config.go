@@ -0,0 +1,46 @@
+package menuStats
+
+import (
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/history"
+)
+
+const (
+	actionNone rendering.ButtonActionId = iota
+	actionExportCSV
+	actionExportJSON
+	actionExit
+)
+
+const (
+	tableRows       = 5  // rows of the grid settings table shown at once
+	histogramBars   = 12 // of the times histogram
+	histogramDays   = 30 // of the games by day histogram
+	histogramWidth  = 12 // in lines
+	histogramHeight = 4  // in lines
+	filterWidth     = 10 // in lines
+)
+
+// positions of the filters in the widgets, after the title
+const (
+	playerFilterIndex = 1
+	gridFilterIndex   = 2
+)
+
+type buttonLoadingData struct {
+	text   *string // in lang.Text
+	action rendering.ButtonActionId
+}
+
+var buttonsData = [...]buttonLoadingData{
+	{&lang.Text.Stats.ExportCSV, actionExportCSV},
+	{&lang.Text.Stats.ExportJSON, actionExportJSON},
+	{&lang.Text.Stats.Back, actionExit},
+}
+
+// format written by the export buttons
+var exportFormats = map[rendering.ButtonActionId]string{
+	actionExportCSV:  history.FormatCSV,
+	actionExportJSON: history.FormatJSON,
+}
@@ -0,0 +1,415 @@
+package menuStats
+
+import (
+	"fmt"
+	"log"
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
+	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/scenes"
+	"minesweeper/pkg/game/theme"
+	"minesweeper/pkg/history"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+/*
+Statistics of the games recorded in the history file, of a player or of all of them: the totals
+and streaks, a table of the games by grid settings (sorted by a click on a column's title), a
+histogram of the won games' times (of a grid settings or of all of them) and the games played by
+day. The whole history can be exported as CSV or JSON in config.ExportsPath
+*/
+type StatsScene struct {
+	widgets      []rendering.Widget
+	renderer     *rendering.CustomRenderer
+	sceneManager *scenes.SceneManager
+	focus        *scenes.FocusManager
+	layout       *scenes.Layout
+	input        *input.Context
+	records      []history.Record
+	player       string // shown, "" for all the players
+	difficulty   string // of the times histogram, "" for all the grid settings
+}
+
+func Initialize(sceneManager *scenes.SceneManager, renderer *rendering.CustomRenderer) (*StatsScene, error) {
+	s := &StatsScene{
+		renderer:     renderer,
+		sceneManager: sceneManager,
+		focus:        scenes.NewFocusManager(),
+		input:        input.NewContext(input.MenuBindings()),
+		// the current player's games first, all of them if they have none
+		player: sceneManager.GetConfig().Game.PlayerName,
+	}
+	return s, nil
+}
+
+// label and value of each line of the totals
+func summaryLines(stats history.Stats) [][2]string {
+	text := &lang.Text.Stats
+	return [][2]string{
+		{text.GamesPlayed, fmt.Sprint(stats.Played)},
+		{text.WinRate, fmt.Sprintf(text.WinRateValue, stats.WinRate(), stats.Won)},
+		{text.CurrentStreak, fmt.Sprint(stats.CurrentStreak)},
+		{text.LongestStreak, fmt.Sprint(stats.LongestStreak)},
+	}
+}
+
+// one row per grid settings, the times of the grids never won are sorted first
+func tableRowsOf(stats history.Stats) []rendering.TableRow {
+	rows := make([]rendering.TableRow, len(stats.Difficulties))
+	for i, d := range stats.Difficulties {
+		best, average := lang.Text.Stats.None, lang.Text.Stats.None
+		if d.Won > 0 {
+			best, average = scenes.FormatDuration(d.BestMS), scenes.FormatDuration(d.AverageMS)
+		}
+		rows[i] = rendering.TableRow{
+			Cells:  []string{d.Difficulty, fmt.Sprint(d.Played), fmt.Sprint(d.Won), best, average},
+			Values: []float64{float64(i), float64(d.Played), float64(d.Won), float64(d.BestMS), float64(d.AverageMS)},
+		}
+	}
+	return rows
+}
+
+// Dropdown of the filter, the first option ("" in the values) is for all the records
+func (s *StatsScene) newFilter(font *rendering.Font, values []string, value, all, format string, onChange func(string)) *rendering.Dropdown {
+	options := append([]string{all}, values...)
+	index := 0
+	for i, v := range values {
+		if v == value {
+			index = i + 1
+		}
+	}
+	textOf := func(option string) string { return fmt.Sprintf(format, option) }
+	dropdown := rendering.NewDropdown(s.renderer, font, options, index, textOf, theme.Palette.Text, theme.Palette.ButtonBackground, theme.Palette.ButtonHoverMenu)
+	dropdown.OnChange = func(index int) {
+		s.sceneManager.PlaySound(audio.SoundClick)
+		if index == 0 {
+			onChange("")
+		} else {
+			onChange(values[index-1])
+		}
+	}
+	return dropdown
+}
+
+// Shows the records of the filters, the focus stays on the changed one
+func (s *StatsScene) setFilter(filter *string, value string, focused int) {
+	*filter = value
+	if err := s.rebuildWidgets(); err != nil {
+		log.Printf("statistics: %s\n", err)
+		return
+	}
+	s.focus.Focus(focused)
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+}
+
+// (Re)creates the widgets with the records, the theme and the language
+func (s *StatsScene) rebuildWidgets() error {
+	font := s.sceneManager.GetTheme().Font
+	lineHeight := int32(font.Height())
+	text := &lang.Text.Stats
+	// the filtered values may be missing from a history changed since
+	players := history.Players(s.records)
+	if indexOf(players, s.player) < 0 {
+		s.player = ""
+	}
+	records := history.Filter(s.records, s.player, "")
+	difficulties := history.Difficulties(records)
+	if indexOf(difficulties, s.difficulty) < 0 {
+		s.difficulty = ""
+	}
+	stats := history.Summarize(records)
+	var widgets []rendering.Widget
+	newTextbox := func(text string) (*rendering.Textbox, error) {
+		tbox, err := rendering.NewTextbox(sdl.Rect{}, true, true, text, s.renderer.SDLrenderer, font, theme.Palette.Text)
+		if err == nil {
+			widgets = append(widgets, tbox)
+		}
+		return tbox, err
+	}
+
+	title, err := newTextbox(text.Title)
+	if err != nil {
+		return err
+	}
+	layout := scenes.NewVBox().WithSpacing(lineHeight / 2)
+	layout.Add(scenes.NewWidgetLayout(title).WithMinSize(0, 2*lineHeight))
+	filters := scenes.NewHBox().WithSpacing(lineHeight / 2)
+	playerFilter := s.newFilter(font, players, s.player, text.AllPlayers, text.Player, func(player string) {
+		s.setFilter(&s.player, player, playerFilterIndex)
+	})
+	gridFilter := s.newFilter(font, difficulties, s.difficulty, text.AllGrids, text.GridFilter, func(difficulty string) {
+		s.setFilter(&s.difficulty, difficulty, gridFilterIndex)
+	})
+	for _, filter := range []*rendering.Dropdown{playerFilter, gridFilter} {
+		widgets = append(widgets, filter)
+		filters.Add(scenes.NewWidgetLayout(filter).WithMinSize(filterWidth*lineHeight, 0))
+	}
+	layout.Add(filters.WithAlign(scenes.AlignCenter, scenes.AlignCenter))
+	summary := scenes.NewGrid(2).WithSpacing(lineHeight / 4)
+	for _, line := range summaryLines(stats) {
+		for i, align := range [2]scenes.Align{scenes.AlignEnd, scenes.AlignStart} {
+			tbox, err := newTextbox(line[i])
+			if err != nil {
+				return err
+			}
+			summary.Add(scenes.NewWidgetLayout(tbox).WithAlign(align, scenes.AlignCenter).WithMinSize(0, lineHeight))
+		}
+	}
+	layout.Add(summary.WithAlign(scenes.AlignCenter, scenes.AlignCenter))
+
+	columns := []rendering.TableColumn{
+		{Title: text.Grid, Sortable: true},
+		{Title: text.Played, Sortable: true},
+		{Title: text.Won, Sortable: true},
+		{Title: text.Best, Sortable: true},
+		{Title: text.Average, Sortable: true},
+	}
+	table := rendering.NewTable(s.renderer, font, columns, tableRowsOf(stats), tableRows, theme.Palette.Text, theme.Palette.ButtonBackground, theme.Palette.ButtonHoverMenu)
+	widgets = append(widgets, table)
+	layout.Add(scenes.NewWidgetLayout(table))
+
+	// the histograms side by side, each between its caption and its range
+	charts := scenes.NewHBox().WithSpacing(lineHeight)
+	addChart := func(caption string, counts []int, countsRange string) error {
+		chart := scenes.NewVBox().WithSpacing(lineHeight / 4)
+		captionText, err := newTextbox(caption)
+		if err != nil {
+			return err
+		}
+		chart.Add(scenes.NewWidgetLayout(captionText).WithMinSize(0, lineHeight))
+		histogram := rendering.NewHistogram(histogramWidth*lineHeight, histogramHeight*lineHeight, counts, theme.Palette.ButtonHoverMenu, theme.Palette.Text)
+		widgets = append(widgets, histogram)
+		chart.Add(scenes.NewWidgetLayout(histogram))
+		rangeText, err := newTextbox(countsRange)
+		if err != nil {
+			return err
+		}
+		chart.Add(scenes.NewWidgetLayout(rangeText).WithMinSize(0, lineHeight))
+		charts.Add(chart)
+		return nil
+	}
+	counts, bucketMS := history.Histogram(history.Filter(records, "", s.difficulty), histogramBars)
+	timesRange := text.None
+	if bucketMS > 0 {
+		timesRange = fmt.Sprintf(text.TimesRange, scenes.FormatDuration(bucketMS*histogramBars))
+	}
+	if err := addChart(text.Times, counts, timesRange); err != nil {
+		return err
+	}
+	perDay := history.GamesPerDay(records, time.Now(), histogramDays)
+	if err := addChart(text.Days, perDay, fmt.Sprintf(text.DaysRange, histogramDays)); err != nil {
+		return err
+	}
+	layout.Add(charts.WithAlign(scenes.AlignCenter, scenes.AlignCenter))
+
+	buttons := scenes.NewHBox().WithSpacing(lineHeight / 2)
+	for _, data := range buttonsData {
+		btn := rendering.NewButton(
+			sdl.Rect{X: 0, Y: 0, W: 10, H: 10},
+			true,
+			true,
+			true,
+			*data.text,
+			data.action,
+			theme.Palette.Text,
+			&theme.Palette.ButtonBackground,
+			&theme.Palette.ButtonHoverMenu,
+		)
+		if err := btn.UpdateTexture(s.renderer.SDLrenderer, font); err != nil {
+			return err
+		}
+		widgets = append(widgets, btn)
+		buttons.Add(scenes.NewWidgetLayout(btn).WithPadding(scenes.UniformInsets(lineHeight / 2)))
+	}
+	layout.Add(buttons.WithAlign(scenes.AlignCenter, scenes.AlignCenter))
+	s.destroyWidgets()
+	s.widgets = widgets
+	s.layout = layout
+	s.focus.SetWidgets(widgets)
+	return nil
+}
+
+func (s *StatsScene) destroyWidgets() {
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.Destroy()
+		} else if tbox, ok := widget.(*rendering.Textbox); ok {
+			tbox.Destroy()
+		} else if control, ok := widget.(rendering.Control); ok {
+			control.Destroy()
+		}
+	}
+	s.widgets = nil
+}
+
+func (s *StatsScene) processButtonClick(b *rendering.Button) {
+	if b.ActionId != actionNone {
+		s.sceneManager.PlaySound(audio.SoundClick)
+	}
+	switch b.ActionId {
+	case actionExportCSV, actionExportJSON:
+		s.export(exportFormats[b.ActionId])
+	case actionExit:
+		s.Exit()
+	}
+}
+
+// Writes the history in a file of config.ExportsPath and tells where
+func (s *StatsScene) export(format string) {
+	text := &lang.Text.Stats
+	var dialog *scenes.Dialog
+	if path, err := exportHistory(s.records, format); err != nil {
+		log.Println(err)
+		dialog = scenes.NewAlertDialog(text.ExportFailed, err.Error(), nil)
+	} else {
+		dialog = scenes.NewAlertDialog(text.Exported, path, nil)
+	}
+	if err := s.sceneManager.ShowDialog(dialog); err != nil {
+		log.Printf("statistics: %s\n", err)
+	}
+}
+
+func exportHistory(records []history.Record, format string) (string, error) {
+	if err := history.CheckFormat(format); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(config.ExportsPath, 0755); err != nil {
+		return "", fmt.Errorf("export history: %s", err)
+	}
+	path := filepath.Join(config.ExportsPath, "history."+format)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("export history: %s", err)
+	}
+	err = history.Export(file, records, format)
+	// the end of the file may only be written when closing it
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("export history: %s", closeErr)
+	}
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (s *StatsScene) ProcessEvent(e sdl.Event) scenes.EventState {
+	if s.focus.ProcessMouse(e) {
+		return scenes.EventProcessed
+	}
+	if t, ok := e.(*sdl.MouseButtonEvent); ok && t.State == sdl.PRESSED {
+		for _, w := range s.widgets {
+			if btn, ok := w.(*rendering.Button); ok && btn.OnButton(sdl.Point{X: t.X, Y: t.Y}) {
+				s.processButtonClick(btn)
+				return scenes.EventProcessed
+			}
+		}
+	}
+	for _, action := range s.input.Process(e) {
+		if action.Pressed && s.processAction(action.Action) {
+			return scenes.EventProcessed
+		}
+	}
+	return scenes.EventToProcess
+}
+
+func (s *StatsScene) processAction(action input.Action) bool {
+	if used, clicked := s.focus.ProcessAction(action); used {
+		if btn, ok := clicked.(*rendering.Button); ok {
+			s.processButtonClick(btn)
+		}
+		return true
+	}
+	if action == input.Menu {
+		s.Exit()
+		return true
+	}
+	return false
+}
+
+func (s *StatsScene) ProcessResize(w, h int32) {
+	s.layout.Resize(sdl.Rect{X: 0, Y: 0, W: w, H: h})
+}
+
+func (s *StatsScene) Update(deltaMS uint64) {
+	for _, action := range s.input.Update(deltaMS) {
+		s.processAction(action.Action)
+	}
+	s.focus.Update(deltaMS)
+	mousePos := sdl.Point{}
+	mousePos.X, mousePos.Y, _ = sdl.GetMouseState()
+	for _, widget := range s.widgets {
+		if btn, ok := widget.(*rendering.Button); ok {
+			btn.SetHovered(mousePos.InRect(&btn.Rect))
+		}
+	}
+}
+
+func (s *StatsScene) Draw(renderer rendering.CustomRenderer) {
+	s.layout.Draw(&renderer)
+	s.focus.DrawCapturing(&renderer)
+}
+
+func (s *StatsScene) Exit() {
+	s.sceneManager.Pop()
+}
+
+// Reads the history again, games may have been finished since the scene was last shown
+func (s *StatsScene) Enter() error {
+	s.input.Reset()
+	records, err := history.Load(config.HistoryFilePath)
+	if err != nil {
+		log.Println(err)
+		title := lang.Text.Stats.LoadFailed
+		if len(records) > 0 {
+			title = lang.Text.Stats.RecordsSkipped
+		}
+		dialog := scenes.NewAlertDialog(title, err.Error(), nil)
+		if err := s.sceneManager.ShowDialog(dialog); err != nil {
+			log.Printf("statistics: %s\n", err)
+		}
+	}
+	s.records = records
+	if err := s.rebuildWidgets(); err != nil {
+		return err
+	}
+	s.focus.Focus(len(s.widgets) - 1)
+	w, h := s.renderer.SDLwindow.GetSize()
+	s.ProcessResize(w, h)
+	return nil
+}
+
+func (s *StatsScene) Pause() {
+}
+
+func (s *StatsScene) Resume() error {
+	return s.Enter()
+}
+
+func (s *StatsScene) Unload() {
+	s.destroyWidgets()
+}
+
+func (s *StatsScene) IsLoaded() bool {
+	return true
+}
+
+func (s *StatsScene) NeedsRedraw() bool {
+	return true
+}
+
+// position of the value in the values, -1 if it isn't in them
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
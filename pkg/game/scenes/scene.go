@@ -1,6 +1,7 @@
 package scenes
 
 import (
+	"fmt"
 	"minesweeper/pkg/game/rendering"
 
 	"github.com/veandco/go-sdl2/sdl"
@@ -13,15 +14,75 @@ const (
 	EventToProcess
 )
 
+/*
+Screen of the game, in the stack of the scene manager: it gets Enter when it is pushed,
+Pause and Resume while a scene is over it, and Unload when it is popped
+*/
 type Scene interface {
 	ProcessEvent(e sdl.Event) EventState
 	Update(deltaMS uint64)
 	Draw(renderer rendering.CustomRenderer)
+	// leaves the scene (Escape or its back button), usually by popping it
 	Exit()
+	// the scene is pushed on the stack of the scene manager
+	Enter() error
+	// a scene is pushed over it
+	Pause()
+	// the scene over it was popped, it is on top again (or shown under an overlay)
+	Resume() error
+	// the scene is popped
 	Unload()
-	// load() error
-	Enter(reload bool) error
 	ProcessResize(w, h int32)
 	IsLoaded() bool
 	NeedsRedraw() bool
 }
+
+// Scene of a game, which the main menu continues or starts over
+type GameSession interface {
+	Scene
+	// true while the game isn't over
+	InProgress() bool
+	// starts a new game with the config's grid, before the scene is pushed
+	NewGame() error
+	// starts the same board over
+	Restart() error
+	// writes the game in progress in a file, it is restored when the program starts
+	Save() error
+	// summary of the game once it is over
+	Results() GameResults
+	// writes the board in a text file, returns its path
+	ExportBoard() (string, error)
+	// writes the finished game's seed and actions in a file, returns its path
+	SaveReplay() (string, error)
+}
+
+// Summary of a finished game, shown by the results overlay
+type GameResults struct {
+	Won         bool
+	DurationMS  uint64
+	Columns     uint32
+	Rows        uint32
+	BombPercent int
+	Seed        int64
+	TilesOpened int // by the player, with the first opening
+	SafeTiles   int
+	FlagsRight  int
+	FlagsWrong  int // left on safe tiles, or removed by the wrong flag penalty
+	LivesUsed   int // one per exploded bomb and per wrong flag penalty
+	Lives       int // negative without limit
+	Hints       int // the game has no hints nor undos yet, always 0
+	Undos       int
+	Clicks      int    // opening, flagging and chording actions
+	BoardValue  int    // 3BV: least clicks needed to clear the board without flags
+	BestMS      uint64 // best time on these grid settings before this game, 0 if none
+	NewBest     bool
+}
+
+// Time as "m:ss" ("h:mm:ss" past an hour)
+func FormatDuration(ms uint64) string {
+	seconds := ms / 1000
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
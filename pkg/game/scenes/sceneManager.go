@@ -4,20 +4,42 @@ import (
 	"errors"
 	"fmt"
 	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/audio"
+	"minesweeper/pkg/game/input"
+	"minesweeper/pkg/game/lang"
 	"minesweeper/pkg/game/rendering"
+	"minesweeper/pkg/game/theme"
 
 	"github.com/veandco/go-sdl2/sdl"
 )
 
+// Scene in the stack of the scene manager
+type stackEntry struct {
+	name    string
+	scene   Scene
+	overlay bool // drawn over the scene under it
+}
+
+/*
+Stack of scenes: the one on top gets the events and is updated, the ones under it are paused.
+An overlay is drawn over the scene under it (like a pause menu over the game).
+
+The changes of scene are animated by the config's transition
+*/
 type SceneManager struct {
-	currentScene      interface{ Scene }
-	namePreviousScene string
-	nameCurrentScene  string
-	scenes            map[string]Scene
-	defaultSceneName  string
-	IsRunning         *bool
-	config            config.Config
-	renderer          rendering.CustomRenderer
+	stack            []stackEntry
+	scenes           map[string]Scene
+	defaultSceneName string
+	IsRunning        *bool
+	config           config.Config
+	renderer         rendering.CustomRenderer
+	frameTimer       *rendering.FrameTimer
+	audio            *audio.Player
+	gamepads         *input.Gamepads
+	theme            *theme.Theme
+	language         *lang.Language
+	dialogs          []*Dialog // the first one is shown, the others wait for it to be closed
+	transition       transition
 }
 
 func (sm *SceneManager) AddScene(scene Scene, id string) {
@@ -28,15 +50,23 @@ func (sm *SceneManager) AddDefaultScene(scene Scene, id string) {
 	sm.defaultSceneName = id
 }
 
-func NewSceneManager(isRunning *bool, renderer rendering.CustomRenderer, config config.Config) (*SceneManager, error) {
+func NewSceneManager(isRunning *bool, renderer rendering.CustomRenderer, config config.Config, audioPlayer *audio.Player, gamepads *input.Gamepads, currentTheme *theme.Theme, language *lang.Language) (*SceneManager, error) {
 	sm := &SceneManager{
-		scenes:            map[string]Scene{},
-		currentScene:      nil,
-		namePreviousScene: "",
-		defaultSceneName:  "",
-		IsRunning:         isRunning,
-		config:            config,
-		renderer:          renderer,
+		scenes:           map[string]Scene{},
+		defaultSceneName: "",
+		IsRunning:        isRunning,
+		config:           config,
+		renderer:         renderer,
+		audio:            audioPlayer,
+		gamepads:         gamepads,
+		theme:            currentTheme,
+		language:         language,
+	}
+	currentTheme.Use()
+	currentTheme.SetLanguageFonts(language.Config.Fonts)
+	language.Use()
+	if config.Window.FrameStats {
+		sm.frameTimer = rendering.NewFrameTimer("scene draw", 5000)
 	}
 	return sm, nil
 }
@@ -45,8 +75,67 @@ func (sm *SceneManager) GetConfig() config.Config {
 	return sm.config
 }
 
+// Sets the config shared by the scenes, the audio and game controllers settings are applied immediately
 func (sm *SceneManager) SetConfig(cfg config.Config) {
 	sm.config = cfg
+	sm.audio.SetConfig(cfg.Audio)
+	sm.gamepads.SetConfig(cfg.Gamepad)
+}
+
+func (sm *SceneManager) PlaySound(id audio.SoundId) {
+	sm.audio.Play(id)
+}
+
+// Rumbles the game controllers (if enabled in the config)
+func (sm *SceneManager) Rumble(durationMS uint32) {
+	sm.gamepads.Rumble(durationMS)
+}
+
+// False if there is no audio device, the sounds are then never played
+func (sm *SceneManager) IsAudioEnabled() bool {
+	return sm.audio.IsEnabled()
+}
+
+func (sm *SceneManager) GetTheme() *theme.Theme {
+	return sm.theme
+}
+
+/*
+Replaces the theme and frees the previous one.
+
+The scenes rebuild their widgets when they are entered or resumed, the current scene must do it right away
+*/
+func (sm *SceneManager) SetTheme(t *theme.Theme) {
+	if t == sm.theme {
+		return
+	}
+	previous := sm.theme
+	sm.theme = t
+	t.Use()
+	t.SetLanguageFonts(sm.language.Config.Fonts)
+	if previous != nil {
+		previous.Destroy()
+	}
+}
+
+func (sm *SceneManager) GetLanguage() *lang.Language {
+	return sm.language
+}
+
+// Replaces the language, the scenes rebuild their widgets when they are entered or resumed (the current scene must do it right away)
+func (sm *SceneManager) SetLanguage(l *lang.Language) {
+	sm.language = l
+	l.Use()
+	sm.theme.SetLanguageFonts(l.Config.Fonts)
+}
+
+// Destroys the theme, the scenes can't be drawn afterward
+func (sm *SceneManager) Destroy() {
+	sm.transition.destroy()
+	if sm.theme != nil {
+		sm.theme.Destroy()
+		sm.theme = nil
+	}
 }
 
 func (sm *SceneManager) GetScene(name string) (Scene, error) {
@@ -57,32 +146,135 @@ func (sm *SceneManager) GetScene(name string) (Scene, error) {
 	return scene, nil
 }
 
-func (sm *SceneManager) SetScene(name string, reload bool) error {
+// scene taking the input, nil if the stack is empty
+func (sm *SceneManager) top() Scene {
+	if len(sm.stack) == 0 {
+		return nil
+	}
+	return sm.stack[len(sm.stack)-1].scene
+}
+
+// Name of the scene on top of the stack
+func (sm *SceneManager) CurrentSceneName() string {
+	if len(sm.stack) == 0 {
+		return ""
+	}
+	return sm.stack[len(sm.stack)-1].name
+}
+
+// index in the stack of the named scene, -1 if it isn't in it
+func (sm *SceneManager) stackIndex(name string) int {
+	for i, entry := range sm.stack {
+		if entry.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (sm *SceneManager) push(name string, overlay bool) error {
 	newScene, found := sm.scenes[name]
 	if !found {
-		return errors.New("setScene: scene not found")
+		return fmt.Errorf("push %q: scene not found", name)
+	}
+	if sm.stackIndex(name) != -1 {
+		return sm.PopTo(name)
+	}
+	sm.startTransition(false)
+	if current := sm.top(); current != nil {
+		current.Pause()
+	}
+	sm.stack = append(sm.stack, stackEntry{name: name, scene: newScene, overlay: overlay})
+	fmt.Printf("%q enter\n", name)
+	return newScene.Enter()
+}
+
+/*
+Puts the named scene on top of the stack, the current one is paused under it.
+
+A scene already in the stack is gone back to (the scenes over it are popped)
+*/
+func (sm *SceneManager) Push(name string) error {
+	return sm.push(name, false)
+}
+
+// Pushes the scene drawn over the current one, which stays shown (and paused) under it
+func (sm *SceneManager) PushOverlay(name string) error {
+	return sm.push(name, true)
+}
+
+// Leaves the current scene for the one under it, leaving the last one quits
+func (sm *SceneManager) Pop() error {
+	if len(sm.stack) <= 1 {
+		sm.Quit()
+		return nil
 	}
-	if sm.currentScene != nil {
-		fmt.Printf("%q unload\n", sm.nameCurrentScene)
-		sm.currentScene.Unload()
-		sm.namePreviousScene = sm.nameCurrentScene
+	sm.startTransition(true)
+	sm.popTop()
+	return sm.resumeShown()
+}
+
+// resumes the scene on top and the ones shown under its overlays, the settings may have changed
+func (sm *SceneManager) resumeShown() error {
+	for _, entry := range sm.stack[sm.firstShown():] {
+		if err := entry.scene.Resume(); err != nil {
+			return err
+		}
 	}
-	sm.currentScene = newScene
-	sm.nameCurrentScene = name
-	fmt.Printf("%q load\n", sm.nameCurrentScene)
-	sm.currentScene.Enter(reload)
 	return nil
 }
-func (sm *SceneManager) SetLastScene(reload bool) error {
-	return sm.SetScene(sm.namePreviousScene, reload)
+
+func (sm *SceneManager) popTop() {
+	entry := sm.stack[len(sm.stack)-1]
+	sm.stack = sm.stack[:len(sm.stack)-1]
+	fmt.Printf("%q unload\n", entry.name)
+	entry.scene.Unload()
+}
+
+// Pops the scenes over the named one
+func (sm *SceneManager) PopTo(name string) error {
+	i := sm.stackIndex(name)
+	if i == -1 {
+		return fmt.Errorf("pop to %q: scene not in the stack", name)
+	}
+	if i == len(sm.stack)-1 {
+		return nil
+	}
+	sm.startTransition(true)
+	for len(sm.stack) > i+1 {
+		sm.popTop()
+	}
+	return sm.resumeShown()
 }
 
-func (sm *SceneManager) SetSceneDefault(reload bool) error {
-	return sm.SetScene(sm.defaultSceneName, reload)
+// Pops the scenes over the default one (the main menu)
+func (sm *SceneManager) PopToDefault() error {
+	return sm.PopTo(sm.defaultSceneName)
 }
+
+// Replaces the current scene by the named one
+func (sm *SceneManager) Replace(name string) error {
+	newScene, found := sm.scenes[name]
+	if !found {
+		return fmt.Errorf("replace by %q: scene not found", name)
+	}
+	if sm.stackIndex(name) != -1 {
+		return sm.PopTo(name)
+	}
+	sm.startTransition(false)
+	overlay := false
+	if len(sm.stack) > 0 {
+		overlay = sm.stack[len(sm.stack)-1].overlay
+		sm.popTop()
+	}
+	sm.stack = append(sm.stack, stackEntry{name: name, scene: newScene, overlay: overlay})
+	fmt.Printf("%q enter\n", name)
+	return newScene.Enter()
+}
+
 func (sm *SceneManager) ExitCurrentScene() {
-	if sm.currentScene != nil {
-		sm.currentScene.Exit()
+	if current := sm.top(); current != nil {
+		current.Exit()
 	}
 }
 
@@ -91,49 +283,109 @@ func (sm *SceneManager) Quit() {
 	*sm.IsRunning = false
 }
 
+// index in the stack of the lowest scene drawn: the first one under the overlays
+func (sm *SceneManager) firstShown() int {
+	i := len(sm.stack) - 1
+	for i > 0 && sm.stack[i].overlay {
+		i--
+	}
+	return i
+}
+
 func (sm *SceneManager) ProcessEvent(e sdl.Event) {
-	if sm.currentScene == nil {
+	current := sm.top()
+	if current == nil {
 		sm.Quit()
 		return
 	}
-	if sm.currentScene.ProcessEvent(e) == EventProcessed {
+	sm.gamepads.ProcessEvent(e)
+	if t, ok := e.(*sdl.WindowEvent); ok && t.Event == sdl.WINDOWEVENT_SIZE_CHANGED {
+		// the paused scenes are resized too, they may be shown under an overlay
+		for _, entry := range sm.stack {
+			entry.scene.ProcessResize(t.Data1, t.Data2)
+		}
+		for _, d := range sm.dialogs {
+			d.resize(t.Data1, t.Data2)
+		}
 		return
 	}
-	switch t := e.(type) {
-	case *sdl.KeyboardEvent:
-		keyCode := t.Keysym.Sym
-		pressed := t.State == sdl.PRESSED
-		if pressed {
-			if keyCode == sdl.K_ESCAPE {
-				if sm.nameCurrentScene == sm.defaultSceneName {
-					sm.Quit()
-				}
-			}
+	if sm.HasDialog() {
+		if _, ok := e.(*sdl.RenderEvent); ok {
+			current.ProcessEvent(e)
+		} else {
+			sm.processDialogEvent(e)
 		}
-
-	case *sdl.WindowEvent:
-		if t.Event == sdl.WINDOWEVENT_SIZE_CHANGED {
-			sm.currentScene.ProcessResize(t.Data1, t.Data2)
+		return
+	}
+	if current.ProcessEvent(e) == EventProcessed {
+		return
+	}
+	if t, ok := e.(*sdl.KeyboardEvent); ok && t.State == sdl.PRESSED && t.Keysym.Sym == config.KeyBack {
+		if sm.CurrentSceneName() == sm.defaultSceneName {
+			sm.Quit()
 		}
 	}
-
 }
+
 func (sm *SceneManager) Update(deltaMS uint64) {
-	if sm.currentScene == nil {
+	current := sm.top()
+	if current == nil {
 		sm.Quit()
 		return
 	}
-	sm.currentScene.Update(deltaMS)
+	sm.updateTransition(deltaMS)
+	if sm.HasDialog() {
+		// the scene is paused under the dialog
+		if done, choice := sm.dialogs[0].update(deltaMS); done {
+			sm.closeDialog(choice)
+		}
+		return
+	}
+	current.Update(deltaMS)
+}
+
+// draws the scenes shown, from the one under the overlays
+func (sm *SceneManager) drawScenes(renderer rendering.CustomRenderer) {
+	sm.renderer.SetDrawColor(theme.Palette.Background)
+	sm.renderer.SDLrenderer.Clear()
+	for _, entry := range sm.stack[sm.firstShown():] {
+		entry.scene.Draw(renderer)
+	}
 }
+
+func (sm *SceneManager) needsRedraw() bool {
+	if sm.HasDialog() || sm.transition.active() {
+		return true
+	}
+	for _, entry := range sm.stack[sm.firstShown():] {
+		if entry.scene.NeedsRedraw() {
+			return true
+		}
+	}
+	return false
+}
+
 func (sm *SceneManager) Draw(renderer rendering.CustomRenderer) {
-	if sm.currentScene == nil {
+	if sm.top() == nil {
 		sm.Quit()
 		return
 	}
-	if sm.currentScene.NeedsRedraw() {
-		sm.renderer.SDLrenderer.SetDrawColor(20, 20, 20, sdl.ALPHA_OPAQUE)
-		sm.renderer.SDLrenderer.Clear()
-		sm.currentScene.Draw(renderer)
-		sm.renderer.SDLrenderer.Present()
+	if !sm.needsRedraw() {
+		return
+	}
+	if sm.frameTimer != nil {
+		sm.frameTimer.Start()
+	}
+	if sm.transition.active() {
+		sm.drawTransition(renderer)
+	} else {
+		sm.drawScenes(renderer)
+	}
+	if sm.HasDialog() {
+		sm.dialogs[0].draw(&renderer)
+	}
+	if sm.frameTimer != nil {
+		sm.frameTimer.Stop()
 	}
+	sm.renderer.SDLrenderer.Present()
 }
@@ -0,0 +1,128 @@
+package scenes
+
+import (
+	"errors"
+	"log"
+	"minesweeper/pkg/game/rendering"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// kinds of the transitions between the scenes (config.Transitions)
+const (
+	transitionNone  = "none"
+	transitionSlide = "slide"
+)
+
+/*
+Animation from the last frame of the previous scenes to the new ones: the new scenes fade in
+over it, or slide it out (to the left when a scene is pushed, to the right when it is popped).
+
+The previous frame is drawn in a texture when the scene changes, the new scenes are drawn in
+another one on each frame of the transition
+*/
+type transition struct {
+	kind       string
+	durationMS uint64
+	elapsedMS  uint64
+	backward   bool // a scene was popped
+	from       *sdl.Texture
+	to         *sdl.Texture
+}
+
+func (t *transition) active() bool {
+	return t.elapsedMS < t.durationMS
+}
+
+// progress of the transition from 0 to 1, easing out
+func (t *transition) progress() float64 {
+	p := float64(t.elapsedMS) / float64(t.durationMS)
+	return 1 - (1-p)*(1-p)
+}
+
+func (t *transition) destroy() {
+	for _, texture := range []*sdl.Texture{t.from, t.to} {
+		if texture != nil {
+			texture.Destroy()
+		}
+	}
+	t.from, t.to = nil, nil
+}
+
+// (re)creates the textures at the size of the screen
+func (t *transition) textures(renderer *sdl.Renderer) error {
+	if !renderer.RenderTargetSupported() {
+		return errors.New("render targets not supported")
+	}
+	w, h, err := renderer.GetOutputSize()
+	if err != nil {
+		return err
+	}
+	if t.from != nil {
+		if _, _, tw, th, _ := t.from.Query(); tw == w && th == h {
+			return nil
+		}
+	}
+	t.destroy()
+	for _, texture := range []**sdl.Texture{&t.from, &t.to} {
+		*texture, err = renderer.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET, w, h)
+		if err != nil {
+			t.destroy()
+			return err
+		}
+	}
+	t.to.SetBlendMode(sdl.BLENDMODE_BLEND)
+	return nil
+}
+
+// Draws the scenes shown before the change of scene, to start the transition from them
+func (sm *SceneManager) startTransition(backward bool) {
+	cfg := sm.config.Window
+	sm.transition.kind = cfg.Transition
+	sm.transition.durationMS = uint64(cfg.TransitionMS)
+	sm.transition.elapsedMS = sm.transition.durationMS
+	if cfg.Transition == transitionNone || cfg.TransitionMS <= 0 || len(sm.stack) == 0 {
+		return
+	}
+	if err := sm.transition.textures(sm.renderer.SDLrenderer); err != nil {
+		log.Printf("transition: %s (no transition)\n", err)
+		return
+	}
+	sm.renderer.SDLrenderer.SetRenderTarget(sm.transition.from)
+	sm.drawScenes(sm.renderer)
+	sm.renderer.SDLrenderer.SetRenderTarget(nil)
+	sm.transition.elapsedMS = 0
+	sm.transition.backward = backward
+}
+
+func (sm *SceneManager) updateTransition(deltaMS uint64) {
+	if sm.transition.active() {
+		sm.transition.elapsedMS += deltaMS
+	}
+}
+
+func (sm *SceneManager) drawTransition(renderer rendering.CustomRenderer) {
+	t := &sm.transition
+	r := sm.renderer.SDLrenderer
+	r.SetRenderTarget(t.to)
+	sm.drawScenes(renderer)
+	r.SetRenderTarget(nil)
+
+	w, h, _ := r.GetOutputSize()
+	p := t.progress()
+	switch t.kind {
+	case transitionSlide:
+		offset := int32(float64(w) * p)
+		if t.backward {
+			r.Copy(t.from, nil, &sdl.Rect{X: offset, Y: 0, W: w, H: h})
+			r.Copy(t.to, nil, &sdl.Rect{X: offset - w, Y: 0, W: w, H: h})
+		} else {
+			r.Copy(t.from, nil, &sdl.Rect{X: -offset, Y: 0, W: w, H: h})
+			r.Copy(t.to, nil, &sdl.Rect{X: w - offset, Y: 0, W: w, H: h})
+		}
+	default:
+		r.Copy(t.from, nil, nil)
+		t.to.SetAlphaMod(uint8(255 * p))
+		r.Copy(t.to, nil, nil)
+	}
+}
@@ -0,0 +1,200 @@
+package theme
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"minesweeper/pkg/config"
+	"minesweeper/pkg/game/rendering"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Colors of the current theme, the scenes' widgets data keep pointers to them
+type Colors struct {
+	Background        sdl.Color
+	Text              sdl.Color
+	TextDisabled      sdl.Color
+	TextSeparator     sdl.Color
+	ButtonBackground  sdl.Color
+	ButtonHover       sdl.Color
+	ButtonHoverMenu   sdl.Color
+	ButtonHoverAccent sdl.Color
+	HudBackground     sdl.Color
+}
+
+// Palette of the current theme, updated by Use
+var Palette = paletteColors(config.DefaultTheme.Palette)
+
+/*
+Loaded theme: its spritesheet, font and palette.
+
+The scenes keep the theme they were built with and rebuild their widgets when
+the scene manager's theme changed
+*/
+type Theme struct {
+	Id          string // name of the theme's directory, empty for the default theme
+	Config      config.ThemeConfig
+	Spritesheet *rendering.Spritesheet
+	Fonts       *rendering.FontManager
+	Font        *rendering.Font // at the theme's size
+	Palette     Colors
+	dirs        []string // where the files are looked up
+}
+
+func paletteColors(p config.ThemePalette) Colors {
+	return Colors{
+		Background:        sdl.Color(p.Background),
+		Text:              sdl.Color(p.Text),
+		TextDisabled:      sdl.Color(p.TextDisabled),
+		TextSeparator:     sdl.Color(p.TextSeparator),
+		ButtonBackground:  sdl.Color(p.ButtonBackground),
+		ButtonHover:       sdl.Color(p.ButtonHover),
+		ButtonHoverMenu:   sdl.Color(p.ButtonHoverMenu),
+		ButtonHoverAccent: sdl.Color(p.ButtonHoverAccent),
+		HudBackground:     sdl.Color(p.HudBackground),
+	}
+}
+
+// Names of the directories of themesPath containing a manifest, sorted
+func List(themesPath string) []string {
+	entries, err := ioutil.ReadDir(themesPath)
+	if err != nil {
+		log.Printf("theme list: %s\n", err)
+		return nil
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(themesPath, entry.Name(), config.ThemeManifestFile)); err == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Name of the theme from its manifest (its id if the manifest can't be read), without loading its files
+func Name(themesPath, id string) string {
+	cfg := config.DefaultTheme
+	if err := config.LoadConfig(filepath.Join(themesPath, id, config.ThemeManifestFile), &cfg); err != nil || cfg.Name == "" {
+		return id
+	}
+	return cfg.Name
+}
+
+/*
+Loads the theme from its directory in themesPath.
+
+The values missing from the manifest are the default theme's ones,
+the files are looked up in the theme's directory then in resourcesPath
+*/
+func Load(renderer *rendering.CustomRenderer, themesPath, id, resourcesPath string) (*Theme, error) {
+	dir := filepath.Join(themesPath, id)
+	cfg := config.DefaultTheme
+	if err := config.LoadConfig(filepath.Join(dir, config.ThemeManifestFile), &cfg); err != nil {
+		return nil, fmt.Errorf("theme %q: %s", id, err)
+	}
+	if err := cfg.Check(); err != nil {
+		return nil, fmt.Errorf("theme %q: %s", id, err)
+	}
+	t, err := newTheme(renderer, cfg, []string{dir, resourcesPath})
+	if err != nil {
+		return nil, fmt.Errorf("theme %q: %s", id, err)
+	}
+	t.Id = id
+	return t, nil
+}
+
+// Loads the built-in theme, its files are in resourcesPath (fontFile replaces its font if not empty)
+func LoadDefault(renderer *rendering.CustomRenderer, resourcesPath, fontFile string) (*Theme, error) {
+	cfg := config.DefaultTheme
+	if fontFile != "" {
+		cfg.Font.File = fontFile
+	}
+	return newTheme(renderer, cfg, []string{resourcesPath})
+}
+
+func newTheme(renderer *rendering.CustomRenderer, cfg config.ThemeConfig, dirs []string) (*Theme, error) {
+	sheet := cfg.Spritesheet
+	var spritesheet *rendering.Spritesheet
+	var err error
+	if sheet.Atlas != "" {
+		spritesheet, err = rendering.NewAtlas(renderer, findFile(dirs, sheet.Atlas))
+	} else {
+		spritesheet, err = rendering.NewSpritesheet(renderer, findFile(dirs, sheet.File), sheet.Columns, sheet.Rows)
+		if err == nil {
+			spritesheet.SetGridVariant(rendering.VariantHover, sheet.HighlightOffset)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("spritesheet load: %s", err)
+	}
+	for _, name := range sheet.Sprites.Names() {
+		if _, err := spritesheet.SpriteId(name); err != nil {
+			spritesheet.Destroy()
+			return nil, fmt.Errorf("spritesheet: %s", err)
+		}
+	}
+	fonts := rendering.NewFontManager(findFile(dirs, cfg.Font.File), findFiles(dirs, cfg.Font.Fallbacks))
+	font, err := fonts.Font(cfg.Font.Size)
+	if err != nil {
+		spritesheet.Destroy()
+		fonts.Destroy()
+		return nil, fmt.Errorf("font load: %s", err)
+	}
+	return &Theme{
+		Config:      cfg,
+		Spritesheet: spritesheet,
+		Fonts:       fonts,
+		Font:        font,
+		Palette:     paletteColors(cfg.Palette),
+		dirs:        dirs,
+	}, nil
+}
+
+// Path of the file in the first directory containing it (the last directory if none does)
+func findFile(dirs []string, file string) string {
+	for _, dir := range dirs {
+		path := filepath.Join(dir, file)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dirs[len(dirs)-1], file)
+}
+
+func findFiles(dirs []string, files []string) []string {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = findFile(dirs, file)
+	}
+	return paths
+}
+
+// Sets the fallback fonts of the language's script, looked up like the theme's font
+func (t *Theme) SetLanguageFonts(files []string) {
+	t.Fonts.SetLanguageFallbacks(findFiles(t.dirs, files))
+}
+
+// Makes the theme's palette the current one
+func (t *Theme) Use() {
+	Palette = t.Palette
+}
+
+func (t *Theme) Destroy() {
+	if t.Spritesheet != nil {
+		t.Spritesheet.Destroy()
+		t.Spritesheet = nil
+	}
+	if t.Fonts != nil {
+		t.Fonts.Destroy()
+		t.Fonts = nil
+		t.Font = nil
+	}
+}
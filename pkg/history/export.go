@@ -0,0 +1,87 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var csvHeader = []string{
+	"player", "finished_at", "columns", "rows", "bomb_percent", "lives", "wrong_flag_penalty", "seed",
+	"won", "duration_ms", "tiles_opened", "flags_right", "flags_wrong", "lives_used", "clicks", "board_value",
+}
+
+// Checks the format before the output is created
+func CheckFormat(format string) error {
+	if format != FormatCSV && format != FormatJSON {
+		return fmt.Errorf("export history: unknown format %q (expected %s or %s)", format, FormatCSV, FormatJSON)
+	}
+	return nil
+}
+
+// Writes the records in the format, one row (or object) per game
+func Export(w io.Writer, records []Record, format string) error {
+	if err := CheckFormat(format); err != nil {
+		return err
+	}
+	if format == FormatJSON {
+		return writeJSON(w, records)
+	}
+	return writeCSV(w, records)
+}
+
+func writeCSV(w io.Writer, records []Record) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return fmt.Errorf("export history: %s", err)
+	}
+	for _, r := range records {
+		row := []string{
+			r.Player,
+			r.FinishedAt.Format(time.RFC3339),
+			strconv.FormatUint(uint64(r.Columns), 10),
+			strconv.FormatUint(uint64(r.Rows), 10),
+			strconv.Itoa(r.BombPercent),
+			strconv.Itoa(r.Lives),
+			strconv.FormatBool(r.WrongFlagPenalty),
+			strconv.FormatInt(r.Seed, 10),
+			strconv.FormatBool(r.Won),
+			strconv.FormatUint(r.DurationMS, 10),
+			strconv.Itoa(r.TilesOpened),
+			strconv.Itoa(r.FlagsRight),
+			strconv.Itoa(r.FlagsWrong),
+			strconv.Itoa(r.LivesUsed),
+			strconv.Itoa(r.Clicks),
+			strconv.Itoa(r.BoardValue),
+		}
+		if err := out.Write(row); err != nil {
+			return fmt.Errorf("export history: %s", err)
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("export history: %s", err)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, records []Record) error {
+	if records == nil {
+		records = []Record{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(records); err != nil {
+		return fmt.Errorf("export history: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportCSV(t *testing.T) {
+	r := Record{
+		Player:           "Ann, \"the\" player",
+		FinishedAt:       time.Date(2026, 3, 1, 12, 30, 0, 0, time.FixedZone("", 3600)),
+		Columns:          30,
+		Rows:             20,
+		BombPercent:      15,
+		Lives:            -1,
+		WrongFlagPenalty: true,
+		Seed:             -42,
+		Won:              true,
+		DurationMS:       123456,
+		TilesOpened:      510,
+		FlagsRight:       88,
+		FlagsWrong:       2,
+		LivesUsed:        3,
+		Clicks:           240,
+		BoardValue:       97,
+	}
+	tests := []struct {
+		name    string
+		records []Record
+		want    string
+	}{
+		{
+			name: "header only",
+			want: "player,finished_at,columns,rows,bomb_percent,lives,wrong_flag_penalty,seed," +
+				"won,duration_ms,tiles_opened,flags_right,flags_wrong,lives_used,clicks,board_value\n",
+		},
+		{
+			name:    "one row per record",
+			records: []Record{r},
+			want: "player,finished_at,columns,rows,bomb_percent,lives,wrong_flag_penalty,seed," +
+				"won,duration_ms,tiles_opened,flags_right,flags_wrong,lives_used,clicks,board_value\n" +
+				"\"Ann, \"\"the\"\" player\",2026-03-01T12:30:00+01:00,30,20,15,-1,true,-42,true,123456,510,88,2,3,240,97\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Export(&out, test.records, FormatCSV); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != test.want {
+				t.Errorf("CSV =\n%s\nwant\n%s", out.String(), test.want)
+			}
+		})
+	}
+}
+
+// The CSV columns are the record's fields in their order, named like in the history file
+func TestCSVHeaderMatchesRecord(t *testing.T) {
+	if !reflect.DeepEqual(csvHeader, recordKeys) {
+		t.Errorf("CSV header %v, want the record's keys %v", csvHeader, recordKeys)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []Record
+	}{
+		{"no records", nil},
+		{"records", []Record{testRecord("Ann", true, 1000), testRecord("Bob", false, 2000)}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Export(&out, test.records, FormatJSON); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(out.String(), "[") {
+				t.Errorf("JSON %q isn't a list", out.String())
+			}
+			var decoded []Record
+			if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if len(decoded) != len(test.records) {
+				t.Fatalf("decoded %d records, want %d", len(decoded), len(test.records))
+			}
+			for i := range decoded {
+				if !reflect.DeepEqual(decoded[i], test.records[i]) {
+					t.Errorf("record %d = %+v, want %+v", i, decoded[i], test.records[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{FormatCSV, false},
+		{FormatJSON, false},
+		{"xml", true},
+		{"", true},
+		{"CSV", true},
+	}
+	for _, test := range tests {
+		if err := CheckFormat(test.format); (err != nil) != test.wantErr {
+			t.Errorf("CheckFormat(%q) = %v, want an error: %t", test.format, err, test.wantErr)
+		}
+		var out bytes.Buffer
+		if err := Export(&out, nil, test.format); test.wantErr && (err == nil || out.Len() > 0) {
+			t.Errorf("Export in %q = %v with %d bytes, want an error and no output", test.format, err, out.Len())
+		}
+	}
+}
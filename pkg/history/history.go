@@ -0,0 +1,158 @@
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// A finished game, with the grid settings it was played with
+type Record struct {
+	Player           string    `yaml:"player" json:"player"`
+	FinishedAt       time.Time `yaml:"finished_at" json:"finished_at"`
+	Columns          uint32    `yaml:"columns" json:"columns"`
+	Rows             uint32    `yaml:"rows" json:"rows"`
+	BombPercent      int       `yaml:"bomb_percent" json:"bomb_percent"`
+	Lives            int       `yaml:"lives" json:"lives"` // negative without limit
+	WrongFlagPenalty bool      `yaml:"wrong_flag_penalty" json:"wrong_flag_penalty"`
+	Seed             int64     `yaml:"seed" json:"seed"`
+	Won              bool      `yaml:"won" json:"won"`
+	DurationMS       uint64    `yaml:"duration_ms" json:"duration_ms"`
+	TilesOpened      int       `yaml:"tiles_opened" json:"tiles_opened"`
+	FlagsRight       int       `yaml:"flags_right" json:"flags_right"`
+	FlagsWrong       int       `yaml:"flags_wrong" json:"flags_wrong"`
+	LivesUsed        int       `yaml:"lives_used" json:"lives_used"`
+	Clicks           int       `yaml:"clicks" json:"clicks"`
+	BoardValue       int       `yaml:"board_value" json:"board_value"` // 3BV
+}
+
+// Grid settings of the record, like "10x10-10%"
+func (r Record) Difficulty() string {
+	return fmt.Sprintf("%dx%d-%d%%", r.Columns, r.Rows, r.BombPercent)
+}
+
+/*
+Adds the record at the end of the history file (one YAML document per record), which is created if needed.
+The previous records are never rewritten
+*/
+func Append(filePath string, r Record) error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("history: couldn't marshal the record: %s", err)
+	}
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("history: %s", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append([]byte("---\n"), data...)); err != nil {
+		return fmt.Errorf("history: couldn't write the record: %s", err)
+	}
+	return nil
+}
+
+/*
+Records of the history file, oldest first. There are none until a game is finished.
+
+A record that can't be read (cut by a crash while it was written, or edited by hand) is skipped,
+the others are returned with an error listing the skipped ones
+*/
+func Load(filePath string) ([]Record, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("history: %s", err)
+	}
+	records := []Record{}
+	skipped := []string{}
+	// yaml.v2's Decoder can't go on after a syntax error, each document is decoded on its own
+	for _, document := range splitDocuments(data) {
+		documentRecords, err := decodeDocument(document.data)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("record at line %d: %s", document.line, err))
+			continue
+		}
+		records = append(records, documentRecords...)
+	}
+	if len(skipped) > 0 {
+		return records, fmt.Errorf("history: skipped %d unreadable record(s): %s", len(skipped), strings.Join(skipped, "; "))
+	}
+	return records, nil
+}
+
+// Text of the history file between two "---" lines, line is its first line in the file
+type document struct {
+	line int
+	data []byte
+}
+
+// Documents of the history file, without the empty ones
+func splitDocuments(data []byte) []document {
+	documents := []document{}
+	current := document{line: 1}
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if string(bytes.TrimRight(line, "\r\n")) != "---" {
+			current.data = append(current.data, line...)
+			continue
+		}
+		if len(bytes.TrimSpace(current.data)) > 0 {
+			documents = append(documents, current)
+		}
+		current = document{line: i + 2}
+	}
+	if len(bytes.TrimSpace(current.data)) > 0 {
+		documents = append(documents, current)
+	}
+	return documents
+}
+
+// YAML keys of a Record, all of them are written so a record missing one was cut
+var recordKeys = func() []string {
+	keys := []string{}
+	t := reflect.TypeOf(Record{})
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0])
+	}
+	return keys
+}()
+
+// Records of a document: a record, or the YAML list of records of the first history files
+func decodeDocument(data []byte) ([]Record, error) {
+	var records []Record
+	var fields []map[string]interface{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-")) {
+		if err := yaml.UnmarshalStrict(data, &records); err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+	} else {
+		var r Record
+		var f map[string]interface{}
+		if err := yaml.UnmarshalStrict(data, &r); err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, err
+		}
+		records = []Record{r}
+		fields = []map[string]interface{}{f}
+	}
+	for _, f := range fields {
+		for _, key := range recordKeys {
+			if _, found := f[key]; !found {
+				return nil, fmt.Errorf("missing %s", key)
+			}
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,175 @@
+package history
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func testRecord(player string, won bool, durationMS uint64) Record {
+	return Record{
+		Player:      player,
+		FinishedAt:  time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Columns:     10,
+		Rows:        10,
+		BombPercent: 10,
+		Lives:       -1,
+		Seed:        42,
+		Won:         won,
+		DurationMS:  durationMS,
+		TilesOpened: 90,
+		BoardValue:  12,
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.yml")
+	records, err := Load(path)
+	if err != nil || records != nil {
+		t.Fatalf("Load of a missing file = %v, %v, want no records and no error", records, err)
+	}
+	want := []Record{testRecord("Ann", true, 1500), testRecord("Bob", false, 800), testRecord("Ann", true, 900)}
+	for _, r := range want {
+		if err := Append(path, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(want) {
+		t.Fatalf("loaded %d records, want %d", len(records), len(want))
+	}
+	for i := range want {
+		if !records[i].FinishedAt.Equal(want[i].FinishedAt) {
+			t.Errorf("record %d finished at %v, want %v", i, records[i].FinishedAt, want[i].FinishedAt)
+		}
+		records[i].FinishedAt = want[i].FinishedAt
+		if records[i] != want[i] {
+			t.Errorf("record %d = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+}
+
+// history file of the records, each one cut or edited by edit (if not nil)
+func historyFile(t *testing.T, records []Record, edit func(i int, document string) string) string {
+	var content strings.Builder
+	for i, r := range records {
+		data, err := yaml.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		document := "---\n" + string(data)
+		if edit != nil {
+			document = edit(i, document)
+		}
+		content.WriteString(document)
+	}
+	return content.String()
+}
+
+func TestLoadSkipsUnreadableRecords(t *testing.T) {
+	three := []Record{testRecord("Ann", true, 1500), testRecord("Bob", false, 800), testRecord("Cid", true, 900)}
+	legacy, err := yaml.Marshal(three[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		content string
+		players []string
+		skipped bool
+	}{
+		{
+			name:    "complete",
+			content: historyFile(t, three, nil),
+			players: []string{"Ann", "Bob", "Cid"},
+		},
+		{
+			name: "last record cut while written",
+			content: historyFile(t, three, func(i int, document string) string {
+				if i == 2 {
+					return document[:len(document)/2]
+				}
+				return document
+			}),
+			players: []string{"Ann", "Bob"},
+			skipped: true,
+		},
+		{
+			name: "record cut after a field",
+			content: historyFile(t, three, func(i int, document string) string {
+				if i == 2 {
+					return document[:strings.Index(document, "duration_ms:")]
+				}
+				return document
+			}),
+			players: []string{"Ann", "Bob"},
+			skipped: true,
+		},
+		{
+			name: "record edited by hand",
+			content: historyFile(t, three, func(i int, document string) string {
+				if i == 1 {
+					return strings.Replace(document, "won: false", "won: [", 1)
+				}
+				return document
+			}),
+			players: []string{"Ann", "Cid"},
+			skipped: true,
+		},
+		{
+			name: "unknown field",
+			content: historyFile(t, three, func(i int, document string) string {
+				if i == 0 {
+					return document + "extra: 1\n"
+				}
+				return document
+			}),
+			players: []string{"Bob", "Cid"},
+			skipped: true,
+		},
+		{
+			name:    "list of the first history files",
+			content: string(legacy),
+			players: []string{"Ann", "Bob"},
+		},
+		{
+			name:    "records appended after a list",
+			content: string(legacy) + historyFile(t, three[2:], nil),
+			players: []string{"Ann", "Bob", "Cid"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.yml")
+			if err := ioutil.WriteFile(path, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			records, err := Load(path)
+			if (err != nil) != test.skipped {
+				t.Errorf("error = %v, want one: %t", err, test.skipped)
+			}
+			players := []string{}
+			for _, r := range records {
+				players = append(players, r.Player)
+			}
+			if strings.Join(players, ",") != strings.Join(test.players, ",") {
+				t.Errorf("players = %v, want %v", players, test.players)
+			}
+		})
+	}
+}
+
+func TestLoadUnreadableFile(t *testing.T) {
+	// a directory can't be read as a file
+	records, err := Load(t.TempDir())
+	if err == nil || records != nil {
+		t.Errorf("Load of a directory = %v, %v, want an error and no records", records, err)
+	}
+}
@@ -0,0 +1,148 @@
+package history
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Games of a grid setting, the times are of the won games (0 without any)
+type DifficultyStats struct {
+	Difficulty string
+	Played     int
+	Won        int
+	BestMS     uint64
+	AverageMS  uint64
+}
+
+type Stats struct {
+	Played        int
+	Won           int
+	CurrentStreak int // games won in a row, up to the last one
+	LongestStreak int
+	Difficulties  []DifficultyStats // by name
+}
+
+// Percentage of the games won
+func (s Stats) WinRate() int {
+	if s.Played == 0 {
+		return 0
+	}
+	return s.Won * 100 / s.Played
+}
+
+// Totals of the records, which are in the order they were played
+func Summarize(records []Record) Stats {
+	stats := Stats{Played: len(records)}
+	byDifficulty := map[string]*DifficultyStats{}
+	totalMS := map[string]uint64{}
+	streak := 0
+	for _, r := range records {
+		key := r.Difficulty()
+		d, found := byDifficulty[key]
+		if !found {
+			d = &DifficultyStats{Difficulty: key}
+			byDifficulty[key] = d
+		}
+		d.Played++
+		if !r.Won {
+			streak = 0
+			continue
+		}
+		stats.Won++
+		streak++
+		if streak > stats.LongestStreak {
+			stats.LongestStreak = streak
+		}
+		d.Won++
+		totalMS[key] += r.DurationMS
+		if d.BestMS == 0 || r.DurationMS < d.BestMS {
+			d.BestMS = r.DurationMS
+		}
+	}
+	stats.CurrentStreak = streak
+	for key, d := range byDifficulty {
+		if d.Won > 0 {
+			d.AverageMS = totalMS[key] / uint64(d.Won)
+		}
+		stats.Difficulties = append(stats.Difficulties, *d)
+	}
+	sort.Slice(stats.Difficulties, func(i, j int) bool {
+		return stats.Difficulties[i].Difficulty < stats.Difficulties[j].Difficulty
+	})
+	return stats
+}
+
+/*
+Counts of the won games by time: the times from 0 to the longest one are split in count buckets
+of bucketMS each (rounded up to a second)
+*/
+func Histogram(records []Record, count int) (buckets []int, bucketMS uint64) {
+	var longest uint64
+	for _, r := range records {
+		if r.Won && r.DurationMS > longest {
+			longest = r.DurationMS
+		}
+	}
+	buckets = make([]int, count)
+	if longest == 0 || count <= 0 {
+		return buckets, 0
+	}
+	bucketMS = (longest/uint64(count)/1000 + 1) * 1000
+	for _, r := range records {
+		if r.Won {
+			buckets[r.DurationMS/bucketMS]++
+		}
+	}
+	return buckets, bucketMS
+}
+
+// Records of the player and the grid settings, "" for any
+func Filter(records []Record, player, difficulty string) []Record {
+	var filtered []Record
+	for _, r := range records {
+		if (player == "" || r.Player == player) && (difficulty == "" || r.Difficulty() == difficulty) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// Names of the players of the records, sorted
+func Players(records []Record) []string {
+	return distinct(records, func(r Record) string { return r.Player })
+}
+
+// Grid settings of the records, sorted like the Stats' Difficulties
+func Difficulties(records []Record) []string {
+	return distinct(records, Record.Difficulty)
+}
+
+func distinct(records []Record, key func(Record) string) []string {
+	found := map[string]bool{}
+	var keys []string
+	for _, r := range records {
+		if k := key(r); !found[k] {
+			found[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Games played on each of the days up to the one of now (in its time zone), the oldest first
+func GamesPerDay(records []Record, now time.Time, days int) []int {
+	counts := make([]int, days)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, r := range records {
+		at := r.FinishedAt.In(now.Location())
+		day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, now.Location())
+		// rounded for the days changing the daylight saving time, down so a later day isn't today
+		ago := int(math.Floor((today.Sub(day).Hours() + 12) / 24))
+		if ago >= 0 && ago < days {
+			counts[days-1-ago]++
+		}
+	}
+	return counts
+}
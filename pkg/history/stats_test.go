@@ -0,0 +1,239 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func gridRecord(columns uint32, won bool, durationMS uint64) Record {
+	r := testRecord("Ann", won, durationMS)
+	r.Columns = columns
+	r.Rows = columns
+	return r
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []Record
+		want    Stats
+	}{
+		{
+			name: "no games",
+			want: Stats{},
+		},
+		{
+			name: "streak reset by a loss",
+			records: []Record{
+				gridRecord(10, true, 3000), gridRecord(10, true, 2000), gridRecord(10, true, 4000),
+				gridRecord(10, false, 500),
+				gridRecord(10, true, 6000),
+			},
+			want: Stats{
+				Played: 5, Won: 4, CurrentStreak: 1, LongestStreak: 3,
+				Difficulties: []DifficultyStats{{Difficulty: "10x10-10%", Played: 5, Won: 4, BestMS: 2000, AverageMS: 3750}},
+			},
+		},
+		{
+			name:    "last game lost",
+			records: []Record{gridRecord(10, true, 1000), gridRecord(10, false, 100)},
+			want: Stats{
+				Played: 2, Won: 1, CurrentStreak: 0, LongestStreak: 1,
+				Difficulties: []DifficultyStats{{Difficulty: "10x10-10%", Played: 2, Won: 1, BestMS: 1000, AverageMS: 1000}},
+			},
+		},
+		{
+			name: "times of the won games only, by grid",
+			records: []Record{
+				gridRecord(20, false, 100), gridRecord(10, true, 1000), gridRecord(20, true, 9000), gridRecord(10, true, 2000),
+				gridRecord(30, false, 100),
+			},
+			want: Stats{
+				Played: 5, Won: 3, CurrentStreak: 0, LongestStreak: 3,
+				Difficulties: []DifficultyStats{
+					{Difficulty: "10x10-10%", Played: 2, Won: 2, BestMS: 1000, AverageMS: 1500},
+					{Difficulty: "20x20-10%", Played: 2, Won: 1, BestMS: 9000, AverageMS: 9000},
+					{Difficulty: "30x30-10%", Played: 1, Won: 0, BestMS: 0, AverageMS: 0},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Summarize(test.records); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Summarize = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWinRate(t *testing.T) {
+	tests := []struct {
+		stats Stats
+		want  int
+	}{
+		{Stats{}, 0},
+		{Stats{Played: 3, Won: 1}, 33},
+		{Stats{Played: 4, Won: 4}, 100},
+	}
+	for _, test := range tests {
+		if got := test.stats.WinRate(); got != test.want {
+			t.Errorf("WinRate of %d/%d = %d, want %d", test.stats.Won, test.stats.Played, got, test.want)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name        string
+		durationsMS []uint64 // of won games
+		lostMS      uint64   // of a lost game, 0 for none
+		count       int
+		want        []int
+		wantMS      uint64
+	}{
+		{
+			name:  "no won games",
+			count: 3,
+			want:  []int{0, 0, 0},
+		},
+		{
+			name:        "longest time in the last bucket",
+			durationsMS: []uint64{0, 999, 1000, 5999, 6000},
+			count:       3,
+			want:        []int{3, 1, 1},
+			wantMS:      3000,
+		},
+		{
+			name:        "longest time a multiple of the buckets",
+			durationsMS: []uint64{3000, 6000},
+			count:       3,
+			want:        []int{0, 1, 1},
+			wantMS:      3000,
+		},
+		{
+			name:        "lost games not counted",
+			durationsMS: []uint64{1500},
+			lostMS:      90000,
+			count:       2,
+			want:        []int{0, 1},
+			wantMS:      1000,
+		},
+		{
+			name:        "no buckets",
+			durationsMS: []uint64{1000},
+			count:       0,
+			want:        []int{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records := []Record{}
+			for _, ms := range test.durationsMS {
+				records = append(records, gridRecord(10, true, ms))
+			}
+			if test.lostMS > 0 {
+				records = append(records, gridRecord(10, false, test.lostMS))
+			}
+			buckets, bucketMS := Histogram(records, test.count)
+			if !reflect.DeepEqual(buckets, test.want) || bucketMS != test.wantMS {
+				t.Errorf("Histogram = %v, %d, want %v, %d", buckets, bucketMS, test.want, test.wantMS)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	ann, bob := testRecord("Ann", true, 1000), testRecord("Bob", false, 2000)
+	big := gridRecord(20, true, 3000)
+	records := []Record{ann, bob, big}
+	tests := []struct {
+		player, difficulty string
+		want               []Record
+	}{
+		{"", "", records},
+		{"Ann", "", []Record{ann, big}},
+		{"", "20x20-10%", []Record{big}},
+		{"Bob", "20x20-10%", nil},
+	}
+	for _, test := range tests {
+		if got := Filter(records, test.player, test.difficulty); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Filter(%q, %q) = %v, want %v", test.player, test.difficulty, got, test.want)
+		}
+	}
+	if got := Players(records); !reflect.DeepEqual(got, []string{"Ann", "Bob"}) {
+		t.Errorf("Players = %v", got)
+	}
+	if got := Difficulties(records); !reflect.DeepEqual(got, []string{"10x10-10%", "20x20-10%"}) {
+		t.Errorf("Difficulties = %v", got)
+	}
+}
+
+func TestGamesPerDay(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(year int, month time.Month, day, hour, min int, location *time.Location) Record {
+		r := testRecord("Ann", true, 1000)
+		r.FinishedAt = time.Date(year, month, day, hour, min, 0, 0, location)
+		return r
+	}
+	tests := []struct {
+		name    string
+		now     time.Time
+		records []Record
+		want    []int
+	}{
+		{
+			name: "days around the spring change (23 hours)",
+			now:  time.Date(2026, 3, 30, 10, 0, 0, 0, paris),
+			records: []Record{
+				at(2026, 3, 27, 12, 0, paris),
+				at(2026, 3, 28, 23, 30, paris),
+				at(2026, 3, 29, 1, 0, paris), at(2026, 3, 29, 23, 0, paris),
+				at(2026, 3, 30, 0, 10, paris),
+			},
+			want: []int{1, 2, 1},
+		},
+		{
+			name: "days around the autumn change (25 hours)",
+			now:  time.Date(2026, 10, 26, 0, 30, 0, 0, paris),
+			records: []Record{
+				at(2026, 10, 24, 23, 59, paris),
+				at(2026, 10, 25, 0, 0, paris), at(2026, 10, 25, 23, 59, paris),
+				at(2026, 10, 26, 0, 0, paris),
+			},
+			want: []int{1, 2, 1},
+		},
+		{
+			name: "records in another time zone",
+			now:  time.Date(2026, 6, 15, 12, 0, 0, 0, paris),
+			records: []Record{
+				// 2026-06-15 00:30 in Paris
+				at(2026, 6, 14, 22, 30, time.UTC),
+				// 2026-06-14 23:30 in Paris
+				at(2026, 6, 14, 21, 30, time.UTC),
+			},
+			want: []int{0, 1, 1},
+		},
+		{
+			name: "days out of the range",
+			now:  time.Date(2026, 6, 15, 12, 0, 0, 0, paris),
+			records: []Record{
+				at(2026, 6, 12, 23, 59, paris),
+				at(2026, 6, 16, 0, 0, paris),
+			},
+			want: []int{0, 0, 0},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := GamesPerDay(test.records, test.now, len(test.want)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("GamesPerDay = %v, want %v", got, test.want)
+			}
+		})
+	}
+}